@@ -0,0 +1,648 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	ttemplate "text/template"
+	"time"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The built-in functions take their subject as the last argument, so that they
+// read naturally at the end of a pipeline, e.g. {{ .name | trimPrefix "x-" | upper }}.
+
+// DefaultMaxRepeat is the maximum length, in bytes, of the strings built by the repeat,
+// indent, and nindent template functions when ParserConfig.MaxOutput is unset.
+const DefaultMaxRepeat = 1 << 24
+
+var (
+	// ErrDivideByZero is returned by the div and mod template functions when the divisor is zero.
+	ErrDivideByZero = errors.New("division by zero")
+
+	// ErrNegativeCount is returned by the repeat, indent, and nindent template functions
+	// when their count is negative.
+	ErrNegativeCount = errors.New("count cannot be negative")
+)
+
+// stringFuncs is the FuncSetStrings function set.  The strings built by repeat, indent,
+// and nindent are bounded by the configured MaxOutput before they are allocated, since
+// the output limit itself only applies once a string is written.
+func stringFuncs(c ParserConfig) map[string]interface{} {
+	r := repeater(c.MaxOutput)
+	if r <= 0 {
+		r = DefaultMaxRepeat
+	}
+
+	return map[string]interface{}{
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+		"title":      title,
+		"trim":       strings.TrimSpace,
+		"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+		"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"replace":    func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+		"contains":   func(substr, s string) bool { return strings.Contains(s, substr) },
+		"hasPrefix":  func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+		"hasSuffix":  func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+		"split":      func(sep, s string) []string { return strings.Split(s, sep) },
+		"join":       join,
+		"repeat":     r.repeat,
+		"trunc":      trunc,
+		"indent":     r.indent,
+		"nindent":    func(n int, s string) (string, error) { s, err := r.indent(n, s); return "\n" + s, err },
+		"quote":      func(v interface{}) string { return fmt.Sprintf("%q", toString(v)) },
+		"toString":   toString,
+	}
+}
+
+// mathFuncs is the FuncSetMath function set.  Results are integers when every
+// argument is an integer, and floating point otherwise.
+var mathFuncs = Funcs{
+	"add":   func(a interface{}, b ...interface{}) (interface{}, error) { return arithmetic(opAdd, a, b...) },
+	"sub":   func(a, b interface{}) (interface{}, error) { return arithmetic(opSub, a, b) },
+	"mul":   func(a interface{}, b ...interface{}) (interface{}, error) { return arithmetic(opMul, a, b...) },
+	"div":   func(a, b interface{}) (interface{}, error) { return arithmetic(opDiv, a, b) },
+	"mod":   func(a, b interface{}) (interface{}, error) { return arithmetic(opMod, a, b) },
+	"max":   func(a interface{}, b ...interface{}) (interface{}, error) { return arithmetic(opMaximum, a, b...) },
+	"min":   func(a interface{}, b ...interface{}) (interface{}, error) { return arithmetic(opMinimum, a, b...) },
+	"floor": func(v interface{}) (float64, error) { f, err := toFloat(v); return math.Floor(f), err },
+	"ceil":  func(v interface{}) (float64, error) { f, err := toFloat(v); return math.Ceil(f), err },
+	"round": func(v interface{}) (float64, error) { f, err := toFloat(v); return math.Round(f), err },
+	"int":   toInt,
+	"float": toFloat,
+}
+
+// collectionFuncs is the FuncSetCollections function set.
+var collectionFuncs = Funcs{
+	"list":      func(v ...interface{}) []interface{} { return v },
+	"dict":      dict,
+	"keys":      keys,
+	"values":    values,
+	"hasKey":    hasKey,
+	"first":     first,
+	"last":      last,
+	"append":    func(v interface{}, l interface{}) ([]interface{}, error) { return appendList(l, v, false) },
+	"prepend":   func(v interface{}, l interface{}) ([]interface{}, error) { return appendList(l, v, true) },
+	"reverse":   reverse,
+	"uniq":      uniq,
+	"has":       has,
+	"sortAlpha": sortAlpha,
+	"empty":     func(v interface{}) bool { t, _ := ttemplate.IsTrue(v); return !t },
+	"default":   func(d, v interface{}) interface{} { t, _ := ttemplate.IsTrue(v); return choose(t, v, d) },
+	"coalesce":  coalesce,
+}
+
+// encodingFuncs is the FuncSetEncoding function set.
+var encodingFuncs = Funcs{
+	"b64enc":       func(v interface{}) string { return base64.StdEncoding.EncodeToString(toBytes(v)) },
+	"b64dec":       b64dec,
+	"toJson":       func(v interface{}) (string, error) { b, err := encodeJSON(v); return string(b), err },
+	"toPrettyJson": toPrettyJSON,
+	"fromJson":     fromJSON,
+	"toYaml":       toYAML,
+	"fromYaml":     fromYAML,
+}
+
+// timeFuncs is the FuncSetTime function set.  Times may be time.Time values, RFC 3339
+// strings, or numbers of seconds since the Unix epoch.  The current time comes from
+// the configured Clock.
+func timeFuncs(c ParserConfig) map[string]interface{} {
+	clk := clock(c)
+	return map[string]interface{}{
+		"now":        clk.Now,
+		"toTime":     toTime,
+		"formatTime": func(layout string, v interface{}) (string, error) { t, err := toTime(v); return t.Format(layout), err },
+		"parseTime":  time.Parse,
+		"unix":       func(v interface{}) (int64, error) { t, err := toTime(v); return t.Unix(), err },
+		"utc":        func(v interface{}) (time.Time, error) { t, err := toTime(v); return t.UTC(), err },
+		"duration":   time.ParseDuration,
+		"addTime":    addTime,
+		"since":      func(v interface{}) (time.Duration, error) { t, err := toTime(v); return clk.Now().Sub(t), err },
+	}
+}
+
+func choose(b bool, t, f interface{}) interface{} {
+	if b {
+		return t
+	}
+
+	return f
+}
+
+// toString formats a value as text/template would print it.
+func toString(v interface{}) string {
+	switch tv := v.(type) {
+	case string:
+		return tv
+
+	case []byte:
+		return string(tv)
+
+	case nil:
+		return ""
+
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func toBytes(v interface{}) []byte {
+	if b, ok := v.([]byte); ok {
+		return b
+	}
+
+	return []byte(toString(v))
+}
+
+func title(s string) string {
+	start := true
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			start = true
+		} else if start {
+			start = false
+			return unicode.ToTitle(r)
+		}
+
+		return r
+	}, s)
+}
+
+func join(sep string, l interface{}) (string, error) {
+	items, err := toList(l)
+	if err != nil {
+		return "", err
+	}
+
+	s := make([]string, len(items))
+	for i, item := range items {
+		s[i] = toString(item)
+	}
+
+	return strings.Join(s, sep), nil
+}
+
+func trunc(n int, s string) string {
+	if r := []rune(s); n >= 0 && n < len(r) {
+		return string(r[:n])
+	}
+
+	return s
+}
+
+// repeater builds repeated strings no longer than its value in bytes.
+type repeater int64
+
+func (r repeater) repeat(n int, s string) (string, error) {
+	switch {
+	case n < 0:
+		return "", ErrNegativeCount
+
+	case len(s) > 0 && int64(n) > int64(r)/int64(len(s)):
+		return "", &OutputLimitError{MaxOutput: int64(r)}
+	}
+
+	return strings.Repeat(s, n), nil
+}
+
+func (r repeater) indent(n int, s string) (string, error) {
+	lines := 1 + strings.Count(s, "\n")
+	switch {
+	case n < 0:
+		return "", ErrNegativeCount
+
+	case n > 0 && int64(n) > (int64(r)-int64(len(s)))/int64(lines):
+		return "", &OutputLimitError{MaxOutput: int64(r)}
+	}
+
+	pad := strings.Repeat(" ", n)
+	return pad + strings.ReplaceAll(s, "\n", "\n"+pad), nil
+}
+
+// number is either an integer or a floating point argument to an arithmetic function.
+type number struct {
+	i       int64
+	f       float64
+	integer bool
+}
+
+func toNumber(v interface{}) (number, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return number{i: rv.Int(), f: float64(rv.Int()), integer: true}, nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return number{i: int64(rv.Uint()), f: float64(rv.Uint()), integer: true}, nil
+
+	case reflect.Float32, reflect.Float64:
+		return number{i: int64(rv.Float()), f: rv.Float()}, nil
+	}
+
+	if n, ok := v.(json.Number); ok {
+		if i, err := n.Int64(); err == nil {
+			return number{i: i, f: float64(i), integer: true}, nil
+		}
+
+		f, err := n.Float64()
+		return number{i: int64(f), f: f}, err
+	}
+
+	return number{}, fmt.Errorf("%v (%T) is not a number", v, v)
+}
+
+func toFloat(v interface{}) (float64, error) {
+	n, err := toNumber(v)
+	return n.f, err
+}
+
+func toInt(v interface{}) (int64, error) {
+	n, err := toNumber(v)
+	return n.i, err
+}
+
+// operator combines two numbers.  Exactly one of the integer or floating point
+// operations is used, depending on the operands.
+type operator struct {
+	i func(a, b int64) (int64, error)
+	f func(a, b float64) (float64, error)
+}
+
+var (
+	opAdd = operator{
+		i: func(a, b int64) (int64, error) { return a + b, nil },
+		f: func(a, b float64) (float64, error) { return a + b, nil },
+	}
+
+	opSub = operator{
+		i: func(a, b int64) (int64, error) { return a - b, nil },
+		f: func(a, b float64) (float64, error) { return a - b, nil },
+	}
+
+	opMul = operator{
+		i: func(a, b int64) (int64, error) { return a * b, nil },
+		f: func(a, b float64) (float64, error) { return a * b, nil },
+	}
+
+	opDiv = operator{
+		i: func(a, b int64) (int64, error) {
+			if b == 0 {
+				return 0, ErrDivideByZero
+			}
+
+			return a / b, nil
+		},
+		f: func(a, b float64) (float64, error) {
+			if b == 0 {
+				return 0, ErrDivideByZero
+			}
+
+			return a / b, nil
+		},
+	}
+
+	opMod = operator{
+		i: func(a, b int64) (int64, error) {
+			if b == 0 {
+				return 0, ErrDivideByZero
+			}
+
+			return a % b, nil
+		},
+		f: func(a, b float64) (float64, error) {
+			if b == 0 {
+				return 0, ErrDivideByZero
+			}
+
+			return math.Mod(a, b), nil
+		},
+	}
+
+	opMaximum = operator{
+		i: func(a, b int64) (int64, error) { return max(a, b), nil },
+		f: func(a, b float64) (float64, error) { return math.Max(a, b), nil },
+	}
+
+	opMinimum = operator{
+		i: func(a, b int64) (int64, error) { return min(a, b), nil },
+		f: func(a, b float64) (float64, error) { return math.Min(a, b), nil },
+	}
+)
+
+// arithmetic folds an operator over its operands.
+func arithmetic(op operator, first interface{}, rest ...interface{}) (interface{}, error) {
+	operands := make([]number, 0, 1+len(rest))
+	integer := true
+	for _, v := range append([]interface{}{first}, rest...) {
+		n, err := toNumber(v)
+		if err != nil {
+			return nil, err
+		}
+
+		integer = integer && n.integer
+		operands = append(operands, n)
+	}
+
+	var err error
+	if integer {
+		result := operands[0].i
+		for i := 1; err == nil && i < len(operands); i++ {
+			result, err = op.i(result, operands[i].i)
+		}
+
+		return result, err
+	}
+
+	result := operands[0].f
+	for i := 1; err == nil && i < len(operands); i++ {
+		result, err = op.f(result, operands[i].f)
+	}
+
+	return result, err
+}
+
+// toList converts any slice or array into a []interface{}.  A nil value is an empty list.
+func toList(l interface{}) ([]interface{}, error) {
+	if l == nil {
+		return nil, nil
+	} else if items, ok := l.([]interface{}); ok {
+		return items, nil
+	}
+
+	rv := reflect.ValueOf(l)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("%T is not a list", l)
+	}
+
+	items := make([]interface{}, rv.Len())
+	for i := range items {
+		items[i] = rv.Index(i).Interface()
+	}
+
+	return items, nil
+}
+
+func dict(kv ...interface{}) (map[string]interface{}, error) {
+	if len(kv)%2 != 0 {
+		return nil, errors.New("dict requires an even number of arguments")
+	}
+
+	d := make(map[string]interface{}, len(kv)/2)
+	for i := 0; i < len(kv); i += 2 {
+		d[toString(kv[i])] = kv[i+1]
+	}
+
+	return d, nil
+}
+
+// mapValue returns the reflected map for a template function's argument.
+func mapValue(m interface{}) (reflect.Value, error) {
+	rv := reflect.ValueOf(m)
+	if rv.Kind() != reflect.Map {
+		return reflect.Value{}, fmt.Errorf("%T is not a map", m)
+	}
+
+	return rv, nil
+}
+
+// sortedKeys returns the keys of a map, sorted by their string forms.
+func sortedKeys(rv reflect.Value) []reflect.Value {
+	k := rv.MapKeys()
+	sort.Slice(k, func(i, j int) bool {
+		return fmt.Sprint(k[i].Interface()) < fmt.Sprint(k[j].Interface())
+	})
+
+	return k
+}
+
+func keys(m interface{}) ([]interface{}, error) {
+	rv, err := mapValue(m)
+	if err != nil {
+		return nil, err
+	}
+
+	var k []interface{}
+	for _, key := range sortedKeys(rv) {
+		k = append(k, key.Interface())
+	}
+
+	return k, nil
+}
+
+func values(m interface{}) ([]interface{}, error) {
+	rv, err := mapValue(m)
+	if err != nil {
+		return nil, err
+	}
+
+	var v []interface{}
+	for _, key := range sortedKeys(rv) {
+		v = append(v, rv.MapIndex(key).Interface())
+	}
+
+	return v, nil
+}
+
+func hasKey(key string, m interface{}) (bool, error) {
+	rv, err := mapValue(m)
+	if err != nil {
+		return false, err
+	} else if rv.Type().Key().Kind() != reflect.String {
+		return false, fmt.Errorf("%T does not have string keys", m)
+	}
+
+	return rv.MapIndex(reflect.ValueOf(key).Convert(rv.Type().Key())).IsValid(), nil
+}
+
+func first(l interface{}) (interface{}, error) {
+	items, err := toList(l)
+	if err != nil || len(items) == 0 {
+		return nil, err
+	}
+
+	return items[0], nil
+}
+
+func last(l interface{}) (interface{}, error) {
+	items, err := toList(l)
+	if err != nil || len(items) == 0 {
+		return nil, err
+	}
+
+	return items[len(items)-1], nil
+}
+
+// appendList returns a new list with a value added to the end or, when front
+// is set, the beginning.  The original list is not modified.
+func appendList(l, v interface{}, front bool) ([]interface{}, error) {
+	items, err := toList(l)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]interface{}, 0, len(items)+1)
+	if front {
+		result = append(result, v)
+	}
+
+	result = append(result, items...)
+	if !front {
+		result = append(result, v)
+	}
+
+	return result, nil
+}
+
+func reverse(l interface{}) ([]interface{}, error) {
+	items, err := toList(l)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]interface{}, len(items))
+	for i, item := range items {
+		result[len(items)-1-i] = item
+	}
+
+	return result, nil
+}
+
+func uniq(l interface{}) ([]interface{}, error) {
+	items, err := toList(l)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []interface{}
+	for _, item := range items {
+		if !containsValue(result, item) {
+			result = append(result, item)
+		}
+	}
+
+	return result, nil
+}
+
+func containsValue(items []interface{}, v interface{}) bool {
+	for _, item := range items {
+		if reflect.DeepEqual(item, v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func has(v, l interface{}) (bool, error) {
+	items, err := toList(l)
+	return containsValue(items, v), err
+}
+
+func sortAlpha(l interface{}) ([]string, error) {
+	items, err := toList(l)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, len(items))
+	for i, item := range items {
+		result[i] = toString(item)
+	}
+
+	sort.Strings(result)
+	return result, nil
+}
+
+func coalesce(v ...interface{}) interface{} {
+	for _, item := range v {
+		if t, _ := ttemplate.IsTrue(item); t {
+			return item
+		}
+	}
+
+	return nil
+}
+
+func b64dec(s string) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	return string(b), err
+}
+
+func toPrettyJSON(v interface{}) (string, error) {
+	b, err := json.MarshalIndent(v, "", "  ")
+	return string(b), err
+}
+
+func fromJSON(s string) (v interface{}, err error) {
+	err = json.Unmarshal([]byte(s), &v)
+	return
+}
+
+// toYAML marshals a value as a YAML document, without the trailing newline.
+func toYAML(v interface{}) (string, error) {
+	b, err := yaml.Marshal(v)
+	return strings.TrimSuffix(string(b), "\n"), err
+}
+
+func fromYAML(s string) (v interface{}, err error) {
+	err = yaml.Unmarshal([]byte(s), &v)
+	return
+}
+
+// toTime converts a time.Time, an RFC 3339 string, or a number of seconds
+// since the Unix epoch into a time.Time.  Numbers are converted to UTC, so that
+// formatting them does not depend on the local time zone.
+func toTime(v interface{}) (time.Time, error) {
+	switch tv := v.(type) {
+	case time.Time:
+		return tv, nil
+
+	case *time.Time:
+		return *tv, nil
+
+	case string:
+		return time.Parse(time.RFC3339Nano, tv)
+	}
+
+	n, err := toNumber(v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%v (%T) is not a time", v, v)
+	} else if n.integer {
+		return time.Unix(n.i, 0).UTC(), nil
+	}
+
+	sec, frac := math.Modf(n.f)
+	return time.Unix(int64(sec), int64(frac*1e9)).UTC(), nil
+}
+
+// addTime adds a duration, either a time.Duration or a string such as "1h30m", to a time.
+func addTime(d, v interface{}) (time.Time, error) {
+	t, err := toTime(v)
+	if err != nil {
+		return t, err
+	}
+
+	switch dv := d.(type) {
+	case time.Duration:
+		return t.Add(dv), nil
+
+	case string:
+		var parsed time.Duration
+		parsed, err = time.ParseDuration(dv)
+		return t.Add(parsed), err
+
+	default:
+		return t, fmt.Errorf("%v (%T) is not a duration", d, d)
+	}
+}
@@ -0,0 +1,202 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuiltins(t *testing.T) {
+	testCases := []struct {
+		name     string
+		template string
+		data     Model
+		expected string
+		err      string
+	}{
+		// strings
+		{name: "Upper", template: `{{ "abc" | upper }}`, expected: "ABC"},
+		{name: "Lower", template: `{{ "ABC" | lower }}`, expected: "abc"},
+		{name: "Title", template: `{{ "hello big-world" | title }}`, expected: "Hello Big-world"},
+		{name: "Trim", template: `{{ "  x  " | trim }}`, expected: "x"},
+		{name: "TrimPrefix", template: `{{ "x-name" | trimPrefix "x-" }}`, expected: "name"},
+		{name: "TrimSuffix", template: `{{ "name.txt" | trimSuffix ".txt" }}`, expected: "name"},
+		{name: "Replace", template: `{{ "a.b.c" | replace "." "/" }}`, expected: "a/b/c"},
+		{name: "Contains", template: `{{ "haystack" | contains "st" }}`, expected: "true"},
+		{name: "HasPrefix", template: `{{ "haystack" | hasPrefix "hay" }}`, expected: "true"},
+		{name: "HasSuffix", template: `{{ "haystack" | hasSuffix "hay" }}`, expected: "false"},
+		{name: "Split", template: `{{ "a,b" | split "," }}`, expected: "[a b]"},
+		{name: "Join", template: `{{ .list | join "-" }}`, data: Model{"list": []interface{}{"a", 1, true}}, expected: "a-1-true"},
+		{name: "JoinNotList", template: `{{ .n | join "-" }}`, data: Model{"n": 1}, err: "int is not a list"},
+		{name: "Repeat", template: `{{ "ab" | repeat 3 }}`, expected: "ababab"},
+		{name: "RepeatNegative", template: `{{ "ab" | repeat -1 }}`, err: ErrNegativeCount.Error()},
+		{name: "RepeatTooLong", template: `{{ repeat .n "x" }}`, data: Model{"n": 10000000000}, err: "output exceeds the maximum of 16777216 bytes"},
+		{name: "IndentNegative", template: `{{ "a" | indent -1 }}`, err: ErrNegativeCount.Error()},
+		{name: "IndentTooLong", template: `{{ "a\nb" | nindent .n }}`, data: Model{"n": 10000000000}, err: "output exceeds the maximum of 16777216 bytes"},
+		{name: "Trunc", template: `{{ "abcdef" | trunc 3 }}|{{ "ab" | trunc 3 }}`, expected: "abc|ab"},
+		{name: "Indent", template: "{{ \"a\\nb\" | indent 2 }}", expected: "  a\n  b"},
+		{name: "Nindent", template: "{{ \"a\" | nindent 2 }}", expected: "\n  a"},
+		{name: "Quote", template: `{{ .v | quote }}`, data: Model{"v": `say "hi"`}, expected: `"say \"hi\""`},
+		{name: "ToString", template: `{{ toString .n }}|{{ toString .b }}|{{ toString .z }}`, data: Model{"n": 1.5, "b": []byte("x"), "z": nil}, expected: "1.5|x|"},
+
+		// math
+		{name: "AddIntegers", template: `{{ add 1 2 3 }}`, expected: "6"},
+		{name: "AddFloats", template: `{{ add 1 2.5 }}`, expected: "3.5"},
+		{name: "AddJSONNumbers", template: `{{ add .a .b }}`, data: Model{"a": json.Number("2"), "b": json.Number("0.5")}, expected: "2.5"},
+		{name: "Sub", template: `{{ sub 5 7 }}`, expected: "-2"},
+		{name: "Mul", template: `{{ mul 2 3 4 }}`, expected: "24"},
+		{name: "DivIntegers", template: `{{ div 7 2 }}`, expected: "3"},
+		{name: "DivFloats", template: `{{ div 7.0 2 }}`, expected: "3.5"},
+		{name: "DivByZero", template: `{{ div 1 0 }}`, err: ErrDivideByZero.Error()},
+		{name: "Mod", template: `{{ mod 7 3 }}|{{ mod 7.5 2 }}`, expected: "1|1.5"},
+		{name: "ModByZero", template: `{{ mod 1 0.0 }}`, err: ErrDivideByZero.Error()},
+		{name: "Max", template: `{{ max 1 5 3 }}`, expected: "5"},
+		{name: "Min", template: `{{ min 1 0.5 }}`, expected: "0.5"},
+		{name: "Floor", template: `{{ floor 1.5 }}|{{ ceil 1.5 }}|{{ round 1.5 }}`, expected: "1|2|2"},
+		{name: "Int", template: `{{ int 2.9 }}|{{ float 2 }}`, expected: "2|2"},
+		{name: "NotANumber", template: `{{ add 1 "x" }}`, err: "x (string) is not a number"},
+
+		// collections
+		{name: "List", template: `{{ list 1 "a" }}`, expected: "[1 a]"},
+		{name: "Dict", template: `{{ $d := dict "a" 1 "b" 2 }}{{ $d.b }}`, expected: "2"},
+		{name: "DictOdd", template: `{{ dict "a" }}`, err: "dict requires an even number of arguments"},
+		{name: "Keys", template: `{{ keys .m }}|{{ values .m }}`, data: Model{"m": map[string]interface{}{"b": 2, "a": 1}}, expected: "[a b]|[1 2]"},
+		{name: "KeysNotMap", template: `{{ keys .l }}`, data: Model{"l": []int{1}}, err: "[]int is not a map"},
+		{name: "HasKey", template: `{{ hasKey "a" .m }}|{{ hasKey "z" .m }}`, data: Model{"m": map[string]interface{}{"a": nil}}, expected: "true|false"},
+		{name: "HasKeyNotStrings", template: `{{ hasKey "a" .m }}`, data: Model{"m": map[int]int{}}, err: "map[int]int does not have string keys"},
+		{name: "FirstLast", template: `{{ first .l }}|{{ last .l }}|{{ first .e }}`, data: Model{"l": []int{1, 2, 3}, "e": []int{}}, expected: "1|3|<no value>"},
+		{name: "Append", template: `{{ append 3 .l }}|{{ prepend 0 .l }}|{{ .l }}`, data: Model{"l": []int{1, 2}}, expected: "[1 2 3]|[0 1 2]|[1 2]"},
+		{name: "Reverse", template: `{{ reverse .l }}`, data: Model{"l": []string{"a", "b", "c"}}, expected: "[c b a]"},
+		{name: "Uniq", template: `{{ uniq .l }}`, data: Model{"l": []interface{}{1, "1", 1, 2}}, expected: "[1 1 2]"},
+		{name: "Has", template: `{{ has 2 .l }}|{{ has 4 .l }}`, data: Model{"l": []int{1, 2}}, expected: "true|false"},
+		{name: "SortAlpha", template: `{{ sortAlpha .l }}`, data: Model{"l": []interface{}{"b", 10, "a"}}, expected: "[10 a b]"},
+		{name: "Empty", template: `{{ empty "" }}|{{ empty .l }}|{{ empty 1 }}`, data: Model{"l": []int{}}, expected: "true|true|false"},
+		{name: "Default", template: `{{ "" | default "d" }}|{{ "v" | default "d" }}`, expected: "d|v"},
+		{name: "Coalesce", template: `{{ coalesce "" 0 "x" "y" }}|{{ coalesce "" }}`, expected: "x|<no value>"},
+
+		// encoding
+		{name: "Base64", template: `{{ "hi" | b64enc }}|{{ "aGk=" | b64dec }}`, expected: "aGk=|hi"},
+		{name: "Base64Invalid", template: `{{ "!" | b64dec }}`, err: "illegal base64 data"},
+		{name: "ToJson", template: `{{ toJson .m }}`, data: Model{"m": map[string]interface{}{"a": "<b>"}}, expected: `{"a":"<b>"}`},
+		{name: "ToPrettyJson", template: `{{ toPrettyJson .m }}`, data: Model{"m": map[string]interface{}{"a": 1}}, expected: "{\n  \"a\": 1\n}"},
+		{name: "FromJson", template: `{{ $v := fromJson "{\"a\": [1]}" }}{{ index $v.a 0 }}`, expected: "1"},
+		{name: "FromJsonInvalid", template: `{{ fromJson "{" }}`, err: "unexpected end of JSON input"},
+		{name: "ToYaml", template: `{{ toYaml .m }}`, data: Model{"m": map[string]interface{}{"a": []int{1}}}, expected: "a:\n    - 1"},
+		{name: "FromYaml", template: `{{ $v := fromYaml "a: b" }}{{ $v.a }}`, expected: "b"},
+
+		// time
+		{name: "ToTimeString", template: `{{ toTime "2024-01-02T03:04:05-05:00" | utc }}`, expected: "2024-01-02 08:04:05 +0000 UTC"},
+		{name: "ToTimeInteger", template: `{{ toTime 86400 }}`, expected: "1970-01-02 00:00:00 +0000 UTC"},
+		{name: "ToTimeFloat", template: `{{ (toTime 1.5).Nanosecond }}`, expected: "500000000"},
+		{name: "ToTimeInvalid", template: `{{ toTime true }}`, err: "true (bool) is not a time"},
+		{name: "FormatTime", template: `{{ formatTime "2006-01-02" 0 }}`, expected: "1970-01-01"},
+		{name: "ParseTime", template: `{{ (parseTime "2006-01-02" "2024-03-04").YearDay }}`, expected: "64"},
+		{name: "Unix", template: `{{ unix "1970-01-01T00:01:00Z" }}`, expected: "60"},
+		{name: "Duration", template: `{{ duration "1h30m" }}`, expected: "1h30m0s"},
+		{name: "AddTime", template: `{{ addTime "1h" 0 | unix }}|{{ addTime (duration "1m") 0 | unix }}`, expected: "3600|60"},
+		{name: "AddTimeInvalid", template: `{{ addTime 1 0 }}`, err: "1 (int) is not a duration"},
+		{name: "TimeValue", template: `{{ unix .t }}`, data: Model{"t": time.Unix(5, 0)}, expected: "5"},
+	}
+
+	p, err := NewParser(ParserConfig{
+		Functions: []string{FuncSetStrings, FuncSetMath, FuncSetCollections, FuncSetEncoding, FuncSetTime},
+	})
+
+	if err != nil {
+		t.Fatalf("unable to create parser: %s", err)
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			tmpl, err := p.Parse("t", testCase.template)
+			if err != nil {
+				t.Fatalf("unable to parse template: %s", err)
+			}
+
+			var o strings.Builder
+			err = tmpl.Execute(&o, testCase.data)
+			switch {
+			case len(testCase.err) > 0:
+				if err == nil || !strings.Contains(err.Error(), testCase.err) {
+					t.Errorf("expected an error containing %q, got %v", testCase.err, err)
+				}
+
+			case err != nil || o.String() != testCase.expected:
+				t.Errorf("expected %q, got %q (%v)", testCase.expected, o.String(), err)
+			}
+		})
+	}
+}
+
+func TestRepeatMaxOutput(t *testing.T) {
+	testCases := []struct {
+		name     string
+		template string
+		expected string
+		err      bool
+	}{
+		{name: "Repeat", template: `{{ repeat 5 "ab" }}`, expected: "ababababab"},
+		{name: "RepeatTooLong", template: `{{ repeat 6 "ab" }}`, err: true},
+		{name: "Indent", template: "{{ \"a\\nb\" | indent 3 }}", expected: "   a\n   b"},
+		{name: "IndentTooLong", template: "{{ \"a\\nb\" | indent 4 }}", err: true},
+	}
+
+	p, err := NewParser(ParserConfig{Functions: []string{FuncSetStrings}, MaxOutput: 10})
+	if err != nil {
+		t.Fatalf("unable to create parser: %s", err)
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			tmpl, err := p.Parse("t", testCase.template)
+			if err != nil {
+				t.Fatalf("unable to parse template: %s", err)
+			}
+
+			var o strings.Builder
+			err = ExecuteWith(context.Background(), tmpl, &o, nil)
+			var ole *OutputLimitError
+			switch {
+			case testCase.err:
+				if !errors.As(err, &ole) || ole.MaxOutput != 10 {
+					t.Errorf("expected an OutputLimitError, got %v", err)
+				}
+
+			case err != nil || o.String() != testCase.expected:
+				t.Errorf("expected %q, got %q (%v)", testCase.expected, o.String(), err)
+			}
+		})
+	}
+}
+
+func TestToTime(t *testing.T) {
+	local := time.FixedZone("local", 3600)
+	testCases := []struct {
+		value    interface{}
+		expected time.Time
+		location *time.Location
+	}{
+		{value: int64(1), expected: time.Unix(1, 0), location: time.UTC},
+		{value: 1.25, expected: time.Unix(1, 250000000), location: time.UTC},
+		{value: json.Number("2"), expected: time.Unix(2, 0), location: time.UTC},
+		{value: time.Unix(3, 0).In(local), expected: time.Unix(3, 0), location: local},
+		{value: "1970-01-01T01:00:04+01:00", expected: time.Unix(4, 0)},
+	}
+
+	for _, testCase := range testCases {
+		actual, err := toTime(testCase.value)
+		switch {
+		case err != nil || !actual.Equal(testCase.expected):
+			t.Errorf("%v: expected %s, got %s (%v)", testCase.value, testCase.expected, actual, err)
+
+		case testCase.location != nil && actual.Location() != testCase.location:
+			t.Errorf("%v: expected the location %s, got %s", testCase.value, testCase.location, actual.Location())
+		}
+	}
+}
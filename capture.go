@@ -0,0 +1,204 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"errors"
+	"fmt"
+	htemplate "html/template"
+	"io"
+	"strings"
+	"sync"
+	ttemplate "text/template"
+)
+
+const (
+	// IncludeFunc is the name of the function that golang templates use to render an
+	// associated template into a string, e.g. {{ include "item" . | trim }}.  Unlike the
+	// template action, the output of include can be used in a pipeline.  A FuncMap
+	// function with this name takes precedence over the built-in function.
+	IncludeFunc = "include"
+
+	// DefaultMaxIncludeDepth is the maximum number of nested include calls.
+	DefaultMaxIncludeDepth = 32
+)
+
+// ErrIncludeUnbound is returned by the include function of a prototype template,
+// which is never executed directly.
+var ErrIncludeUnbound = errors.New("include can only be called from a parsed template")
+
+// IncludeDepthError indicates that include calls were nested too deeply, which
+// usually means that a template includes itself.
+type IncludeDepthError struct {
+	// Name is the template that could not be included.
+	Name string
+
+	// MaxDepth is the maximum number of nested include calls.
+	MaxDepth int
+}
+
+// Error satisfies the error interface.
+func (ide *IncludeDepthError) Error() string {
+	return fmt.Sprintf("cannot include %q: exceeded the maximum include depth of %d", ide.Name, ide.MaxDepth)
+}
+
+// unboundInclude is installed in prototypes so that templates which call include
+// can be parsed.  Each parsed template set rebinds include to itself.
+func unboundInclude(string, interface{}) (string, error) {
+	return "", ErrIncludeUnbound
+}
+
+// bindsInclude tests whether golang templates from a configuration get the built-in
+// include function, which is the case unless the FuncMap has its own include.
+func bindsInclude(c ParserConfig) bool {
+	_, overridden := c.FuncMap[IncludeFunc]
+	return !overridden
+}
+
+// includeSet is a template set that the include function renders from.
+type includeSet interface {
+	ExecuteTemplate(w io.Writer, name string, data interface{}) error
+}
+
+// includeLevels produces the sets that nested include calls render with.  Each
+// nesting level uses its own clone of the set, whose include function renders
+// with the next level.  This limits the nesting depth without any state that would
+// be shared by concurrent executions.  Clones are made lazily from a pristine copy,
+// since html/template cannot clone a set that has been executed.
+type includeLevels struct {
+	maxDepth int
+
+	// limit wraps the writer that each included template renders to
+	limit func(io.Writer) io.Writer
+
+	// clone creates a copy of the pristine set for a nesting depth, with
+	// the include function for that depth installed
+	clone func(depth int) (includeSet, error)
+
+	lock   sync.Mutex
+	levels []includeSet
+}
+
+// level returns the set for a nesting depth, creating it and any shallower
+// sets as necessary.  The depth must be at least 1.
+func (il *includeLevels) level(depth int) (includeSet, error) {
+	il.lock.Lock()
+	defer il.lock.Unlock()
+	for len(il.levels) < depth {
+		set, err := il.clone(len(il.levels) + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		il.levels = append(il.levels, set)
+	}
+
+	return il.levels[depth-1], nil
+}
+
+// render is the include function for a nesting depth.  It executes a named
+// template from the set for the next depth.
+func (il *includeLevels) render(depth int, name string, data interface{}) (string, error) {
+	if depth >= il.maxDepth {
+		return "", &IncludeDepthError{Name: name, MaxDepth: il.maxDepth}
+	}
+
+	set, err := il.level(depth + 1)
+	if err != nil {
+		return "", err
+	}
+
+	var o strings.Builder
+	err = set.ExecuteTemplate(il.limit(&o), name, data)
+
+	// report a runaway include once, rather than once for every level
+	var ide *IncludeDepthError
+	if errors.As(err, &ide) {
+		err = ide
+	}
+
+	return o.String(), err
+}
+
+// copyTrees clones a text/template set along with its parse trees, so that
+// the trees of the copy can be rewritten without affecting the original.
+func copyTrees(t *ttemplate.Template) (*ttemplate.Template, error) {
+	return withTrees(t, t.Templates())
+}
+
+// withTrees clones a text/template set, adding copies of the parse trees
+// of the given templates to the clone.
+func withTrees(t *ttemplate.Template, ts []*ttemplate.Template) (*ttemplate.Template, error) {
+	c, err := t.Clone()
+	for i := 0; err == nil && i < len(ts); i++ {
+		if ts[i].Tree != nil {
+			_, err = c.AddParseTree(ts[i].Name(), ts[i].Tree.Copy())
+		}
+	}
+
+	return c, err
+}
+
+// newIncludeLevels creates the includeLevels for the executions with the given state.
+func newIncludeLevels(state *execState, l limits) *includeLevels {
+	return &includeLevels{
+		maxDepth: l.maxIncludeDepth,
+		limit: func(w io.Writer) io.Writer {
+			return l.writer(state.ctx, w)
+		},
+	}
+}
+
+// bindTextInclude installs the include function into a text/template set.  Nested
+// include calls render with clones of pristine, which must never be executed, and
+// funcs are installed into each clone.  This must be done before the set is executed.
+func bindTextInclude(set, pristine *ttemplate.Template, funcs map[string]interface{}, il *includeLevels) {
+	include := func(depth int) ttemplate.FuncMap {
+		return ttemplate.FuncMap{
+			IncludeFunc: func(name string, data interface{}) (string, error) {
+				return il.render(depth, name, data)
+			},
+		}
+	}
+
+	il.clone = func(depth int) (includeSet, error) {
+		c, err := pristine.Clone()
+		if err == nil {
+			c.Funcs(funcs)
+			c.Funcs(include(depth))
+		}
+
+		return c, err
+	}
+
+	set.Funcs(include(0))
+}
+
+// bindHTMLInclude installs the include function into an html/template set.  Nested
+// include calls render with clones of pristine, which must never be executed, and
+// funcs are installed into each clone.  This must be done before the set is executed.
+// The output of an html/template is already escaped, so include returns it as
+// htemplate.HTML.
+func bindHTMLInclude(set, pristine *htemplate.Template, funcs map[string]interface{}, il *includeLevels) {
+	include := func(depth int) htemplate.FuncMap {
+		return htemplate.FuncMap{
+			IncludeFunc: func(name string, data interface{}) (htemplate.HTML, error) {
+				o, err := il.render(depth, name, data)
+				return htemplate.HTML(o), err
+			},
+		}
+	}
+
+	il.clone = func(depth int) (includeSet, error) {
+		c, err := pristine.Clone()
+		if err == nil {
+			c.Funcs(funcs)
+			c.Funcs(include(depth))
+		}
+
+		return c, err
+	}
+
+	set.Funcs(include(0))
+}
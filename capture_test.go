@@ -0,0 +1,203 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	ttemplate "text/template"
+)
+
+func TestInclude(t *testing.T) {
+	testCases := []struct {
+		name     string
+		config   ParserConfig
+		template string
+		expected string
+	}{
+		{
+			name:     "Pipeline",
+			template: `{{define "item"}}  {{.}}  {{end}}[{{include "item" .x | printf "%s" | len}}]`,
+			expected: "[7]",
+		},
+		{
+			name:     "Nested",
+			template: `{{define "inner"}}<{{.}}>{{end}}{{define "outer"}}({{include "inner" .}}){{end}}{{include "outer" .x}}`,
+			expected: "(<a&b>)",
+		},
+		{
+			name:     "FromIncludes",
+			config:   ParserConfig{FS: testIncludes, Includes: []string{"includes/*.inc"}},
+			template: `{{include "shout" .x | printf "%q"}}`,
+			expected: `"hello, a&b!"`,
+		},
+		{
+			name:     "HTML",
+			config:   ParserConfig{HTML: true},
+			template: `{{define "item"}}<b>{{.}}</b>{{end}}<p>{{include "item" .x}}</p>`,
+			expected: "<p><b>a&amp;b</b></p>",
+		},
+		{
+			name:     "JSON",
+			config:   ParserConfig{JSON: true},
+			template: `{{define "item"}}"{{.}}"{{end}}{"raw": {{include "item" .x}}, "text": "{{include "item" .x}}"}`,
+			expected: `{"raw": "\"a&b\"", "text": "\"a&b\""}`,
+		},
+		{
+			name:     "Overridden",
+			config:   ParserConfig{FuncMap: map[string]interface{}{IncludeFunc: func(name string, _ interface{}) string { return "custom " + name }}},
+			template: `{{include "item" .}}`,
+			expected: "custom item",
+		},
+		{
+			name:     "Recursion",
+			template: `{{define "count"}}{{if .}}{{len .}}{{include "count" (slice . 1)}}{{end}}{{end}}{{include "count" "abc"}}`,
+			expected: "321",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			p, err := NewParser(testCase.config)
+			if err != nil {
+				t.Fatalf("unable to create parser: %s", err)
+			}
+
+			tmpl, err := p.Parse("t", testCase.template)
+			if err != nil {
+				t.Fatalf("unable to parse template: %s", err)
+			}
+
+			// the include functions are bound once, so later executions see the same result
+			for i := 0; i < 2; i++ {
+				var o strings.Builder
+				if err := tmpl.Execute(&o, Model{"x": "a&b"}); err != nil || o.String() != testCase.expected {
+					t.Errorf("expected %q, got %q (%v)", testCase.expected, o.String(), err)
+				}
+			}
+		})
+	}
+}
+
+func TestIncludeErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		config   ParserConfig
+		template string
+		depth    int
+		err      string
+	}{
+		{
+			name:     "SelfInclude",
+			template: `{{define "loop"}}{{include "loop" .}}{{end}}{{include "loop" .}}`,
+			depth:    DefaultMaxIncludeDepth,
+		},
+		{
+			name:     "MaxIncludeDepth",
+			config:   ParserConfig{MaxIncludeDepth: 3},
+			template: `{{define "loop"}}{{include "loop" .}}{{end}}{{include "loop" .}}`,
+			depth:    3,
+		},
+		{
+			name:     "HTMLSelfInclude",
+			config:   ParserConfig{HTML: true, MaxIncludeDepth: 3},
+			template: `{{define "loop"}}x{{include "loop" .}}{{end}}{{include "loop" .}}`,
+			depth:    3,
+		},
+		{
+			name:     "JSONSelfInclude",
+			config:   ParserConfig{JSON: true, MaxIncludeDepth: 3},
+			template: `{{define "loop"}}{{include "loop" .}}{{end}}[{{include "loop" .}}]`,
+			depth:    3,
+		},
+		{
+			name:     "MissingTemplate",
+			template: `{{include "nosuch" .}}`,
+			err:      `no template "nosuch"`,
+		},
+		{
+			name:     "IncludedError",
+			template: `{{define "item"}}{{.missing}}{{end}}{{include "item" .}}`,
+			err:      "map has no entry for key",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			p, err := NewParser(testCase.config)
+			if err != nil {
+				t.Fatalf("unable to create parser: %s", err)
+			}
+
+			tmpl, err := p.Parse("t", testCase.template)
+			if err != nil {
+				t.Fatalf("unable to parse template: %s", err)
+			}
+
+			var o strings.Builder
+			err = tmpl.Execute(&o, Model{})
+			if testCase.depth == 0 {
+				if err == nil || !strings.Contains(err.Error(), testCase.err) {
+					t.Errorf("expected an error containing %q, got %v", testCase.err, err)
+				}
+
+				return
+			}
+
+			var ide *IncludeDepthError
+			switch {
+			case !errors.As(err, &ide) || ide.Name != "loop" || ide.MaxDepth != testCase.depth:
+				t.Errorf("expected an IncludeDepthError with depth %d, got %v", testCase.depth, err)
+
+			case strings.Count(err.Error(), "cannot include") != 1:
+				t.Errorf("a runaway include should be reported once: %s", err)
+			}
+		})
+	}
+}
+
+func TestUnboundInclude(t *testing.T) {
+	if _, err := unboundInclude("t", nil); !errors.Is(err, ErrIncludeUnbound) {
+		t.Errorf("expected ErrIncludeUnbound, got %v", err)
+	}
+
+	testCases := []struct {
+		config   ParserConfig
+		expected bool
+	}{
+		{config: ParserConfig{}, expected: true},
+		{config: ParserConfig{FuncMap: map[string]interface{}{"other": strings.ToUpper}}, expected: true},
+		{config: ParserConfig{FuncMap: map[string]interface{}{IncludeFunc: strings.ToUpper}}, expected: false},
+	}
+
+	for _, testCase := range testCases {
+		if actual := bindsInclude(testCase.config); actual != testCase.expected {
+			t.Errorf("%v: expected %t, got %t", testCase.config.FuncMap, testCase.expected, actual)
+		}
+	}
+}
+
+func TestCopyTrees(t *testing.T) {
+	original := ttemplate.Must(ttemplate.New("t").Parse(`{{define "a"}}a{{end}}{{template "a"}}`))
+	c, err := copyTrees(original)
+	if err != nil {
+		t.Fatalf("unable to copy trees: %s", err)
+	}
+
+	if len(c.Templates()) != len(original.Templates()) {
+		t.Errorf("expected %d templates, got %d", len(original.Templates()), len(c.Templates()))
+	}
+
+	for _, ct := range c.Templates() {
+		if ot := original.Lookup(ct.Name()); ot == nil || ot.Tree == ct.Tree {
+			t.Errorf("%s: the copy should have its own parse tree", ct.Name())
+		}
+	}
+
+	var o strings.Builder
+	if err := c.Execute(&o, nil); err != nil || o.String() != "a" {
+		t.Errorf("expected a, got %q (%v)", o.String(), err)
+	}
+}
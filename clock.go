@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	mrand "math/rand/v2"
+	"sync"
+	"time"
+)
+
+// Clock supplies the current time to template functions.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+}
+
+// ClockFunc is a function type that implements Clock.
+type ClockFunc func() time.Time
+
+// Now invokes this function.
+func (cf ClockFunc) Now() time.Time {
+	return cf()
+}
+
+// SystemClock is the Clock that reports the system time.  It is used when
+// a ParserConfig has no Clock.
+var SystemClock Clock = ClockFunc(time.Now)
+
+// FixedClock returns a Clock that always reports the given time.  A fixed
+// clock makes templates that use the current time render reproducibly.
+func FixedClock(t time.Time) Clock {
+	return ClockFunc(func() time.Time { return t })
+}
+
+// SystemEntropy is the cryptographically secure source of random bytes used
+// when a ParserConfig has no Entropy.
+var SystemEntropy io.Reader = rand.Reader
+
+// lockedReader serializes reads from a reader that is not safe for concurrent use.
+type lockedReader struct {
+	lock sync.Mutex
+	r    io.Reader
+}
+
+func (lr *lockedReader) Read(p []byte) (int, error) {
+	lr.lock.Lock()
+	defer lr.lock.Unlock()
+	return lr.r.Read(p)
+}
+
+// SeededEntropy returns a deterministic source of random bytes.  The same seed and
+// labels always produce the same sequence of bytes, so templates that use random
+// functions render reproducibly.  Labels, such as the names of a template and its
+// model, derive distinct sequences from one seed, which keeps each execution with its
+// own source independent of the others.  The returned reader is safe for concurrent
+// use, but it is not suitable for anything that requires real randomness.
+func SeededEntropy(seed int64, labels ...string) io.Reader {
+	var key [32]byte
+	binary.LittleEndian.PutUint64(key[:], uint64(seed))
+	if len(labels) > 0 {
+		h := sha256.New()
+		h.Write(key[:8])
+		for _, l := range labels {
+			// the length prefix keeps ("ab", "c") distinct from ("a", "bc")
+			binary.Write(h, binary.LittleEndian, uint64(len(l)))
+			io.WriteString(h, l)
+		}
+
+		h.Sum(key[:0])
+	}
+
+	return &lockedReader{
+		r: mrand.NewChaCha8(key),
+	}
+}
+
+// clock returns the configured Clock, or SystemClock if none is configured.
+func clock(c ParserConfig) Clock {
+	if c.Clock != nil {
+		return c.Clock
+	}
+
+	return SystemClock
+}
+
+// entropy returns the configured Entropy, or SystemEntropy if none is configured.
+func entropy(c ParserConfig) io.Reader {
+	if c.Entropy != nil {
+		return c.Entropy
+	}
+
+	return SystemEntropy
+}
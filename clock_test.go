@@ -0,0 +1,135 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFixedClock(t *testing.T) {
+	fixed := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	c := FixedClock(fixed)
+	if !c.Now().Equal(fixed) || !c.Now().Equal(fixed) {
+		t.Errorf("a fixed clock should always report %s", fixed)
+	}
+
+	if !clock(ParserConfig{Clock: c}).Now().Equal(fixed) {
+		t.Error("the configured clock should be used")
+	}
+
+	if since := time.Since(clock(ParserConfig{}).Now()); since < 0 || since > time.Minute {
+		t.Error("the system clock should be used when none is configured")
+	}
+}
+
+func TestClockFuncs(t *testing.T) {
+	fixed := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	testCases := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{name: "Now", template: `{{ now | formatTime "2006-01-02T15:04:05Z07:00" }}`, expected: "2024-05-06T07:08:09Z"},
+		{name: "Since", template: `{{ since "2024-05-06T06:08:09Z" }}`, expected: "1h0m0s"},
+		{name: "AddTime", template: `{{ now | addTime "24h" | formatTime "2006-01-02" }}`, expected: "2024-05-07"},
+	}
+
+	p, err := NewParser(ParserConfig{Functions: []string{FuncSetTime}, Clock: FixedClock(fixed)})
+	if err != nil {
+		t.Fatalf("unable to create parser: %s", err)
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			tmpl, err := p.Parse("t", testCase.template)
+			if err != nil {
+				t.Fatalf("unable to parse template: %s", err)
+			}
+
+			var o strings.Builder
+			if err := tmpl.Execute(&o, nil); err != nil || o.String() != testCase.expected {
+				t.Errorf("expected %q, got %q (%v)", testCase.expected, o.String(), err)
+			}
+		})
+	}
+}
+
+// readEntropy reads n bytes from a source of random bytes.
+func readEntropy(t *testing.T, r io.Reader, n int) []byte {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		t.Fatalf("unable to read entropy: %s", err)
+	}
+
+	return b
+}
+
+func TestSeededEntropy(t *testing.T) {
+	testCases := []struct {
+		name   string
+		first  io.Reader
+		second io.Reader
+		same   bool
+	}{
+		{name: "SameSeed", first: SeededEntropy(1), second: SeededEntropy(1), same: true},
+		{name: "DifferentSeeds", first: SeededEntropy(1), second: SeededEntropy(2)},
+		{name: "SameLabels", first: SeededEntropy(1, "t", "m"), second: SeededEntropy(1, "t", "m"), same: true},
+		{name: "DifferentLabels", first: SeededEntropy(1, "t", "m"), second: SeededEntropy(1, "t", "n")},
+		{name: "LabelBoundaries", first: SeededEntropy(1, "ab", "c"), second: SeededEntropy(1, "a", "bc")},
+		{name: "LabelsAndNone", first: SeededEntropy(1), second: SeededEntropy(1, "")},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			first, second := readEntropy(t, testCase.first, 64), readEntropy(t, testCase.second, 64)
+			if bytes.Equal(first, second) != testCase.same {
+				t.Errorf("expected the sequences to be the same: %t", testCase.same)
+			}
+		})
+	}
+
+	// reads of a seeded source continue its sequence, even when concurrent
+	var (
+		expected = readEntropy(t, SeededEntropy(7), 8*100)
+		shared   = SeededEntropy(7)
+		chunks   = make([][]byte, 100)
+		wg       sync.WaitGroup
+	)
+
+	for i := range chunks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			chunks[i] = readEntropy(t, shared, 8)
+		}(i)
+	}
+
+	wg.Wait()
+	seen := make(map[string]bool, len(chunks))
+	for i := 0; i < len(expected); i += 8 {
+		seen[string(expected[i:i+8])] = true
+	}
+
+	for _, c := range chunks {
+		if !seen[string(c)] {
+			t.Errorf("concurrent reads should divide the sequence among them, got %x", c)
+		}
+	}
+}
+
+func TestEntropy(t *testing.T) {
+	r := SeededEntropy(1)
+	if entropy(ParserConfig{Entropy: r}) != r {
+		t.Error("the configured entropy should be used")
+	}
+
+	if entropy(ParserConfig{}) != SystemEntropy {
+		t.Error("the system entropy should be used when none is configured")
+	}
+}
@@ -0,0 +1,143 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"io"
+	"os"
+
+	"github.com/xmidt-org/thoth"
+)
+
+// CheckCmd is the command that parses every template and executes
+// each one against its samples.
+type CheckCmd struct {
+	Samples []string `optional:"true" name:"samples" short:"s" help:"sample patterns"`
+	Update  bool     `optional:"true" default:"false" name:"update" short:"u" help:"rewrite golden files with the rendered output"`
+	Format  string   `optional:"true" default:"console" name:"format" short:"f" enum:"console,json,junit,tap,sarif" help:"result format: console, json, junit, tap, or sarif"`
+	Output  string   `optional:"true" name:"output" short:"o" help:"file to write results to, instead of stdout"`
+
+	MaxFailures      int  `optional:"true" default:"0" name:"max-failures" help:"stop after this many failures, or 0 to check everything"`
+	WarningsAsErrors bool `optional:"true" default:"false" name:"warnings-as-errors" help:"treat warnings as failures"`
+	Validate         bool `optional:"true" default:"false" name:"validate" help:"check that rendered output is well-formed for its media type"`
+}
+
+// openOutput returns the writer for results, which is either stdout or
+// the file named on the command line.
+func (cc CheckCmd) openOutput() (io.WriteCloser, error) {
+	if len(cc.Output) == 0 {
+		return nopCloser{Writer: os.Stdout}, nil
+	}
+
+	return os.Create(cc.Output)
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nc nopCloser) Close() error { return nil }
+
+func (cc CheckCmd) newLogger(cli CLI, out io.Writer) Logger {
+	c := collector{
+		Out:     out,
+		Err:     os.Stderr,
+		Verbose: cli.Verbose,
+	}
+
+	switch cc.Format {
+	case FormatJSON:
+		return &JSONLogger{collector: c}
+
+	case FormatJUnit:
+		return &JUnitLogger{collector: c}
+
+	case FormatTAP:
+		return &TAPLogger{collector: c}
+
+	case FormatSARIF:
+		return &SARIFLogger{collector: c, Root: cli.Root}
+
+	default:
+		return &ConsoleLogger{
+			Out:     out,
+			Err:     os.Stderr,
+			Verbose: cli.Verbose,
+		}
+	}
+}
+
+// newSamples builds the Matcher for sample files from the command line
+// and the configuration.
+func (cc CheckCmd) newSamples(cfg Config) (thoth.Matcher, error) {
+	var patterns []string
+	patterns = append(patterns, cc.Samples...)
+	patterns = append(patterns, cfg.Samples...)
+	return thoth.ParsePatterns(patterns...)
+}
+
+func (cc CheckCmd) newScanner(cli CLI, r Logger, s thoth.Selector, samples thoth.Matcher, schemas *Schemas) Scanner {
+	root := os.DirFS(cli.Root)
+	return Scanner{
+		Root:     root,
+		Logger:   r,
+		Selector: s,
+		Samples:  samples,
+		Schemas:  schemas,
+		Golden: &Golden{
+			Root:   root,
+			Dir:    cli.Root,
+			Update: cc.Update,
+		},
+		MaxFailures:      cc.MaxFailures,
+		WarningsAsErrors: cc.WarningsAsErrors,
+		Validate:         cc.Validate,
+		Seed:             cli.Seed,
+	}
+}
+
+func (cc CheckCmd) run(cli CLI) (int, error) {
+	out, err := cc.openOutput()
+	if err != nil {
+		return ExitBadCommandLine, err
+	}
+
+	defer out.Close()
+	l := cc.newLogger(cli, out)
+	cfg, err := loadConfig(cli, l)
+	if err != nil {
+		return ExitBadConfig, err
+	}
+
+	selector, err := newSelector(cli, cfg)
+	if err != nil {
+		return ExitBadConfig, err
+	}
+
+	samples, err := cc.newSamples(cfg)
+	if err != nil {
+		return ExitBadConfig, err
+	}
+
+	schemas, err := newSchemas(os.DirFS(cli.Root), selectorConfigs(cli, cfg))
+	if err != nil {
+		return ExitBadConfig, err
+	}
+
+	scanner := cc.newScanner(cli, l, selector, samples, schemas)
+	_, summary, err := scanner.Scan()
+	if closeErr := l.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return ExitScanFailed, err
+	}
+
+	if summary.Failures(cc.WarningsAsErrors) > 0 {
+		return ExitChecksFailed, nil
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeFiles creates files, relative to dir, with the given contents.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatalf("unable to create directory for %s: %s", name, err)
+		}
+
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil { //nolint:gosec
+			t.Fatalf("unable to write %s: %s", name, err)
+		}
+	}
+}
+
+func TestCheckExitCodes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		files    map[string]string
+		args     []string
+		expected int
+	}{
+		{
+			name: "Passed",
+			files: map[string]string{
+				"hello.tmpl": `{"hello": "{{.name}}"}`,
+				"hello.yaml": "name: world\n",
+			},
+		},
+		{
+			name: "Failed",
+			files: map[string]string{
+				"hello.tmpl": "{{.name.missing}}",
+				"hello.yaml": "name: world\n",
+			},
+			expected: ExitChecksFailed,
+		},
+		{
+			name: "ParseError",
+			files: map[string]string{
+				"hello.tmpl": "{{.name",
+			},
+			expected: ExitChecksFailed,
+		},
+		{
+			name: "Warning",
+			files: map[string]string{
+				"hello.tmpl":  `{}`,
+				"hello.yaml":  "name: world\n",
+				"orphan.yaml": "name: nobody\n",
+			},
+		},
+		{
+			name: "WarningsAsErrors",
+			files: map[string]string{
+				"hello.tmpl":  `{}`,
+				"hello.yaml":  "name: world\n",
+				"orphan.yaml": "name: nobody\n",
+			},
+			args:     []string{"--warnings-as-errors"},
+			expected: ExitChecksFailed,
+		},
+		{
+			name: "NotValidated",
+			files: map[string]string{
+				"page.html.tmpl": "<p>{{.name}}</p>",
+				"page.yaml":      "name: world\n",
+			},
+		},
+		{
+			name: "Validated",
+			files: map[string]string{
+				"page.html.tmpl": "<p>{{.name}}</p>",
+				"page.yaml":      "name: world\n",
+			},
+			args:     []string{"--validate"},
+			expected: ExitChecksFailed,
+		},
+		{
+			name: "BadConfig",
+			files: map[string]string{
+				ConfigFileName: "templates: [",
+			},
+			expected: ExitBadConfig,
+		},
+		{
+			name:     "BadCommandLine",
+			args:     []string{"--no-such-flag"},
+			expected: ExitBadCommandLine,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, testCase.files)
+			args := []string{
+				"-R", dir, "-t", "*.tmpl",
+				"check", "-s", "*.yaml", "-o", filepath.Join(dir, "results.txt"),
+			}
+
+			exit, err := run(append(args, testCase.args...))
+			if exit != testCase.expected {
+				t.Errorf("expected exit code %d, got %d (%v)", testCase.expected, exit, err)
+			}
+
+			if (exit == ExitBadConfig || exit == ExitBadCommandLine) != (err != nil) {
+				t.Errorf("unexpected error for exit code %d: %v", exit, err)
+			}
+		})
+	}
+}
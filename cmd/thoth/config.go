@@ -15,8 +15,9 @@ const ConfigFileName = ".thoth.yaml"
 
 type Config struct {
 	// Samples is the set of globs that specify files that are sample models
-	// for checking templates.  A sample matches a template if it's file name starts
-	// with either the base name or full name of the template.
+	// for checking templates.  A sample matches a template if it is in the same directory
+	// and its file name starts with either the base name or full name of the template.
+	// Each template is executed against every sample that matches it.
 	Samples []string `json:"samples" yaml:"samples"`
 
 	// Templates associates file patterns with parser configurations.
@@ -0,0 +1,204 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+// diffOp is a single line-level edit.  The kind is one of ' ', '-', or '+'.
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// splitLines breaks text into lines, discarding the line terminators.
+func splitLines(text string) []string {
+	if len(text) == 0 {
+		return nil
+	}
+
+	lines := strings.Split(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// maxDiffCells bounds the size of the table used to compute a minimal edit script.
+// Beyond it, the differing lines are all removed and then all added, which is still
+// a correct diff, just not a minimal one.
+const maxDiffCells = 1 << 22
+
+// diffLines computes an edit script between two sequences of lines.  Lines common to
+// the beginning and end of both are unchanged, and the lines between are diffed using
+// the longest common subsequence, if that is small enough.
+func diffLines(a, b []string) (ops []diffOp) {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{kind: ' ', line: line})
+	}
+
+	ops = append(ops, lcsDiff(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{kind: ' ', line: line})
+	}
+
+	return
+}
+
+// lcsDiff computes a minimal edit script between two sequences of lines using the
+// longest common subsequence.  The table for that takes len(a)*len(b) space, so
+// sequences too large for maxDiffCells are simply replaced.
+func lcsDiff(a, b []string) (ops []diffOp) {
+	if len(a)*len(b) > maxDiffCells {
+		for _, line := range a {
+			ops = append(ops, diffOp{kind: '-', line: line})
+		}
+
+		for _, line := range b {
+			ops = append(ops, diffOp{kind: '+', line: line})
+		}
+
+		return
+	}
+
+	// lcs[i][j] is the length of the LCS of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{kind: ' ', line: a[i]})
+			i++
+			j++
+
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{kind: '-', line: a[i]})
+			i++
+
+		default:
+			ops = append(ops, diffOp{kind: '+', line: b[j]})
+			j++
+		}
+	}
+
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{kind: '-', line: a[i]})
+	}
+
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{kind: '+', line: b[j]})
+	}
+
+	return
+}
+
+// unifiedDiff produces a unified diff of two texts.  If the texts are
+// identical, the empty string is returned.
+func unifiedDiff(fromName, toName, from, to string) string {
+	ops := diffLines(splitLines(from), splitLines(to))
+
+	var o strings.Builder
+	for start := 0; start < len(ops); {
+		// find the next change
+		for start < len(ops) && ops[start].kind == ' ' {
+			start++
+		}
+
+		if start == len(ops) {
+			break
+		}
+
+		// extend the hunk until there is a run of unchanged lines
+		// long enough to separate it from the next change
+		end := start
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+
+			run := end
+			for run < len(ops) && ops[run].kind == ' ' {
+				run++
+			}
+
+			if run == len(ops) || run-end > 2*diffContext {
+				break
+			}
+
+			end = run
+		}
+
+		hunkStart := max(start-diffContext, 0)
+		hunkEnd := min(end+diffContext, len(ops))
+
+		// compute the 1-based line numbers for the hunk header
+		fromLine, toLine := 1, 1
+		for _, op := range ops[:hunkStart] {
+			if op.kind != '+' {
+				fromLine++
+			}
+
+			if op.kind != '-' {
+				toLine++
+			}
+		}
+
+		var fromCount, toCount int
+		for _, op := range ops[hunkStart:hunkEnd] {
+			if op.kind != '+' {
+				fromCount++
+			}
+
+			if op.kind != '-' {
+				toCount++
+			}
+		}
+
+		if o.Len() == 0 {
+			fmt.Fprintf(&o, "--- %s\n+++ %s\n", fromName, toName)
+		}
+
+		fmt.Fprintf(&o, "@@ -%d,%d +%d,%d @@\n", fromLine, fromCount, toLine, toCount)
+		for _, op := range ops[hunkStart:hunkEnd] {
+			o.WriteByte(op.kind)
+			o.WriteString(op.line)
+			o.WriteByte('\n')
+		}
+
+		start = hunkEnd
+	}
+
+	return o.String()
+}
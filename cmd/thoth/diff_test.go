@@ -0,0 +1,156 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	testCases := []struct {
+		name     string
+		from, to string
+		expected string
+	}{
+		{
+			name:     "Identical",
+			from:     "a\nb\n",
+			to:       "a\nb\n",
+			expected: "",
+		},
+		{
+			name:     "Empty",
+			from:     "",
+			to:       "",
+			expected: "",
+		},
+		{
+			name: "Changed",
+			from: "a\nb\nc\n",
+			to:   "a\nx\nc\n",
+			expected: "--- from\n+++ to\n" +
+				"@@ -1,3 +1,3 @@\n a\n-b\n+x\n c\n",
+		},
+		{
+			name: "Added",
+			from: "",
+			to:   "a\n",
+			expected: "--- from\n+++ to\n" +
+				"@@ -1,0 +1,1 @@\n+a\n",
+		},
+		{
+			name: "Removed",
+			from: "a\nb\n",
+			to:   "a\n",
+			expected: "--- from\n+++ to\n" +
+				"@@ -1,2 +1,1 @@\n a\n-b\n",
+		},
+		{
+			name: "SeparateHunks",
+			from: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			to:   "x\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\ny\n",
+			expected: "--- from\n+++ to\n" +
+				"@@ -1,4 +1,4 @@\n-1\n+x\n 2\n 3\n 4\n" +
+				"@@ -9,4 +9,4 @@\n 9\n 10\n 11\n-12\n+y\n",
+		},
+		{
+			name: "MergedHunks",
+			from: "1\n2\n3\n4\n5\n6\n7\n",
+			to:   "x\n2\n3\n4\n5\n6\ny\n",
+			expected: "--- from\n+++ to\n" +
+				"@@ -1,7 +1,7 @@\n-1\n+x\n 2\n 3\n 4\n 5\n 6\n-7\n+y\n",
+		},
+		{
+			name:     "TrailingNewline",
+			from:     "a\n",
+			to:       "a",
+			expected: "",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if actual := unifiedDiff("from", "to", testCase.from, testCase.to); actual != testCase.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", testCase.expected, actual)
+			}
+		})
+	}
+}
+
+// numberedLines returns count lines, each holding a number with the given prefix.
+func numberedLines(prefix string, count int) string {
+	var o strings.Builder
+	for i := 0; i < count; i++ {
+		o.WriteString(prefix + strconv.Itoa(i) + "\n")
+	}
+
+	return o.String()
+}
+
+func TestDiffLines(t *testing.T) {
+	testCases := []struct {
+		name     string
+		from, to string
+		removed  int
+		added    int
+	}{
+		{
+			name:    "SmallChange",
+			from:    "same\n" + numberedLines("a", 10) + "same\n",
+			to:      "same\n" + numberedLines("a", 5) + "x\n" + numberedLines("b", 4) + "same\n",
+			removed: 5,
+			added:   5,
+		},
+		{
+			// the unchanged lines at either end don't count toward the size of the table
+			name:    "LargeCommonEnds",
+			from:    numberedLines("same", 5000) + "a\n" + numberedLines("end", 5000),
+			to:      numberedLines("same", 5000) + "b\n" + numberedLines("end", 5000),
+			removed: 1,
+			added:   1,
+		},
+		{
+			// too large for a minimal diff, so the lines are replaced
+			name:    "Large",
+			from:    numberedLines("a", 3000) + "common\n" + numberedLines("c", 3000),
+			to:      numberedLines("b", 3000) + "common\n" + numberedLines("d", 3000),
+			removed: 6001,
+			added:   6001,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			a, b := splitLines(testCase.from), splitLines(testCase.to)
+			var removed, added int
+			var from, to []string
+			for _, op := range diffLines(a, b) {
+				switch op.kind {
+				case '-':
+					removed++
+					from = append(from, op.line)
+
+				case '+':
+					added++
+					to = append(to, op.line)
+
+				default:
+					from = append(from, op.line)
+					to = append(to, op.line)
+				}
+			}
+
+			if removed != testCase.removed || added != testCase.added {
+				t.Errorf("expected %d removed and %d added, got %d and %d", testCase.removed, testCase.added, removed, added)
+			}
+
+			// whatever the edits are, they must transform one text into the other
+			if strings.Join(from, "\n") != strings.Join(a, "\n") || strings.Join(to, "\n") != strings.Join(b, "\n") {
+				t.Error("the edits do not reproduce the texts")
+			}
+		})
+	}
+}
@@ -0,0 +1,369 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xmidt-org/thoth"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// ExpectKey is the optional model key that holds the assertions for a case.
+	// This key is removed from the model before the template is executed.
+	ExpectKey = "expect"
+
+	// expectInfix marks a file as holding the assertions for a sample file.
+	// For example, foo.expect.yaml holds the assertions for foo.sample.yaml.
+	expectInfix = ".expect"
+)
+
+// AssertionError indicates that rendered output did not satisfy a
+// sample's assertions.  Every failed assertion is reported.
+type AssertionError struct {
+	Failures []string
+}
+
+// Error satisfies the error interface.
+func (ae *AssertionError) Error() string {
+	if len(ae.Failures) == 1 {
+		return "assertion failed: " + ae.Failures[0]
+	}
+
+	return fmt.Sprintf("%d assertions failed:\n%s", len(ae.Failures), strings.Join(ae.Failures, "\n"))
+}
+
+// Expect is the set of assertions made about a sample's rendered output.  Assertions
+// come from a sidecar file next to the sample, from an expect key within each case's
+// model, or both.
+type Expect struct {
+	// Contains are substrings that must appear in the output.
+	Contains []string `json:"contains" yaml:"contains"`
+
+	// NotContains are substrings that must not appear in the output.
+	NotContains []string `json:"notContains" yaml:"notContains"`
+
+	// Matches are regular expressions that must match the output.
+	Matches []string `json:"matches" yaml:"matches"`
+
+	// JSON maps JSON Pointers, e.g. "/device/id", or simple JSONPath expressions,
+	// e.g. "$.device.id" or "$.items[0]", to the values expected at those locations.
+	// The output must be valid JSON for these assertions to pass.
+	JSON map[string]interface{} `json:"json" yaml:"json"`
+
+	// Error is text that must appear in the error that rendering produces.  When
+	// set, this is a negative test:  rendering must fail.
+	Error string `json:"error" yaml:"error"`
+
+	// MinLength is the minimum length, in bytes, of the output.
+	MinLength *int `json:"minLength" yaml:"minLength"`
+
+	// MaxLength is the maximum length, in bytes, of the output.
+	MaxLength *int `json:"maxLength" yaml:"maxLength"`
+}
+
+// isExpect tests if a path refers to an assertions sidecar file.
+func isExpect(name string) bool {
+	return strings.Contains(path.Base(name), expectInfix+".")
+}
+
+// expectName computes the sidecar file name for a sample file.  For example,
+// the sample foo.sample.yaml has the sidecar foo.expect.yaml.
+func expectName(sampleName string) string {
+	stem := strings.TrimSuffix(sampleName, path.Ext(sampleName))
+	stem = strings.TrimSuffix(stem, sampleInfix)
+	return stem + expectInfix + ".yaml"
+}
+
+// readExpect loads the sidecar assertions for a sample file.  If there is
+// no sidecar, this function returns nil with no error.
+func readExpect(fsys fs.FS, sampleName string) (*Expect, error) {
+	name := expectName(sampleName)
+	data, err := fs.ReadFile(fsys, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	e := new(Expect)
+	if err := yaml.Unmarshal(data, e); err != nil {
+		return nil, fmt.Errorf("unable to read assertions [%s]: %w", name, err)
+	}
+
+	return e, nil
+}
+
+// extractExpect removes the expect key from a model and decodes it.  The model itself
+// is not modified.  Instead, a copy without the expect key is returned.
+func extractExpect(m thoth.Model) (thoth.Model, *Expect, error) {
+	raw, ok := m[ExpectKey]
+	if !ok {
+		return m, nil, nil
+	}
+
+	stripped := make(thoth.Model, len(m))
+	for k, v := range m {
+		if k != ExpectKey {
+			stripped[k] = v
+		}
+	}
+
+	// round trip through YAML, so that Expect's field tags apply
+	e := new(Expect)
+	data, err := yaml.Marshal(raw)
+	if err == nil {
+		err = yaml.Unmarshal(data, e)
+	}
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid %s section: %w", ExpectKey, err)
+	}
+
+	return stripped, e, nil
+}
+
+// merge combines the sidecar assertions with a case's own assertions.  The case's
+// list assertions are added to the sidecar's.  Its other assertions take precedence.
+// Either may be nil.
+func (e *Expect) merge(more *Expect) *Expect {
+	switch {
+	case e == nil:
+		return more
+
+	case more == nil:
+		return e
+	}
+
+	merged := &Expect{
+		Contains:    append(append([]string(nil), e.Contains...), more.Contains...),
+		NotContains: append(append([]string(nil), e.NotContains...), more.NotContains...),
+		Matches:     append(append([]string(nil), e.Matches...), more.Matches...),
+		JSON:        make(map[string]interface{}, len(e.JSON)+len(more.JSON)),
+		Error:       e.Error,
+		MinLength:   e.MinLength,
+		MaxLength:   e.MaxLength,
+	}
+
+	for k, v := range e.JSON {
+		merged.JSON[k] = v
+	}
+
+	for k, v := range more.JSON {
+		merged.JSON[k] = v
+	}
+
+	if len(more.Error) > 0 {
+		merged.Error = more.Error
+	}
+
+	if more.MinLength != nil {
+		merged.MinLength = more.MinLength
+	}
+
+	if more.MaxLength != nil {
+		merged.MaxLength = more.MaxLength
+	}
+
+	return merged
+}
+
+// Check applies these assertions to the outcome of rendering a template.  If these
+// assertions expect an error, the output is not examined.  Otherwise, a rendering
+// error is returned as is, and any failed assertions produce an *AssertionError.
+func (e *Expect) Check(output []byte, renderErr error) error {
+	if e == nil {
+		return renderErr
+	}
+
+	if len(e.Error) > 0 {
+		switch {
+		case renderErr == nil:
+			return &AssertionError{Failures: []string{
+				fmt.Sprintf("expected an error containing %q, but rendering succeeded", e.Error),
+			}}
+
+		case !strings.Contains(renderErr.Error(), e.Error):
+			return &AssertionError{Failures: []string{
+				fmt.Sprintf("expected an error containing %q, but got: %s", e.Error, renderErr),
+			}}
+
+		default:
+			return nil
+		}
+	}
+
+	if renderErr != nil {
+		return renderErr
+	}
+
+	var failures []string
+	for _, s := range e.Contains {
+		if !bytes.Contains(output, []byte(s)) {
+			failures = append(failures, fmt.Sprintf("output does not contain %q", s))
+		}
+	}
+
+	for _, s := range e.NotContains {
+		if bytes.Contains(output, []byte(s)) {
+			failures = append(failures, fmt.Sprintf("output contains %q", s))
+		}
+	}
+
+	for _, pattern := range e.Matches {
+		if re, err := regexp.Compile(pattern); err != nil {
+			failures = append(failures, fmt.Sprintf("invalid pattern %q: %s", pattern, err))
+		} else if !re.Match(output) {
+			failures = append(failures, fmt.Sprintf("output does not match %q", pattern))
+		}
+	}
+
+	if e.MinLength != nil && len(output) < *e.MinLength {
+		failures = append(failures, fmt.Sprintf("output length %d is less than %d", len(output), *e.MinLength))
+	}
+
+	if e.MaxLength != nil && len(output) > *e.MaxLength {
+		failures = append(failures, fmt.Sprintf("output length %d is greater than %d", len(output), *e.MaxLength))
+	}
+
+	failures = append(failures, e.checkJSON(output)...)
+	if len(failures) > 0 {
+		return &AssertionError{Failures: failures}
+	}
+
+	return nil
+}
+
+// checkJSON applies the JSON assertions.  Expected and actual values are compared
+// after both are normalized through encoding/json, so that, for example, the YAML
+// integer 1 equals the JSON number 1.0.
+func (e *Expect) checkJSON(output []byte) (failures []string) {
+	if len(e.JSON) == 0 {
+		return
+	}
+
+	var document interface{}
+	if err := json.Unmarshal(output, &document); err != nil {
+		return []string{fmt.Sprintf("output is not valid JSON: %s", err)}
+	}
+
+	exprs := make([]string, 0, len(e.JSON))
+	for expr := range e.JSON {
+		exprs = append(exprs, expr)
+	}
+
+	sort.Strings(exprs) // report failures in a stable order
+	for _, expr := range exprs {
+		expected := e.JSON[expr]
+		tokens, err := parseLocation(expr)
+		if err != nil {
+			failures = append(failures, err.Error())
+			continue
+		}
+
+		actual, found := resolve(document, tokens)
+		if !found {
+			failures = append(failures, fmt.Sprintf("%s: no value found", expr))
+			continue
+		}
+
+		if normalized, err := normalize(expected); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: invalid expected value: %s", expr, err))
+		} else if !reflect.DeepEqual(normalized, actual) {
+			a, _ := json.Marshal(actual)
+			x, _ := json.Marshal(normalized)
+			failures = append(failures, fmt.Sprintf("%s: expected %s, but got %s", expr, x, a))
+		}
+	}
+
+	return
+}
+
+// normalize round trips a value through encoding/json.
+func normalize(v interface{}) (n interface{}, err error) {
+	var data []byte
+	data, err = json.Marshal(v)
+	if err == nil {
+		err = json.Unmarshal(data, &n)
+	}
+
+	return
+}
+
+// jsonPathToken matches a single step in a simple JSONPath expression.
+var jsonPathToken = regexp.MustCompile(`^(?:\.([^.\[]+)|\['([^']*)'\]|\["([^"]*)"\]|\[(\d+)\])`)
+
+// parseLocation converts either a JSON Pointer or a simple JSONPath expression
+// into a sequence of reference tokens.
+func parseLocation(expr string) ([]string, error) {
+	switch {
+	case expr == "":
+		return nil, nil
+
+	case strings.HasPrefix(expr, "/"):
+		tokens := strings.Split(expr[1:], "/")
+		for i, t := range tokens {
+			tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(t)
+		}
+
+		return tokens, nil
+
+	case strings.HasPrefix(expr, "$"):
+		var tokens []string
+		for rest := expr[1:]; len(rest) > 0; {
+			m := jsonPathToken.FindStringSubmatch(rest)
+			if m == nil {
+				return nil, fmt.Errorf("%s: unsupported JSONPath expression", expr)
+			}
+
+			tokens = append(tokens, m[1]+m[2]+m[3]+m[4])
+			rest = rest[len(m[0]):]
+		}
+
+		return tokens, nil
+
+	default:
+		return nil, fmt.Errorf("%s: expected a JSON Pointer or a JSONPath expression", expr)
+	}
+}
+
+// resolve follows reference tokens through a decoded JSON document.
+func resolve(document interface{}, tokens []string) (interface{}, bool) {
+	current := document
+	for _, t := range tokens {
+		switch v := current.(type) {
+		case map[string]interface{}:
+			next, ok := v[t]
+			if !ok {
+				return nil, false
+			}
+
+			current = next
+
+		case []interface{}:
+			i, err := strconv.Atoi(t)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+
+			current = v[i]
+
+		default:
+			return nil, false
+		}
+	}
+
+	return current, true
+}
@@ -0,0 +1,321 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/xmidt-org/thoth"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestExpectCheck(t *testing.T) {
+	const output = `{"device": {"id": "abc", "tags": ["x", "y"], "a/b": 1, "count": 2}}`
+
+	testCases := []struct {
+		name      string
+		expect    *Expect
+		output    string
+		renderErr error
+		failures  []string
+		err       string
+	}{
+		{
+			name:   "Nil",
+			output: output,
+		},
+		{
+			name:      "NilWithError",
+			renderErr: errors.New("render failed"),
+			err:       "render failed",
+		},
+		{
+			name:      "RenderError",
+			expect:    &Expect{Contains: []string{"abc"}},
+			renderErr: errors.New("render failed"),
+			err:       "render failed",
+		},
+		{
+			name: "Passing",
+			expect: &Expect{
+				Contains:    []string{`"abc"`},
+				NotContains: []string{"zzz"},
+				Matches:     []string{`"id":\s*"[a-c]+"`},
+				MinLength:   intPtr(10),
+				MaxLength:   intPtr(1000),
+				JSON: map[string]interface{}{
+					"/device/id":         "abc",
+					"/device/tags/1":     "y",
+					"/device/a~1b":       1,
+					"$.device.count":     2,
+					"$.device.tags[0]":   "x",
+					"$['device']['id']":  "abc",
+					"$.device.tags":      []interface{}{"x", "y"},
+					"":                   map[string]interface{}{"device": map[string]interface{}{"id": "abc", "tags": []interface{}{"x", "y"}, "a/b": 1, "count": 2}},
+					`$["device"]["a/b"]`: 1.0,
+				},
+			},
+			output: output,
+		},
+		{
+			name: "Failing",
+			expect: &Expect{
+				Contains:    []string{"missing"},
+				NotContains: []string{"abc"},
+				Matches:     []string{`^\[`, `(`},
+				MinLength:   intPtr(1000),
+				MaxLength:   intPtr(10),
+			},
+			output: output,
+			failures: []string{
+				`output does not contain "missing"`,
+				`output contains "abc"`,
+				`output does not match "^\\["`,
+				"invalid pattern \"(\"",
+				"is less than 1000",
+				"is greater than 10",
+			},
+		},
+		{
+			name: "FailingJSON",
+			expect: &Expect{
+				JSON: map[string]interface{}{
+					"/device/id":       "xyz",
+					"/device/missing":  1,
+					"$.device.tags[5]": "x",
+					"device":           1,
+					"$.device..id":     1,
+				},
+			},
+			output: output,
+			failures: []string{
+				`$.device..id: unsupported JSONPath expression`,
+				`$.device.tags[5]: no value found`,
+				`/device/id: expected "xyz", but got "abc"`,
+				`/device/missing: no value found`,
+				`device: expected a JSON Pointer or a JSONPath expression`,
+			},
+		},
+		{
+			name:     "InvalidJSON",
+			expect:   &Expect{JSON: map[string]interface{}{"/a": 1}},
+			output:   "not json",
+			failures: []string{"output is not valid JSON"},
+		},
+		{
+			name:      "ExpectedError",
+			expect:    &Expect{Error: "boom", Contains: []string{"ignored"}},
+			renderErr: errors.New("it went boom"),
+		},
+		{
+			name:      "WrongError",
+			expect:    &Expect{Error: "boom"},
+			renderErr: errors.New("something else"),
+			failures:  []string{`expected an error containing "boom", but got: something else`},
+		},
+		{
+			name:     "MissingError",
+			expect:   &Expect{Error: "boom"},
+			output:   output,
+			failures: []string{`expected an error containing "boom", but rendering succeeded`},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := testCase.expect.Check([]byte(testCase.output), testCase.renderErr)
+			var ae *AssertionError
+			switch {
+			case len(testCase.err) > 0:
+				if err == nil || errors.As(err, &ae) || !strings.Contains(err.Error(), testCase.err) {
+					t.Errorf("expected the rendering error %q, got %v", testCase.err, err)
+				}
+
+			case len(testCase.failures) > 0:
+				if !errors.As(err, &ae) || len(ae.Failures) != len(testCase.failures) {
+					t.Fatalf("expected %d failures, got %v", len(testCase.failures), err)
+				}
+
+				for i, f := range testCase.failures {
+					if !strings.Contains(ae.Failures[i], f) {
+						t.Errorf("expected failure %d to contain %q, got %q", i, f, ae.Failures[i])
+					}
+				}
+
+			case err != nil:
+				t.Errorf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
+func TestAssertionError(t *testing.T) {
+	testCases := []struct {
+		failures []string
+		expected string
+	}{
+		{failures: []string{"one"}, expected: "assertion failed: one"},
+		{failures: []string{"one", "two"}, expected: "2 assertions failed:\none\ntwo"},
+	}
+
+	for _, testCase := range testCases {
+		if actual := (&AssertionError{Failures: testCase.failures}).Error(); actual != testCase.expected {
+			t.Errorf("expected %q, got %q", testCase.expected, actual)
+		}
+	}
+}
+
+func TestExpectMerge(t *testing.T) {
+	sidecar := &Expect{
+		Contains:  []string{"a"},
+		JSON:      map[string]interface{}{"/a": 1, "/b": 1},
+		Error:     "sidecar",
+		MinLength: intPtr(1),
+	}
+
+	embedded := &Expect{
+		Contains:  []string{"b"},
+		JSON:      map[string]interface{}{"/b": 2},
+		MaxLength: intPtr(10),
+	}
+
+	testCases := []struct {
+		name     string
+		e, more  *Expect
+		expected *Expect
+	}{
+		{name: "BothNil"},
+		{name: "SidecarOnly", e: sidecar, expected: sidecar},
+		{name: "EmbeddedOnly", more: embedded, expected: embedded},
+		{
+			name: "Both",
+			e:    sidecar,
+			more: embedded,
+			expected: &Expect{
+				Contains:  []string{"a", "b"},
+				JSON:      map[string]interface{}{"/a": 1, "/b": 2},
+				Error:     "sidecar",
+				MinLength: intPtr(1),
+				MaxLength: intPtr(10),
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if actual := testCase.e.merge(testCase.more); !reflect.DeepEqual(testCase.expected, actual) {
+				t.Errorf("expected %+v, got %+v", testCase.expected, actual)
+			}
+		})
+	}
+
+	if len(sidecar.Contains) != 1 || len(sidecar.JSON) != 2 {
+		t.Error("merge should not modify the sidecar assertions")
+	}
+}
+
+func TestExtractExpect(t *testing.T) {
+	testCases := []struct {
+		name     string
+		model    thoth.Model
+		expected *Expect
+		err      bool
+	}{
+		{
+			name:  "None",
+			model: thoth.Model{"a": 1},
+		},
+		{
+			name:     "Embedded",
+			model:    thoth.Model{"a": 1, ExpectKey: map[string]interface{}{"contains": []interface{}{"x"}, "notContains": []interface{}{"y"}}},
+			expected: &Expect{Contains: []string{"x"}, NotContains: []string{"y"}},
+		},
+		{
+			name:  "Invalid",
+			model: thoth.Model{"a": 1, ExpectKey: "not a map"},
+			err:   true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			m, e, err := extractExpect(testCase.model)
+			if testCase.err {
+				if err == nil {
+					t.Error("expected an error")
+				}
+
+				return
+			}
+
+			if err != nil || !reflect.DeepEqual(testCase.expected, e) {
+				t.Errorf("expected %+v, got %+v (%v)", testCase.expected, e, err)
+			}
+
+			if _, ok := m[ExpectKey]; ok || m["a"] != 1 {
+				t.Errorf("unexpected model %v", m)
+			}
+
+			if _, ok := testCase.model[ExpectKey]; !ok && testCase.expected != nil {
+				t.Error("the original model should not be modified")
+			}
+		})
+	}
+}
+
+func TestScannerExpect(t *testing.T) {
+	fsys := fstest.MapFS{
+		"hello.tmpl":          {Data: []byte(`{"hello": "{{.who}}"}`)},
+		"hello.sample.yaml":   {Data: []byte("who: world\n---\nwho: you\nexpect: {contains: [you]}\n")},
+		"hello.expect.yaml":   {Data: []byte("json: {/hello: world}\n")},
+		"failing.tmpl":        {Data: []byte(`{{.missing.field}}`)},
+		"failing.sample.yaml": {Data: []byte("expect: {error: map has no entry}\n")},
+		"broken.tmpl":         {Data: []byte(`{}`)},
+		"broken.sample.yaml":  {Data: []byte("a: 1\n")},
+		"broken.expect.yaml":  {Data: []byte("contains: [")},
+	}
+
+	s, l := newTestScanner(t, fsys)
+	if _, _, err := s.Scan(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	testCases := []struct {
+		name string
+		err  string
+	}{
+		{name: "hello.tmpl hello.sample.yaml#0"},
+		{name: "hello.tmpl hello.sample.yaml#1", err: `/hello: expected "world", but got "you"`},
+		{name: "failing.tmpl failing.sample.yaml"},
+		{name: "broken.tmpl broken.sample.yaml", err: "unable to read assertions [broken.expect.yaml]"},
+	}
+
+	outcomes := l.sampleErrors()
+	for _, testCase := range testCases {
+		actual, ok := outcomes[testCase.name]
+		switch {
+		case !ok:
+			t.Errorf("%s: not executed", testCase.name)
+
+		case len(testCase.err) == 0 && len(actual) > 0:
+			t.Errorf("%s: unexpected error: %s", testCase.name, actual)
+
+		case !strings.Contains(actual, testCase.err):
+			t.Errorf("%s: expected an error containing %q, got %q", testCase.name, testCase.err, actual)
+		}
+	}
+
+	for _, w := range l.warnings {
+		if strings.Contains(w, ".expect.") {
+			t.Errorf("assertion files should not be samples: %s", w)
+		}
+	}
+}
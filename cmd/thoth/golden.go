@@ -0,0 +1,133 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/xmidt-org/thoth"
+)
+
+const (
+	// goldenInfix marks a file as containing the expected output for a sample.
+	goldenInfix = ".golden"
+
+	// sampleInfix is the optional marker in a sample's file name, which is
+	// removed when computing the golden file name.
+	sampleInfix = ".sample"
+)
+
+// GoldenMismatchError indicates that a template's rendered output did
+// not match the contents of its golden file.
+type GoldenMismatchError struct {
+	Name string
+	Diff string
+}
+
+// Error satisfies the error interface.  The message includes the unified diff.
+func (gme *GoldenMismatchError) Error() string {
+	return fmt.Sprintf("output does not match golden file %s\n%s", gme.Name, strings.TrimRight(gme.Diff, "\n"))
+}
+
+// MissingGoldenError indicates that there is no golden file for a sample's
+// rendered output to be compared against.
+type MissingGoldenError struct {
+	Name string
+}
+
+// Error satisfies the error interface.
+func (mge *MissingGoldenError) Error() string {
+	return fmt.Sprintf("golden file %s does not exist, run with --update to create it", mge.Name)
+}
+
+// isGolden tests if a path refers to a golden file.
+func isGolden(name string) bool {
+	return strings.Contains(path.Base(name), goldenInfix+".") ||
+		strings.HasSuffix(name, goldenInfix)
+}
+
+// outputExt determines the file extension of a template's rendered output.
+// For a name like foo.json.tmpl, this is the extension that precedes the
+// template's own extension.  Otherwise, the extension is derived from the
+// template's media type.
+func outputExt(t thoth.Template) string {
+	name := t.Name()
+	if ext := path.Ext(strings.TrimSuffix(name, path.Ext(name))); len(ext) > 0 {
+		return ext
+	}
+
+	if exts, err := mime.ExtensionsByType(thoth.MediaType(t)); err == nil && len(exts) > 0 {
+		return exts[0]
+	}
+
+	return ""
+}
+
+// goldenName computes the golden file name for a template and sample case.  For example,
+// the template foo.json.tmpl and the sample foo.sample.yaml have a golden file named
+// foo.golden.json.  When a sample file has multiple cases, the case ID is included,
+// e.g. foo.0.golden.json.
+func goldenName(t thoth.Template, c Case) string {
+	stem := strings.TrimSuffix(c.File, path.Ext(c.File))
+	stem = strings.TrimSuffix(stem, sampleInfix)
+	if len(c.ID) > 0 {
+		// case names come from sample data, so keep them from introducing directories
+		stem += "." + strings.ReplaceAll(c.ID, "/", "_")
+	}
+
+	return stem + goldenInfix + outputExt(t)
+}
+
+// Golden compares rendered output against golden files, or rewrites
+// the golden files when Update is set.
+type Golden struct {
+	// Root is the file system golden files are read from.
+	Root fs.FS
+
+	// Dir is the system directory that corresponds to Root.  Golden files
+	// are written relative to this directory.
+	Dir string
+
+	// Update indicates that golden files should be rewritten with the actual output
+	// rather than compared.
+	Update bool
+}
+
+// Check verifies that actual output matches the given golden file.  If no golden
+// file exists, this method returns a *MissingGoldenError unless Update is set.
+func (g Golden) Check(name string, actual []byte) error {
+	if g.Update {
+		return os.WriteFile(filepath.Join(g.Dir, filepath.FromSlash(name)), actual, 0644) //nolint:gosec
+	}
+
+	expected, err := fs.ReadFile(g.Root, name)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return &MissingGoldenError{Name: name}
+
+	case err != nil:
+		return err
+
+	case bytes.Equal(expected, actual):
+		return nil
+	}
+
+	diff := unifiedDiff(name, "actual", string(expected), string(actual))
+	if len(diff) == 0 {
+		diff = "(files differ only in their trailing newline)"
+	}
+
+	return &GoldenMismatchError{
+		Name: name,
+		Diff: diff,
+	}
+}
@@ -0,0 +1,209 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/xmidt-org/thoth"
+)
+
+func TestGoldenName(t *testing.T) {
+	testCases := []struct {
+		template  string
+		mediaType string
+		c         Case
+		expected  string
+	}{
+		{
+			template: "foo.json.tmpl",
+			c:        Case{File: "foo.sample.yaml"},
+			expected: "foo.golden.json",
+		},
+		{
+			template: "foo.json.tmpl",
+			c:        Case{File: "foo.yaml", ID: "0"},
+			expected: "foo.0.golden.json",
+		},
+		{
+			template: "dir/foo.json.tmpl",
+			c:        Case{File: "dir/foo.sample.yaml", ID: "a/b"},
+			expected: "dir/foo.a_b.golden.json",
+		},
+		{
+			template:  "foo.tmpl",
+			mediaType: "application/json",
+			c:         Case{File: "foo.yaml"},
+			expected:  "foo.golden.json",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.expected, func(t *testing.T) {
+			p, err := thoth.NewParser(thoth.ParserConfig{MediaType: testCase.mediaType})
+			if err != nil {
+				t.Fatalf("unable to create parser: %s", err)
+			}
+
+			tmpl, err := p.Parse(testCase.template, "")
+			if err != nil {
+				t.Fatalf("unable to parse template: %s", err)
+			}
+
+			if actual := goldenName(tmpl, testCase.c); actual != testCase.expected {
+				t.Errorf("expected %s, got %s", testCase.expected, actual)
+			}
+		})
+	}
+}
+
+func TestIsGolden(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected bool
+	}{
+		{name: "foo.golden.json", expected: true},
+		{name: "dir/foo.0.golden.json", expected: true},
+		{name: "foo.golden", expected: true},
+		{name: "foo.json", expected: false},
+		{name: "golden.d/foo.json", expected: false},
+	}
+
+	for _, testCase := range testCases {
+		if actual := isGolden(testCase.name); actual != testCase.expected {
+			t.Errorf("%s: expected %t, got %t", testCase.name, testCase.expected, actual)
+		}
+	}
+}
+
+func TestGoldenCheck(t *testing.T) {
+	g := Golden{
+		Root: fstest.MapFS{
+			"foo.golden.json": {Data: []byte("{\"a\": 1}\n")},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		file     string
+		actual   string
+		mismatch string
+		missing  bool
+	}{
+		{
+			name:   "Match",
+			file:   "foo.golden.json",
+			actual: "{\"a\": 1}\n",
+		},
+		{
+			name:    "Missing",
+			file:    "bar.golden.json",
+			actual:  "anything",
+			missing: true,
+		},
+		{
+			name:     "Different",
+			file:     "foo.golden.json",
+			actual:   "{\"a\": 2}\n",
+			mismatch: "-{\"a\": 1}\n+{\"a\": 2}",
+		},
+		{
+			name:     "TrailingNewline",
+			file:     "foo.golden.json",
+			actual:   "{\"a\": 1}",
+			mismatch: "differ only in their trailing newline",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := g.Check(testCase.file, []byte(testCase.actual))
+			var mge *MissingGoldenError
+			if testCase.missing {
+				if !errors.As(err, &mge) || mge.Name != testCase.file || !strings.Contains(err.Error(), "--update") {
+					t.Errorf("expected a MissingGoldenError, got %v", err)
+				}
+
+				return
+			}
+
+			if len(testCase.mismatch) == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %s", err)
+				}
+
+				return
+			}
+
+			var gme *GoldenMismatchError
+			if !errors.As(err, &gme) {
+				t.Fatalf("expected a GoldenMismatchError, got %v", err)
+			}
+
+			if gme.Name != testCase.file || !strings.Contains(err.Error(), testCase.mismatch) {
+				t.Errorf("expected a mismatch for %s containing %q, got %s", testCase.file, testCase.mismatch, err)
+			}
+		})
+	}
+}
+
+func TestGoldenUpdate(t *testing.T) {
+	dir := t.TempDir()
+	g := Golden{
+		Root:   os.DirFS(dir),
+		Dir:    dir,
+		Update: true,
+	}
+
+	if err := g.Check("foo.golden.json", []byte("updated")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "foo.golden.json"))
+	if err != nil || string(data) != "updated" {
+		t.Errorf("expected the golden file to be written, got %q, %v", data, err)
+	}
+}
+
+func TestScannerGolden(t *testing.T) {
+	fsys := fstest.MapFS{
+		"pass.txt.tmpl":      {Data: []byte("hello {{.name}}\n")},
+		"pass.yaml":          {Data: []byte("name: world\n")},
+		"pass.golden.txt":    {Data: []byte("hello world\n")},
+		"fail.txt.tmpl":      {Data: []byte("hello {{.name}}\n")},
+		"fail.yaml":          {Data: []byte("name: world\n")},
+		"fail.golden.txt":    {Data: []byte("goodbye world\n")},
+		"multi.txt.tmpl":     {Data: []byte("{{.name}}\n")},
+		"multi.yaml":         {Data: []byte("name: a\n---\nname: b\n")},
+		"multi.a.golden.txt": {Data: []byte("a\n")},
+		"multi.b.golden.txt": {Data: []byte("b\n")},
+		"new.txt.tmpl":       {Data: []byte("{{.name}}\n")},
+		"new.yaml":           {Data: []byte("name: new\n")},
+	}
+
+	s, l := newTestScanner(t, fsys)
+	s.Golden = &Golden{Root: fsys}
+	_, summary, err := s.Scan()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// a sample without a golden file passes, with a warning
+	if summary.Passed != 4 || summary.Failed != 1 || summary.Warnings != 1 {
+		t.Errorf("expected 4 passed, 1 failed, and 1 warning, got %+v", summary)
+	}
+
+	if len(l.warnings) != 1 || !strings.HasPrefix(l.warnings[0], "new.yaml: golden file new.golden.txt does not exist") {
+		t.Errorf("expected a warning about the missing golden file, got %q", l.warnings)
+	}
+
+	if actual := l.sampleErrors()["fail.txt.tmpl fail.yaml"]; !strings.Contains(actual, "+hello world") {
+		t.Errorf("expected a golden diff, got %q", actual)
+	}
+}
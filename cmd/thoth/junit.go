@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// junitParseCase is the name of the test case that represents parsing a template.
+const junitParseCase = "parse"
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Line      int           `xml:"line,attr,omitempty"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Errors   int         `xml:"errors,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitReport struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Errors   int          `xml:"errors,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+// newJUnitProblem creates the failure or error element for a test case.
+func newJUnitProblem(err error, problemType string) *junitProblem {
+	return &junitProblem{
+		Message: firstLine(err.Error()),
+		Type:    problemType,
+		Text:    err.Error(),
+	}
+}
+
+// JUnitLogger writes all results as a JUnit XML document when closed.  Each template
+// is a test suite.  Parsing the template and executing each sample are the test cases.
+type JUnitLogger struct {
+	collector
+}
+
+func (jl *JUnitLogger) Close() error {
+	report := junitReport{
+		Name:   "thoth",
+		Suites: make([]junitSuite, 0, len(jl.results)),
+	}
+
+	for _, tr := range jl.results {
+		suite := junitSuite{
+			Name:  tr.Name,
+			Tests: 1 + len(tr.SampleResults),
+			Cases: make([]junitCase, 0, 1+len(tr.SampleResults)),
+		}
+
+		parse := junitCase{
+			Name:      junitParseCase,
+			ClassName: tr.Name,
+			File:      tr.Name,
+		}
+
+		if tr.Err != nil {
+			loc := errorLocation(tr.Err)
+			parse.File, parse.Line = loc.file(tr.Name), loc.Line
+			parse.Error = newJUnitProblem(tr.Err, "ParseError")
+			suite.Errors++
+		}
+
+		suite.Cases = append(suite.Cases, parse)
+		for _, sr := range tr.SampleResults {
+			sc := junitCase{
+				Name:      sr.Name,
+				ClassName: tr.Name,
+				File:      tr.Name,
+			}
+
+			if sr.Err != nil {
+				loc := errorLocation(sr.Err)
+				sc.File, sc.Line = loc.file(tr.Name), loc.Line
+				sc.Failure = newJUnitProblem(sr.Err, "SampleFailure")
+				suite.Failures++
+			}
+
+			suite.Cases = append(suite.Cases, sc)
+		}
+
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Errors += suite.Errors
+		report.Suites = append(report.Suites, suite)
+	}
+
+	_, err := io.WriteString(jl.out(), xml.Header)
+	if err == nil {
+		e := xml.NewEncoder(jl.out())
+		e.Indent("", "  ")
+		err = e.Encode(report)
+	}
+
+	if err == nil {
+		_, err = io.WriteString(jl.out(), "\n")
+	}
+
+	return err
+}
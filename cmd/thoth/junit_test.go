@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestJUnitLogger(t *testing.T) {
+	var out bytes.Buffer
+	logTestResults(t, &JUnitLogger{collector: collector{Out: &out}})
+
+	if !strings.HasPrefix(out.String(), xml.Header) {
+		t.Errorf("expected the XML header, got:\n%s", out.String())
+	}
+
+	var report junitReport
+	if err := xml.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JUnit report: %s\n%s", err, out.String())
+	}
+
+	if report.Tests != 6 || report.Failures != 1 || report.Errors != 1 {
+		t.Errorf("unexpected totals: tests=%d failures=%d errors=%d", report.Tests, report.Failures, report.Errors)
+	}
+
+	testCases := []struct {
+		suite    string
+		cases    []string
+		failures int
+		errors   int
+	}{
+		{suite: "good.tmpl", cases: []string{junitParseCase, "good.yaml"}},
+		{suite: "bad.tmpl", cases: []string{junitParseCase}, errors: 1},
+		{suite: "failing.tmpl", cases: []string{junitParseCase, "failing.yaml#0", "failing.yaml#1"}, failures: 1},
+	}
+
+	if len(report.Suites) != len(testCases) {
+		t.Fatalf("expected %d suites, got %d", len(testCases), len(report.Suites))
+	}
+
+	for i, testCase := range testCases {
+		suite := report.Suites[i]
+		if suite.Name != testCase.suite || suite.Failures != testCase.failures || suite.Errors != testCase.errors {
+			t.Errorf("unexpected suite %+v", suite)
+		}
+
+		if suite.Tests != len(testCase.cases) || len(suite.Cases) != len(testCase.cases) {
+			t.Errorf("%s: expected %d cases, got %d", testCase.suite, len(testCase.cases), len(suite.Cases))
+			continue
+		}
+
+		for j, name := range testCase.cases {
+			if suite.Cases[j].Name != name || suite.Cases[j].ClassName != testCase.suite {
+				t.Errorf("%s: unexpected case %+v", testCase.suite, suite.Cases[j])
+			}
+		}
+	}
+
+	parse := report.Suites[1].Cases[0]
+	if parse.Error == nil || parse.Error.Type != "ParseError" || parse.Line != 3 {
+		t.Errorf("unexpected parse error case %+v", parse)
+	}
+
+	failure := report.Suites[2].Cases[2].Failure
+	if failure == nil || failure.Message != "assertion failed: first line" || !strings.Contains(failure.Text, "second line") {
+		t.Errorf("unexpected failure %+v", failure)
+	}
+}
@@ -8,16 +8,24 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 )
 
 const (
 	ErrorLabel = "ERROR"
 	PassLabel  = "PASS"
 	FailLabel  = "FAIL"
+	WarnLabel  = "WARN"
 
 	indent = "  "
 )
 
+// indentDetail indents all but the first line of a message, so that multiline
+// errors such as diffs are nested under their result line.
+func indentDetail(v interface{}) string {
+	return strings.ReplaceAll(fmt.Sprint(v), "\n", "\n"+indent+indent)
+}
+
 type SampleResult struct {
 	Name string
 	Err  error
@@ -33,6 +41,17 @@ type Logger interface {
 	Debugf(message string, args ...interface{}) error
 	Errorf(message string, args ...interface{}) error
 	Result(TemplateResult) error
+
+	// Warning reports a problem with the named file that does not, by default,
+	// fail the run.
+	Warning(name, message string) error
+
+	// Summary reports the counts for the entire run.
+	Summary(Summary) error
+
+	// Close flushes any buffered results.  Loggers that produce a single
+	// document, such as JUnit XML, write that document when closed.
+	Close() error
 }
 
 type ConsoleLogger struct {
@@ -60,7 +79,7 @@ func (cr *ConsoleLogger) err() io.Writer {
 }
 
 func (cr *ConsoleLogger) Debugf(format string, args ...interface{}) (err error) {
-	if !cr.Verbose {
+	if cr.Verbose {
 		_, err = fmt.Fprintf(cr.out(), format+"\n", args...)
 	}
 
@@ -94,15 +113,17 @@ func (cr *ConsoleLogger) Result(tr TemplateResult) (err error) {
 	if cr.Verbose {
 		// always write the header for verbose output,
 		headerOnce()
-	} else if tr.Err != nil {
+	}
+
+	if tr.Err != nil {
 		headerOnce()
-		fmt.Fprintf(&cr.buffer, "%s%-5.5s\t%s\n", indent, ErrorLabel, tr.Err)
+		fmt.Fprintf(&cr.buffer, "%s%-5.5s\t%s\n", indent, ErrorLabel, indentDetail(tr.Err))
 	}
 
 	for _, sr := range tr.SampleResults {
 		if sr.Err != nil {
 			headerOnce()
-			fmt.Fprintf(&cr.buffer, "%s%-5.5s\t%s\t%s\n", indent, FailLabel, sr.Name, sr.Err)
+			fmt.Fprintf(&cr.buffer, "%s%-5.5s\t%s\t%s\n", indent, FailLabel, sr.Name, indentDetail(sr.Err))
 		} else if cr.Verbose {
 			headerOnce()
 			fmt.Fprintf(&cr.buffer, "%s%-5.5s\t%s\n", indent, PassLabel, sr.Name)
@@ -116,3 +137,18 @@ func (cr *ConsoleLogger) Result(tr TemplateResult) (err error) {
 
 	return
 }
+
+func (cr *ConsoleLogger) Warning(name, message string) (err error) {
+	_, err = fmt.Fprintf(cr.out(), "%-5.5s\t%s\t%s\n", WarnLabel, name, message)
+	return
+}
+
+func (cr *ConsoleLogger) Summary(s Summary) (err error) {
+	_, err = fmt.Fprintln(cr.out(), s)
+	return
+}
+
+// Close is a nop, since each result is written immediately.
+func (cr *ConsoleLogger) Close() error {
+	return nil
+}
@@ -6,8 +6,10 @@ package main
 import (
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/alecthomas/kong"
 	"github.com/xmidt-org/thoth"
@@ -23,6 +25,19 @@ const (
 	// ExitScanFailed is the process exit code indicating the the file system scan
 	// for templates and/or samples failed.
 	ExitScanFailed
+
+	// ExitChecksFailed is the process exit code indicating that at least one template
+	// could not be parsed or at least one sample failed.  Warnings are included when
+	// the warnings-as-errors option is set.
+	ExitChecksFailed
+
+	// ExitRenderFailed is the process exit code indicating that a template could
+	// not be rendered.
+	ExitRenderFailed
+
+	// ExitServeFailed is the process exit code indicating that the HTTP server
+	// could not be started.
+	ExitServeFailed
 )
 
 var (
@@ -30,39 +45,43 @@ var (
 	ErrCfgMismatch = errors.New("cannot specify a config file and the no-cfg option")
 )
 
-// CLI represents the thoth command line.
+// CLI represents the thoth command line.  The options in this struct are global
+// and apply to every command.
 type CLI struct {
-	Root      string   `optional:"true" default:"." name:"root" short:"R" help:"root directory for file traversal"`
-	Verbose   bool     `optional:"true" default:"false" name:"verbose" short:"v" help:"verbose output"`
-	NoCfg     bool     `optional:"true" default:"false" name:"no-cfg" help:"ignore any configuration files"`
-	Cfg       string   `optional:"true" name:"cfg" help:"explicit configuration file, instead of searching"`
-	Samples   []string `optional:"true" name:"samples" short:"s" help:"sample patterns"`
-	Templates []string `optional:"true" name:"templates" short:"t" help:"template patterns"`
+	Root      string     `optional:"true" default:"." name:"root" short:"R" help:"root directory for file traversal"`
+	Verbose   bool       `optional:"true" default:"false" name:"verbose" short:"v" help:"verbose output"`
+	NoCfg     bool       `optional:"true" default:"false" name:"no-cfg" help:"ignore any configuration files"`
+	Cfg       string     `optional:"true" name:"cfg" help:"explicit configuration file, instead of searching"`
+	Templates []string   `optional:"true" name:"templates" short:"t" help:"template patterns"`
+	Clock     *time.Time `optional:"true" name:"clock" help:"fixed RFC 3339 time reported by template time functions"`
+	Seed      *int64     `optional:"true" name:"seed" help:"seed for template random functions, making their output reproducible"`
+
+	Check  CheckCmd  `cmd:"" default:"withargs" help:"check templates against their samples (default)"`
+	Render RenderCmd `cmd:"" help:"render a single template"`
+	Serve  ServeCmd  `cmd:"" help:"run an HTTP service that renders templates"`
 }
 
-// parseCommandLine uses kong to parse the given arguments and return the CLI instance.
-func parseCommandLine(args []string) (cli CLI, err error) {
-	var parser *kong.Kong
+// parseCommandLine uses kong to parse the given arguments and return the CLI instance
+// along with the name of the selected command.
+func parseCommandLine(args []string) (cli CLI, command string, err error) {
+	var (
+		parser *kong.Kong
+		ctx    *kong.Context
+	)
+
 	parser, err = kong.New(&cli)
 	if err == nil {
-		_, err = parser.Parse(args)
+		ctx, err = parser.Parse(args)
 	}
 
 	if err == nil {
+		command = ctx.Selected().Name
 		cli.Root, err = filepath.Abs(os.ExpandEnv(cli.Root))
 	}
 
 	return
 }
 
-func newLogger(cli CLI) Logger {
-	return &ConsoleLogger{
-		Out:     os.Stdout,
-		Err:     os.Stderr,
-		Verbose: cli.Verbose,
-	}
-}
-
 // loadConfig uses the command line to locate the optional thoth configuration file.
 func loadConfig(cli CLI, l Logger) (c Config, err error) {
 	switch {
@@ -96,8 +115,12 @@ func loadConfig(cli CLI, l Logger) (c Config, err error) {
 	return
 }
 
-func newSelector(cli CLI, cfg Config) (thoth.Selector, error) {
-	var scfgs []thoth.SelectorConfig
+// selectorConfigs returns the template configurations from the command line
+// and the configuration file, in the order they are matched.  Includes are read
+// from the root directory, and any clock or seed from the command line is applied.
+// Commands that render samples give each execution its own source of random values
+// derived from the seed, so the seed here applies only to other executions.
+func selectorConfigs(cli CLI, cfg Config) (scfgs []thoth.SelectorConfig) {
 	if len(cli.Templates) > 0 {
 		// any template globs from the command-line are given
 		// the default parser settings
@@ -109,41 +132,49 @@ func newSelector(cli CLI, cfg Config) (thoth.Selector, error) {
 	}
 
 	scfgs = append(scfgs, cfg.Templates...)
-	return thoth.NewSelector(scfgs...)
-}
+	var (
+		root    = os.DirFS(cli.Root)
+		clock   thoth.Clock
+		entropy io.Reader
+	)
+
+	if cli.Clock != nil {
+		clock = thoth.FixedClock(*cli.Clock)
+	}
+
+	if cli.Seed != nil {
+		entropy = thoth.SeededEntropy(*cli.Seed)
+	}
 
-func newScanner(cli CLI, r Logger, s thoth.Selector) Scanner {
-	return Scanner{
-		Root:     os.DirFS(cli.Root),
-		Logger:   r,
-		Selector: s,
+	for i := range scfgs {
+		scfgs[i].Parser.FS = root
+		scfgs[i].Parser.Clock = clock
+		scfgs[i].Parser.Entropy = entropy
 	}
+
+	return
+}
+
+func newSelector(cli CLI, cfg Config) (thoth.Selector, error) {
+	return thoth.NewSelector(selectorConfigs(cli, cfg)...)
 }
 
 func run(args []string) (int, error) {
-	cli, err := parseCommandLine(args)
+	cli, command, err := parseCommandLine(args)
 	if err != nil {
 		return ExitBadCommandLine, err
 	}
 
-	l := newLogger(cli)
-	cfg, err := loadConfig(cli, l)
-	if err != nil {
-		return ExitBadConfig, err
-	}
+	switch command {
+	case "render":
+		return cli.Render.run(cli)
 
-	selector, err := newSelector(cli, cfg)
-	if err != nil {
-		return ExitBadConfig, err
-	}
+	case "serve":
+		return cli.Serve.run(cli)
 
-	scanner := newScanner(cli, l, selector)
-	_, _, err = scanner.Scan()
-	if err != nil {
-		return ExitScanFailed, err
+	default:
+		return cli.Check.run(cli)
 	}
-
-	return 0, nil
 }
 
 func main() {
@@ -0,0 +1,190 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/xmidt-org/thoth"
+	"gopkg.in/yaml.v3"
+)
+
+// stdinName is the model file name that refers to standard input.
+const stdinName = "-"
+
+// RenderCmd is the command that renders a single template to a file or stdout.
+type RenderCmd struct {
+	Template  string   `arg:"" name:"template" help:"path of the template to render, which must be under the root"`
+	Model     string   `optional:"true" name:"model" short:"m" help:"file containing the model, or - for stdin"`
+	Format    string   `optional:"true" name:"model-format" enum:",json,yaml,toml,dotenv" default:"" help:"format of the model, which is otherwise determined by the file extension, or yaml for stdin"`
+	Set       []string `optional:"true" name:"set" sep:"none" help:"set a model value, as in a.b.c=value"`
+	Out       string   `optional:"true" name:"out" help:"file to write the rendered output to, instead of stdout"`
+	MediaType bool     `optional:"true" default:"false" name:"media-type" help:"print the media type of the rendered output to stderr"`
+}
+
+// templateName converts the template path on the command line into the name
+// used to select a parser, which is the slash-separated path relative to root.
+func (rc RenderCmd) templateName(root string) (string, error) {
+	path, err := filepath.Abs(rc.Template)
+	if err == nil {
+		path, err = filepath.Rel(root, path)
+	}
+
+	if err == nil && (path == ".." || strings.HasPrefix(path, ".."+string(filepath.Separator))) {
+		err = fmt.Errorf("template [%s] is not under the root directory [%s]", rc.Template, root)
+	}
+
+	return filepath.ToSlash(path), err
+}
+
+// modelFormat determines the format of the model file.
+func (rc RenderCmd) modelFormat() string {
+	if len(rc.Format) > 0 {
+		return rc.Format
+	} else if format, ok := thoth.FormatOf(rc.Model); ok {
+		return format
+	}
+
+	return thoth.FormatYAML
+}
+
+// readModel loads the model from the model file, if any, then applies
+// each --set option.
+func (rc RenderCmd) readModel() (m thoth.Model, err error) {
+	switch rc.Model {
+	case "":
+		m = thoth.Model{}
+
+	case stdinName:
+		m, err = thoth.DecodeModel(os.Stdin, rc.modelFormat())
+
+	default:
+		var f *os.File
+		f, err = os.Open(rc.Model)
+		if err == nil {
+			defer f.Close()
+			m, err = thoth.DecodeModel(f, rc.modelFormat())
+		}
+	}
+
+	for i := 0; err == nil && i < len(rc.Set); i++ {
+		err = setValue(m, rc.Set[i])
+	}
+
+	return
+}
+
+// modelName is the name of the model file used to derive random values.  A model file
+// under the root is named as it would be as a sample, so that it renders the same
+// random values as the check command.
+func (rc RenderCmd) modelName(root string) string {
+	if len(rc.Model) == 0 || rc.Model == stdinName {
+		return rc.Model
+	}
+
+	path, err := filepath.Abs(rc.Model)
+	if err == nil {
+		path, err = filepath.Rel(root, path)
+	}
+
+	if err != nil || path == ".." || strings.HasPrefix(path, ".."+string(filepath.Separator)) {
+		path = rc.Model
+	}
+
+	return filepath.ToSlash(path)
+}
+
+// setValue applies a single a.b.c=value expression to a model.  Intermediate
+// maps are created as needed.  The value is interpreted as a YAML scalar, so
+// numbers and booleans are typed appropriately.
+func setValue(m thoth.Model, expr string) error {
+	key, text, ok := strings.Cut(expr, "=")
+	if !ok || len(key) == 0 {
+		return fmt.Errorf("invalid --set expression [%s]: expected key=value", expr)
+	}
+
+	var value interface{}
+	if err := yaml.Unmarshal([]byte(text), &value); err != nil || value == nil {
+		value = text
+	}
+
+	thoth.KeyValues{}.
+		Append(thoth.Model{key: value}).
+		ApplyOverridesDeep(m, thoth.ListReplace)
+
+	return nil
+}
+
+// write sends the rendered output to the --out file or stdout.
+func (rc RenderCmd) write(output []byte) error {
+	if len(rc.Out) > 0 {
+		return os.WriteFile(rc.Out, output, 0644) //nolint:gosec
+	}
+
+	_, err := os.Stdout.Write(output)
+	return err
+}
+
+func (rc RenderCmd) run(cli CLI) (int, error) {
+	l := &ConsoleLogger{
+		Out:     os.Stderr,
+		Err:     os.Stderr,
+		Verbose: cli.Verbose,
+	}
+
+	cfg, err := loadConfig(cli, l)
+	if err != nil {
+		return ExitBadConfig, err
+	}
+
+	selector, err := newSelector(cli, cfg)
+	if err != nil {
+		return ExitBadConfig, err
+	}
+
+	name, err := rc.templateName(cli.Root)
+	if err != nil {
+		return ExitBadCommandLine, err
+	}
+
+	p, found := selector.Select(name)
+	if !found {
+		return ExitBadConfig, fmt.Errorf("no templates configuration matches [%s]", name)
+	}
+
+	m, err := rc.readModel()
+	if err != nil {
+		return ExitBadCommandLine, err
+	}
+
+	content, err := os.ReadFile(filepath.Join(cli.Root, filepath.FromSlash(name)))
+	if err != nil {
+		return ExitRenderFailed, err
+	}
+
+	t, err := p.Parse(name, string(content))
+	if err != nil {
+		return ExitRenderFailed, err
+	}
+
+	var output bytes.Buffer
+	if err = thoth.ExecuteWith(context.Background(), t, &output, m, executeOptions(cli.Seed, name, rc.modelName(cli.Root))...); err != nil {
+		return ExitRenderFailed, err
+	}
+
+	if rc.MediaType {
+		fmt.Fprintln(os.Stderr, thoth.MediaType(t))
+	}
+
+	if err = rc.write(output.Bytes()); err != nil {
+		return ExitRenderFailed, err
+	}
+
+	return 0, nil
+}
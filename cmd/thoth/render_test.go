@@ -0,0 +1,269 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/xmidt-org/thoth"
+)
+
+func TestSetValue(t *testing.T) {
+	testCases := []struct {
+		name     string
+		model    thoth.Model
+		expr     string
+		expected thoth.Model
+		err      bool
+	}{
+		{
+			name:     "String",
+			model:    thoth.Model{},
+			expr:     "a=hello",
+			expected: thoth.Model{"a": "hello"},
+		},
+		{
+			name:     "Number",
+			model:    thoth.Model{},
+			expr:     "a=12",
+			expected: thoth.Model{"a": 12},
+		},
+		{
+			name:     "Bool",
+			model:    thoth.Model{},
+			expr:     "a=true",
+			expected: thoth.Model{"a": true},
+		},
+		{
+			name:     "Empty",
+			model:    thoth.Model{},
+			expr:     "a=",
+			expected: thoth.Model{"a": ""},
+		},
+		{
+			name:     "Nested",
+			model:    thoth.Model{"a": map[string]interface{}{"b": 1, "c": 2}},
+			expr:     "a.b.d=x",
+			expected: thoth.Model{"a": map[string]interface{}{"b": map[string]interface{}{"d": "x"}, "c": 2}},
+		},
+		{
+			name:     "Merged",
+			model:    thoth.Model{"a": map[string]interface{}{"b": 1, "c": 2}},
+			expr:     "a.b=3",
+			expected: thoth.Model{"a": map[string]interface{}{"b": 3, "c": 2}},
+		},
+		{
+			name:     "Created",
+			model:    thoth.Model{},
+			expr:     "a.b=x=y",
+			expected: thoth.Model{"a": map[string]interface{}{"b": "x=y"}},
+		},
+		{
+			name:  "NoEquals",
+			model: thoth.Model{},
+			expr:  "a",
+			err:   true,
+		},
+		{
+			name:  "NoKey",
+			model: thoth.Model{},
+			expr:  "=a",
+			err:   true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := setValue(testCase.model, testCase.expr)
+			switch {
+			case testCase.err && err == nil:
+				t.Error("expected an error")
+
+			case !testCase.err && err != nil:
+				t.Errorf("unexpected error: %s", err)
+
+			case !testCase.err && !reflect.DeepEqual(testCase.expected, testCase.model):
+				t.Errorf("expected %v, got %v", testCase.expected, testCase.model)
+			}
+		})
+	}
+}
+
+func TestRenderTemplateName(t *testing.T) {
+	root := t.TempDir()
+	testCases := []struct {
+		template string
+		expected string
+		err      bool
+	}{
+		{template: filepath.Join(root, "foo.tmpl"), expected: "foo.tmpl"},
+		{template: filepath.Join(root, "dir", "foo.tmpl"), expected: "dir/foo.tmpl"},
+		{template: filepath.Join(root, "..", "foo.tmpl"), err: true},
+	}
+
+	for _, testCase := range testCases {
+		actual, err := RenderCmd{Template: testCase.template}.templateName(root)
+		switch {
+		case testCase.err && err == nil:
+			t.Errorf("%s: expected an error", testCase.template)
+
+		case !testCase.err && (err != nil || actual != testCase.expected):
+			t.Errorf("%s: expected %s, got %s (%v)", testCase.template, testCase.expected, actual, err)
+		}
+	}
+}
+
+func TestRenderModelFormat(t *testing.T) {
+	testCases := []struct {
+		rc       RenderCmd
+		expected string
+	}{
+		{rc: RenderCmd{}, expected: thoth.FormatYAML},
+		{rc: RenderCmd{Model: stdinName}, expected: thoth.FormatYAML},
+		{rc: RenderCmd{Model: "model.json"}, expected: thoth.FormatJSON},
+		{rc: RenderCmd{Model: "model.toml"}, expected: thoth.FormatTOML},
+		{rc: RenderCmd{Model: "model.json", Format: thoth.FormatYAML}, expected: thoth.FormatYAML},
+	}
+
+	for _, testCase := range testCases {
+		if actual := testCase.rc.modelFormat(); actual != testCase.expected {
+			t.Errorf("%+v: expected %s, got %s", testCase.rc, testCase.expected, actual)
+		}
+	}
+}
+
+func TestRenderRun(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"hello.tmpl": `{"greeting": "{{.greeting}}", "name": "{{.name}}", "count": {{.count}}}`,
+		"model.json": `{"greeting": "hello", "name": "world", "count": 1}`,
+	})
+
+	testCases := []struct {
+		name     string
+		args     []string
+		expected string
+		exit     int
+	}{
+		{
+			name:     "Model",
+			args:     []string{"-m", filepath.Join(dir, "model.json")},
+			expected: `{"greeting": "hello", "name": "world", "count": 1}`,
+		},
+		{
+			name:     "Set",
+			args:     []string{"-m", filepath.Join(dir, "model.json"), "--set", "name=you", "--set", "count=2"},
+			expected: `{"greeting": "hello", "name": "you", "count": 2}`,
+		},
+		{
+			name:     "SetWithCommas",
+			args:     []string{"-m", filepath.Join(dir, "model.json"), "--set", "name=you, me", "--set", "count=[1,2]"},
+			expected: `{"greeting": "hello", "name": "you, me", "count": [1 2]}`,
+		},
+		{
+			name: "MissingModel",
+			args: []string{"-m", filepath.Join(dir, "missing.json")},
+			exit: ExitBadCommandLine,
+		},
+		{
+			name: "BadSet",
+			args: []string{"--set", "novalue"},
+			exit: ExitBadCommandLine,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			out := filepath.Join(t.TempDir(), "out.json")
+			args := append([]string{"-R", dir, "-t", "*.tmpl", "render", filepath.Join(dir, "hello.tmpl"), "--out", out}, testCase.args...)
+			exit, err := run(args)
+			if exit != testCase.exit {
+				t.Fatalf("expected exit code %d, got %d (%v)", testCase.exit, exit, err)
+			}
+
+			if exit != 0 {
+				return
+			}
+
+			actual, err := os.ReadFile(out)
+			if err != nil || string(actual) != testCase.expected {
+				t.Errorf("expected %s, got %s (%v)", testCase.expected, actual, err)
+			}
+		})
+	}
+}
+
+func TestRenderModelName(t *testing.T) {
+	root := t.TempDir()
+	outside := filepath.Join(root, "..", "model.yaml")
+	testCases := []struct {
+		model    string
+		expected string
+	}{
+		{model: "", expected: ""},
+		{model: stdinName, expected: stdinName},
+		{model: filepath.Join(root, "model.yaml"), expected: "model.yaml"},
+		{model: filepath.Join(root, "dir", "model.yaml"), expected: "dir/model.yaml"},
+		{model: outside, expected: filepath.ToSlash(outside)},
+	}
+
+	for _, testCase := range testCases {
+		if actual := (RenderCmd{Model: testCase.model}).modelName(root); actual != testCase.expected {
+			t.Errorf("%s: expected %s, got %s", testCase.model, testCase.expected, actual)
+		}
+	}
+}
+
+func TestRenderSeedMatchesCheck(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		ConfigFileName: "templates:\n  - patterns: [\"*.tmpl\"]\n    parser:\n      functions: [random, time]\n",
+		"hello.tmpl":   `{"id": "{{ uuid }}", "n": {{ randInt 0 1000000 }}, "year": "{{ now | formatTime "2006" }}"}`,
+		"hello.yaml":   "name: world\n",
+	})
+
+	// the golden file records the output of the check command
+	exit, err := run([]string{"-R", dir, "--seed", "5", "--clock", "2020-01-01T00:00:00Z", "check", "-s", "*.yaml", "-u", "-o", filepath.Join(dir, "results.txt")})
+	if exit != 0 {
+		t.Fatalf("unable to update golden files: %d (%v)", exit, err)
+	}
+
+	golden, err := os.ReadFile(filepath.Join(dir, "hello.golden.json"))
+	if err != nil {
+		t.Fatalf("unable to read golden file: %s", err)
+	}
+
+	testCases := []struct {
+		name string
+		args []string
+		same bool
+	}{
+		{name: "SameSeed", args: []string{"--seed", "5", "--clock", "2020-01-01T00:00:00Z"}, same: true},
+		{name: "DifferentSeed", args: []string{"--seed", "6", "--clock", "2020-01-01T00:00:00Z"}},
+		{name: "DifferentClock", args: []string{"--seed", "5", "--clock", "2021-01-01T00:00:00Z"}},
+		{name: "NoSeed", args: []string{"--clock", "2020-01-01T00:00:00Z"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			out := filepath.Join(t.TempDir(), "out.json")
+			args := append(append([]string{"-R", dir}, testCase.args...), "render", filepath.Join(dir, "hello.tmpl"), "-m", filepath.Join(dir, "hello.yaml"), "--out", out)
+			if exit, err := run(args); exit != 0 {
+				t.Fatalf("unable to render: %d (%v)", exit, err)
+			}
+
+			actual, err := os.ReadFile(out)
+			if err != nil {
+				t.Fatalf("unable to read output: %s", err)
+			}
+
+			if (string(actual) == string(golden)) != testCase.same {
+				t.Errorf("expected the output to match check's: %t, got %s and %s", testCase.same, actual, golden)
+			}
+		})
+	}
+}
@@ -0,0 +1,298 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	FormatConsole = "console"
+	FormatJSON    = "json"
+	FormatJUnit   = "junit"
+	FormatTAP     = "tap"
+	FormatSARIF   = "sarif"
+)
+
+// templateErrorPattern matches the location prefix that the golang template
+// packages put on parse and execution errors, e.g. "template: foo.tmpl:3:14: ..."
+var templateErrorPattern = regexp.MustCompile(`template: ?(.+?):(\d+)(?::(\d+))?: `)
+
+// Location is a position within a template file.  Zero values mean the
+// corresponding part of the position is unknown.
+type Location struct {
+	// File is the name of the file the position is in.  This can differ from the
+	// template being checked, as when an error occurs in an include or a layout.
+	File   string
+	Line   int
+	Column int
+}
+
+// file returns the name of the file this location is in, or the given
+// name if this location doesn't know its file.
+func (l Location) file(name string) string {
+	if len(l.File) > 0 {
+		return l.File
+	}
+
+	return name
+}
+
+// errorLocation extracts the template file, line, and column from an error, if present.
+func errorLocation(err error) (l Location) {
+	if err == nil {
+		return
+	}
+
+	if m := templateErrorPattern.FindStringSubmatch(err.Error()); m != nil {
+		l.File = m[1]
+		l.Line, _ = strconv.Atoi(m[2])
+		l.Column, _ = strconv.Atoi(m[3])
+	}
+
+	return
+}
+
+// collector is the common implementation for loggers that report results
+// in a machine-readable format.  Diagnostic messages go to Err, leaving Out
+// for the report itself.
+type collector struct {
+	Out     io.Writer
+	Err     io.Writer
+	Verbose bool
+
+	results  []TemplateResult
+	warnings []warning
+	summary  Summary
+}
+
+// warning is a single problem reported through Logger.Warning.
+type warning struct {
+	Name    string `json:"name"`
+	Message string `json:"message"`
+}
+
+func (c *collector) out() io.Writer {
+	if c.Out != nil {
+		return c.Out
+	}
+
+	return os.Stdout
+}
+
+func (c *collector) err() io.Writer {
+	if c.Err != nil {
+		return c.Err
+	}
+
+	return os.Stderr
+}
+
+func (c *collector) Debugf(format string, args ...interface{}) (err error) {
+	if c.Verbose {
+		_, err = fmt.Fprintf(c.err(), format+"\n", args...)
+	}
+
+	return
+}
+
+func (c *collector) Errorf(format string, args ...interface{}) (err error) {
+	_, err = fmt.Fprintf(c.err(), format+"\n", args...)
+	return
+}
+
+func (c *collector) Result(tr TemplateResult) error {
+	c.results = append(c.results, tr)
+	return nil
+}
+
+func (c *collector) Warning(name, message string) error {
+	c.warnings = append(c.warnings, warning{Name: name, Message: message})
+	return nil
+}
+
+func (c *collector) Summary(s Summary) error {
+	c.summary = s
+	return nil
+}
+
+// errorText returns the text of an error, or the empty string for nil.
+func errorText(err error) string {
+	if err != nil {
+		return err.Error()
+	}
+
+	return ""
+}
+
+// firstLine returns the first line of a possibly multiline message.
+func firstLine(message string) string {
+	if i := strings.IndexByte(message, '\n'); i >= 0 {
+		return message[:i]
+	}
+
+	return message
+}
+
+type jsonSample struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Error  string `json:"error,omitempty"`
+	File   string `json:"file,omitempty"`
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+}
+
+type jsonTemplate struct {
+	Name    string       `json:"name"`
+	Passed  bool         `json:"passed"`
+	Error   string       `json:"error,omitempty"`
+	File    string       `json:"file,omitempty"`
+	Line    int          `json:"line,omitempty"`
+	Column  int          `json:"column,omitempty"`
+	Samples []jsonSample `json:"samples"`
+}
+
+type jsonReport struct {
+	Templates []jsonTemplate `json:"templates"`
+	Warnings  []warning      `json:"warnings"`
+	Summary   Summary        `json:"summary"`
+}
+
+// JSONLogger writes all results as a single JSON document when closed.
+type JSONLogger struct {
+	collector
+}
+
+func (jl *JSONLogger) Close() error {
+	report := jsonReport{
+		Templates: make([]jsonTemplate, 0, len(jl.results)),
+		Warnings:  append(make([]warning, 0, len(jl.warnings)), jl.warnings...),
+		Summary:   jl.summary,
+	}
+
+	for _, tr := range jl.results {
+		loc := errorLocation(tr.Err)
+		jt := jsonTemplate{
+			Name:    tr.Name,
+			Passed:  tr.Err == nil,
+			Error:   errorText(tr.Err),
+			File:    loc.File,
+			Line:    loc.Line,
+			Column:  loc.Column,
+			Samples: make([]jsonSample, 0, len(tr.SampleResults)),
+		}
+
+		for _, sr := range tr.SampleResults {
+			loc := errorLocation(sr.Err)
+			jt.Passed = jt.Passed && sr.Err == nil
+			jt.Samples = append(jt.Samples, jsonSample{
+				Name:   sr.Name,
+				Passed: sr.Err == nil,
+				Error:  errorText(sr.Err),
+				File:   loc.File,
+				Line:   loc.Line,
+				Column: loc.Column,
+			})
+		}
+
+		report.Templates = append(report.Templates, jt)
+	}
+
+	e := json.NewEncoder(jl.out())
+	e.SetIndent("", "  ")
+	return e.Encode(report)
+}
+
+// TAPLogger writes results using the Test Anything Protocol, version 13.  Each template
+// parse and each sample execution is a test point.  Test points are written as results
+// arrive, and the plan is written when this logger is closed.  Warnings and the summary
+// are written as TAP comments.
+type TAPLogger struct {
+	collector
+
+	started bool
+	count   int
+}
+
+// header writes the TAP version line once, before any other output.
+func (tl *TAPLogger) header() (err error) {
+	if !tl.started {
+		tl.started = true
+		_, err = fmt.Fprintln(tl.out(), "TAP version 13")
+	}
+
+	return
+}
+
+func (tl *TAPLogger) point(description string, err error) (werr error) {
+	werr = tl.header()
+	tl.count++
+	if werr == nil && err == nil {
+		_, werr = fmt.Fprintf(tl.out(), "ok %d - %s\n", tl.count, description)
+	} else if werr == nil {
+		var o strings.Builder
+		fmt.Fprintf(&o, "not ok %d - %s\n", tl.count, description)
+		o.WriteString("  ---\n  message: |\n")
+		for _, line := range strings.Split(err.Error(), "\n") {
+			fmt.Fprintf(&o, "    %s\n", line)
+		}
+
+		if loc := errorLocation(err); loc.Line > 0 {
+			fmt.Fprintf(&o, "  file: %s\n", loc.File)
+			fmt.Fprintf(&o, "  line: %d\n", loc.Line)
+			if loc.Column > 0 {
+				fmt.Fprintf(&o, "  column: %d\n", loc.Column)
+			}
+		}
+
+		o.WriteString("  ...\n")
+		_, werr = io.WriteString(tl.out(), o.String())
+	}
+
+	return
+}
+
+func (tl *TAPLogger) Result(tr TemplateResult) (err error) {
+	err = tl.point(tr.Name, tr.Err)
+	for i := 0; err == nil && i < len(tr.SampleResults); i++ {
+		sr := tr.SampleResults[i]
+		err = tl.point(tr.Name+" "+sr.Name, sr.Err)
+	}
+
+	return
+}
+
+func (tl *TAPLogger) Warning(name, message string) (err error) {
+	err = tl.header()
+	if err == nil {
+		_, err = fmt.Fprintf(tl.out(), "# %s %s: %s\n", WarnLabel, name, message)
+	}
+
+	return
+}
+
+func (tl *TAPLogger) Summary(s Summary) (err error) {
+	err = tl.header()
+	if err == nil {
+		_, err = fmt.Fprintf(tl.out(), "# %s\n", s)
+	}
+
+	return
+}
+
+func (tl *TAPLogger) Close() (err error) {
+	err = tl.header()
+	if err == nil {
+		_, err = fmt.Fprintf(tl.out(), "1..%d\n", tl.count)
+	}
+
+	return
+}
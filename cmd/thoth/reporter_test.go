@@ -0,0 +1,247 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// logTestResults sends a fixed set of results, with a parse error, a sample
+// failure, and a warning, to a Logger and then closes it.
+func logTestResults(t *testing.T, l Logger) {
+	results := []TemplateResult{
+		{
+			Name: "good.tmpl",
+			SampleResults: []SampleResult{
+				{Name: "good.yaml"},
+			},
+		},
+		{
+			Name: "bad.tmpl",
+			Err:  errors.New("template: bad.tmpl:3:14: unexpected EOF"),
+		},
+		{
+			Name: "failing.tmpl",
+			SampleResults: []SampleResult{
+				{Name: "failing.yaml#0"},
+				{Name: "failing.yaml#1", Err: errors.New("assertion failed: first line\nsecond line")},
+			},
+		},
+	}
+
+	var s Summary
+	for _, tr := range results {
+		if err := l.Result(tr); err != nil {
+			t.Fatalf("unable to log result: %s", err)
+		}
+
+		s.add(tr)
+	}
+
+	s.Warnings++
+	if err := l.Warning("orphan.yaml", "sample does not match any template"); err != nil {
+		t.Fatalf("unable to log warning: %s", err)
+	}
+
+	if err := l.Summary(s); err != nil {
+		t.Fatalf("unable to log summary: %s", err)
+	}
+
+	if err := l.Close(); err != nil {
+		t.Fatalf("unable to close logger: %s", err)
+	}
+}
+
+func TestErrorLocation(t *testing.T) {
+	testCases := []struct {
+		err      error
+		expected Location
+	}{
+		{err: nil},
+		{err: errors.New("no location")},
+		{err: errors.New("template: foo.tmpl:3: function not defined"), expected: Location{File: "foo.tmpl", Line: 3}},
+		{err: errors.New("template: foo.tmpl:3:14: executing"), expected: Location{File: "foo.tmpl", Line: 3, Column: 14}},
+		{err: errors.New("wrapped: template: dir/foo.tmpl:7:2: executing"), expected: Location{File: "dir/foo.tmpl", Line: 7, Column: 2}},
+		{err: errors.New(`template: includes/a.inc:2:5: executing "greet"`), expected: Location{File: "includes/a.inc", Line: 2, Column: 5}},
+	}
+
+	for _, testCase := range testCases {
+		if actual := errorLocation(testCase.err); actual != testCase.expected {
+			t.Errorf("%v: expected %+v, got %+v", testCase.err, testCase.expected, actual)
+		}
+	}
+}
+
+func TestLoggersLocateErrorsInOtherFiles(t *testing.T) {
+	testCases := []struct {
+		name     string
+		logger   func(*bytes.Buffer) Logger
+		expected string
+	}{
+		{name: "JSON", logger: func(out *bytes.Buffer) Logger { return &JSONLogger{collector: collector{Out: out}} }, expected: `"file": "includes/a.inc"`},
+		{name: "TAP", logger: func(out *bytes.Buffer) Logger { return &TAPLogger{collector: collector{Out: out}} }, expected: "  file: includes/a.inc\n"},
+		{name: "JUnit", logger: func(out *bytes.Buffer) Logger { return &JUnitLogger{collector: collector{Out: out}} }, expected: `file="includes/a.inc" line="2"`},
+		{name: "SARIF", logger: func(out *bytes.Buffer) Logger { return &SARIFLogger{collector: collector{Out: out}} }, expected: `"uri": "includes/a.inc"`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var out bytes.Buffer
+			l := testCase.logger(&out)
+			err := l.Result(TemplateResult{
+				Name: "page.tmpl",
+				SampleResults: []SampleResult{
+					{Name: "page.yaml", Err: errors.New(`template: includes/a.inc:2:5: executing "greet" at <.missing>: map has no entry for key "missing"`)},
+				},
+			})
+
+			if err == nil {
+				err = l.Close()
+			}
+
+			if err != nil {
+				t.Fatalf("unable to log results: %s", err)
+			}
+
+			if !strings.Contains(out.String(), testCase.expected) {
+				t.Errorf("expected the include to be the location, containing %s, got:\n%s", testCase.expected, out.String())
+			}
+		})
+	}
+}
+
+func TestJSONLogger(t *testing.T) {
+	var out bytes.Buffer
+	logTestResults(t, &JSONLogger{collector: collector{Out: &out}})
+
+	var report jsonReport
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON report: %s\n%s", err, out.String())
+	}
+
+	expected := []jsonTemplate{
+		{
+			Name:    "good.tmpl",
+			Passed:  true,
+			Samples: []jsonSample{{Name: "good.yaml", Passed: true}},
+		},
+		{
+			Name:    "bad.tmpl",
+			Error:   "template: bad.tmpl:3:14: unexpected EOF",
+			File:    "bad.tmpl",
+			Line:    3,
+			Column:  14,
+			Samples: []jsonSample{},
+		},
+		{
+			Name: "failing.tmpl",
+			Samples: []jsonSample{
+				{Name: "failing.yaml#0", Passed: true},
+				{Name: "failing.yaml#1", Error: "assertion failed: first line\nsecond line"},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(expected, report.Templates) {
+		t.Errorf("expected templates %+v, got %+v", expected, report.Templates)
+	}
+
+	if len(report.Warnings) != 1 || report.Warnings[0].Name != "orphan.yaml" {
+		t.Errorf("unexpected warnings: %+v", report.Warnings)
+	}
+
+	if report.Summary.Failed != 1 || report.Summary.Errors != 1 || report.Summary.Passed != 2 {
+		t.Errorf("unexpected summary: %+v", report.Summary)
+	}
+}
+
+func TestTAPLogger(t *testing.T) {
+	var out bytes.Buffer
+	logTestResults(t, &TAPLogger{collector: collector{Out: &out}})
+
+	expected := `TAP version 13
+ok 1 - good.tmpl
+ok 2 - good.tmpl good.yaml
+not ok 3 - bad.tmpl
+  ---
+  message: |
+    template: bad.tmpl:3:14: unexpected EOF
+  file: bad.tmpl
+  line: 3
+  column: 14
+  ...
+ok 4 - failing.tmpl
+ok 5 - failing.tmpl failing.yaml#0
+not ok 6 - failing.tmpl failing.yaml#1
+  ---
+  message: |
+    assertion failed: first line
+    second line
+  ...
+# WARN orphan.yaml: sample does not match any template
+# 3 templates, 3 samples: 2 passed, 1 failed, 1 errors, 1 warnings
+1..6
+`
+
+	if out.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
+
+func TestTAPLoggerEmpty(t *testing.T) {
+	var out bytes.Buffer
+	if err := (&TAPLogger{collector: collector{Out: &out}}).Close(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if expected := "TAP version 13\n1..0\n"; out.String() != expected {
+		t.Errorf("expected %q, got %q", expected, out.String())
+	}
+}
+
+func TestConsoleLogger(t *testing.T) {
+	testCases := []struct {
+		name     string
+		verbose  bool
+		expected string
+	}{
+		{
+			name: "Quiet",
+			expected: "bad.tmpl\n" +
+				"  ERROR\ttemplate: bad.tmpl:3:14: unexpected EOF\n" +
+				"failing.tmpl\n" +
+				"  FAIL \tfailing.yaml#1\tassertion failed: first line\n    second line\n" +
+				"WARN \torphan.yaml\tsample does not match any template\n" +
+				"3 templates, 3 samples: 2 passed, 1 failed, 1 errors, 1 warnings\n",
+		},
+		{
+			name:    "Verbose",
+			verbose: true,
+			expected: "good.tmpl\n" +
+				"  PASS \tgood.yaml\n" +
+				"bad.tmpl\n" +
+				"  ERROR\ttemplate: bad.tmpl:3:14: unexpected EOF\n" +
+				"failing.tmpl\n" +
+				"  PASS \tfailing.yaml#0\n" +
+				"  FAIL \tfailing.yaml#1\tassertion failed: first line\n    second line\n" +
+				"WARN \torphan.yaml\tsample does not match any template\n" +
+				"3 templates, 3 samples: 2 passed, 1 failed, 1 errors, 1 warnings\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var out bytes.Buffer
+			logTestResults(t, &ConsoleLogger{Out: &out, Err: &out, Verbose: testCase.verbose})
+			if out.String() != testCase.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", testCase.expected, out.String())
+			}
+		})
+	}
+}
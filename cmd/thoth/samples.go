@@ -5,19 +5,202 @@ package main
 
 import (
 	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/xmidt-org/thoth"
 )
 
+// CaseNameKey is the optional model key that names a case within a sample
+// file that contains multiple models.
+const CaseNameKey = "name"
+
+// Case is a single model from a sample file.  A sample file may contain
+// several models, such as a multi-document YAML stream, each of which is
+// a separate case.
+type Case struct {
+	// File is the name of the sample file.
+	File string
+
+	// ID distinguishes this case from the others in the same file.  This is
+	// either the case's name or its index.  ID is empty when the file has only
+	// one model.
+	ID string
+
+	// Model is the data the template is executed with.
+	Model thoth.Model
+
+	// Expect holds the assertions for this case.  If nil, a case passes
+	// as long as the template executes without error.
+	Expect *Expect
+}
+
+// Name returns the display name of this case, which is either the file name
+// or file#id.
+func (c Case) Name() string {
+	if len(c.ID) > 0 {
+		return c.File + "#" + c.ID
+	}
+
+	return c.File
+}
+
+// caseID determines the ID of a case within a sample file.  A string name field
+// in the model is used if present.  Otherwise, the index of the case is used.
+func caseID(index int, m thoth.Model) string {
+	if name, ok := m[CaseNameKey].(string); ok && len(name) > 0 {
+		return name
+	}
+
+	return strconv.Itoa(index)
+}
+
+// sample is a cache entry for a single sample file.
+type sample struct {
+	matched bool
+	loaded  bool
+	cases   []Case
+	err     error
+}
+
 // Samples is both a loader and a cache for sample Model data.
 type Samples struct {
-	Root    fs.FS
-	Suffix  string
-	samples map[string]interface{}
+	Root fs.FS
+
+	names   []string
+	samples map[string]*sample
 }
 
+// Add registers a sample file.  The sample is not loaded until
+// it is needed by a template.
 func (s *Samples) Add(name string) {
 	if s.samples == nil {
-		s.samples = make(map[string]interface{})
+		s.samples = make(map[string]*sample)
+	}
+
+	if _, exists := s.samples[name]; !exists {
+		s.names = append(s.names, name)
+		s.samples[name] = new(sample) // placeholder until actually loaded
+	}
+}
+
+// Len returns the number of samples registered with this instance.
+func (s *Samples) Len() int {
+	return len(s.names)
+}
+
+// baseName returns the name of a template file with all extensions removed.
+func baseName(fileName string) string {
+	if i := strings.IndexByte(fileName, '.'); i > 0 {
+		return fileName[:i]
+	}
+
+	return fileName
+}
+
+// hasNamePrefix tests if fileName starts with prefix followed by a '.'.
+func hasNamePrefix(fileName, prefix string) bool {
+	return len(fileName) > len(prefix) &&
+		strings.HasPrefix(fileName, prefix) &&
+		fileName[len(prefix)] == '.'
+}
+
+// Match returns the names of the samples that apply to the given template.
+// A sample applies to a template if it is in the same directory and its file
+// name starts with either the base name or the full name of the template,
+// followed by a '.'.  For example, both foo.yaml and foo.json.tmpl.yaml are
+// samples for the template foo.json.tmpl.
+func (s *Samples) Match(templateName string) (names []string) {
+	dir, full := path.Split(templateName)
+	base := baseName(full)
+	for _, n := range s.names {
+		sdir, sfile := path.Split(n)
+		if n == templateName || sdir != dir {
+			continue
+		}
+
+		if hasNamePrefix(sfile, full) || hasNamePrefix(sfile, base) {
+			s.samples[n].matched = true
+			names = append(names, n)
+		}
+	}
+
+	return
+}
+
+// Unmatched returns the names of the samples that have not matched any
+// template passed to Match.
+func (s *Samples) Unmatched() (names []string) {
+	for _, n := range s.names {
+		if !s.samples[n].matched {
+			names = append(names, n)
+		}
+	}
+
+	return
+}
+
+// Load returns the cases for the given sample.  Each sample is read from
+// the Root file system at most once, and the results are cached.
+func (s *Samples) Load(name string) ([]Case, error) {
+	e, ok := s.samples[name]
+	if !ok {
+		s.Add(name)
+		e = s.samples[name]
+	}
+
+	if !e.loaded {
+		e.cases, e.err = s.read(name)
+		e.loaded = true
+	}
+
+	return e.cases, e.err
+}
+
+// read loads a sample file from the Root file system.  The file's extension
+// determines its format.  A file with multiple models, such as a YAML stream
+// or a JSON array, produces one Case per model.  Assertions come from the
+// sample's sidecar file, if any, and from each model's expect key.  Case IDs
+// are unique within the file.
+func (s *Samples) read(name string) ([]Case, error) {
+	models, err := thoth.LoadModels(s.Root, name)
+	if err != nil {
+		return nil, err
+	}
+
+	sidecar, err := readExpect(s.Root, name)
+	if err != nil {
+		return nil, err
+	}
+
+	cases := make([]Case, 0, len(models))
+	ids := make(map[string]bool, len(models))
+	for i, m := range models {
+		c := Case{
+			File: name,
+		}
+
+		var embedded *Expect
+		c.Model, embedded, err = extractExpect(m)
+		if err != nil {
+			return nil, err
+		}
+
+		c.Expect = sidecar.merge(embedded)
+		if len(models) > 1 {
+			// a name used by an earlier case gets the index appended, so that
+			// each case has its own results and golden file
+			c.ID = caseID(i, c.Model)
+			for ids[c.ID] {
+				c.ID += "." + strconv.Itoa(i)
+			}
+
+			ids[c.ID] = true
+		}
+
+		cases = append(cases, c)
 	}
 
-	s.samples[name] = struct{}{} // placeholder until actually loaded
+	return cases, nil
 }
@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/xmidt-org/thoth"
+)
+
+func TestSamplesMatch(t *testing.T) {
+	samples := new(Samples)
+	for _, name := range []string{
+		"foo.yaml",
+		"foo.json.tmpl.yaml",
+		"foo.sample.json",
+		"foobar.yaml",
+		"bar.yaml",
+		"sub/foo.yaml",
+	} {
+		samples.Add(name)
+	}
+
+	testCases := []struct {
+		template string
+		expected []string
+	}{
+		{
+			template: "foo.json.tmpl",
+			expected: []string{"foo.yaml", "foo.json.tmpl.yaml", "foo.sample.json"},
+		},
+		{
+			template: "bar.tmpl",
+			expected: []string{"bar.yaml"},
+		},
+		{
+			template: "sub/foo.tmpl",
+			expected: []string{"sub/foo.yaml"},
+		},
+		{
+			template: "missing.tmpl",
+			expected: nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.template, func(t *testing.T) {
+			if actual := samples.Match(testCase.template); !reflect.DeepEqual(testCase.expected, actual) {
+				t.Errorf("expected %q, got %q", testCase.expected, actual)
+			}
+		})
+	}
+
+	if unmatched := samples.Unmatched(); !reflect.DeepEqual([]string{"foobar.yaml"}, unmatched) {
+		t.Errorf("unexpected unmatched samples: %q", unmatched)
+	}
+}
+
+func TestSamplesLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"single.yaml": {Data: []byte("value: 1\n")},
+		"multi.yaml":  {Data: []byte("name: first\nvalue: 1\n---\nvalue: 2\n")},
+		"array.json":  {Data: []byte(`[{"value": 1}, {"name": "second", "value": 2}]`)},
+		"bad.json":    {Data: []byte(`{`)},
+		"dupes.yaml":  {Data: []byte("name: a\nvalue: 1\n---\nname: a\nvalue: 2\n---\nname: a.1\nvalue: 3\n---\nname: \"1\"\nvalue: 4\n---\nvalue: 5\n")},
+	}
+
+	testCases := []struct {
+		name     string
+		expected []string
+		err      bool
+	}{
+		{
+			name:     "single.yaml",
+			expected: []string{"single.yaml"},
+		},
+		{
+			name:     "multi.yaml",
+			expected: []string{"multi.yaml#first", "multi.yaml#1"},
+		},
+		{
+			name:     "array.json",
+			expected: []string{"array.json#0", "array.json#second"},
+		},
+		{
+			name:     "dupes.yaml",
+			expected: []string{"dupes.yaml#a", "dupes.yaml#a.1", "dupes.yaml#a.1.2", "dupes.yaml#1", "dupes.yaml#4"},
+		},
+		{
+			name: "bad.json",
+			err:  true,
+		},
+		{
+			name: "missing.yaml",
+			err:  true,
+		},
+	}
+
+	samples := &Samples{Root: fsys}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			cases, err := samples.Load(testCase.name)
+			if testCase.err {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			var names []string
+			for _, c := range cases {
+				names = append(names, c.Name())
+				if _, ok := c.Model["value"]; !ok {
+					t.Errorf("case %s has no value: %v", c.Name(), c.Model)
+				}
+			}
+
+			if !reflect.DeepEqual(testCase.expected, names) {
+				t.Errorf("expected cases %q, got %q", testCase.expected, names)
+			}
+
+			again, _ := samples.Load(testCase.name)
+			if len(again) != len(cases) || (len(cases) > 0 && &again[0] != &cases[0]) {
+				t.Error("expected the cases to be cached")
+			}
+		})
+	}
+}
+
+func TestCaseID(t *testing.T) {
+	testCases := []struct {
+		model    thoth.Model
+		expected string
+	}{
+		{model: thoth.Model{}, expected: "3"},
+		{model: thoth.Model{CaseNameKey: "named"}, expected: "named"},
+		{model: thoth.Model{CaseNameKey: ""}, expected: "3"},
+		{model: thoth.Model{CaseNameKey: 12}, expected: "3"},
+	}
+
+	for _, testCase := range testCases {
+		if actual := caseID(3, testCase.model); actual != testCase.expected {
+			t.Errorf("model %v: expected %q, got %q", testCase.model, testCase.expected, actual)
+		}
+	}
+}
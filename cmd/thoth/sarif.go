@@ -0,0 +1,174 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifRootID  = "SRCROOT"
+
+	// RuleTemplateError identifies results for templates that could not be parsed.
+	RuleTemplateError = "thoth/template-error"
+
+	// RuleSampleFailure identifies results for samples that failed against a template.
+	RuleSampleFailure = "thoth/sample-failure"
+
+	// RuleWarning identifies results for warnings.
+	RuleWarning = "thoth/warning"
+)
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifRun struct {
+	Tool               sarifTool                        `json:"tool"`
+	OriginalURIBaseIDs map[string]sarifArtifactLocation `json:"originalUriBaseIds,omitempty"`
+	Results            []sarifResult                    `json:"results"`
+}
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+// SARIFLogger writes failures and warnings as a SARIF 2.1.0 log when closed.  Passing
+// templates and samples produce no SARIF results.  Locations are relative to Root.
+type SARIFLogger struct {
+	collector
+
+	// Root is the absolute system path of the directory that was scanned.
+	Root string
+}
+
+func (sl *SARIFLogger) newResult(ruleID, name string, err error) sarifResult {
+	// an error in an include or layout is located in that file rather than the template
+	loc := errorLocation(err)
+	pl := sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifactLocation{
+			URI:       loc.file(name),
+			URIBaseID: sarifRootID,
+		},
+	}
+
+	if loc.Line > 0 {
+		pl.Region = &sarifRegion{
+			StartLine:   loc.Line,
+			StartColumn: loc.Column,
+		}
+	}
+
+	return sarifResult{
+		RuleID:    ruleID,
+		Level:     "error",
+		Message:   sarifMessage{Text: err.Error()},
+		Locations: []sarifLocation{{PhysicalLocation: pl}},
+	}
+}
+
+func (sl *SARIFLogger) Close() error {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           "thoth",
+				InformationURI: "https://github.com/xmidt-org/thoth",
+				Rules: []sarifRule{
+					{ID: RuleTemplateError, ShortDescription: sarifMessage{Text: "template could not be parsed"}},
+					{ID: RuleSampleFailure, ShortDescription: sarifMessage{Text: "template failed for a sample model"}},
+					{ID: RuleWarning, ShortDescription: sarifMessage{Text: "possible problem with a template or sample"}},
+				},
+			},
+		},
+		Results: []sarifResult{},
+	}
+
+	if len(sl.Root) > 0 {
+		root := (&url.URL{Scheme: "file", Path: filepath.ToSlash(sl.Root)}).String()
+		if !strings.HasSuffix(root, "/") {
+			root += "/"
+		}
+
+		run.OriginalURIBaseIDs = map[string]sarifArtifactLocation{
+			sarifRootID: {URI: root},
+		}
+	}
+
+	for _, tr := range sl.results {
+		if tr.Err != nil {
+			run.Results = append(run.Results, sl.newResult(RuleTemplateError, tr.Name, tr.Err))
+		}
+
+		for _, sr := range tr.SampleResults {
+			if sr.Err != nil {
+				r := sl.newResult(RuleSampleFailure, tr.Name, sr.Err)
+				r.Message.Text = sr.Name + ": " + r.Message.Text
+				run.Results = append(run.Results, r)
+			}
+		}
+	}
+
+	for _, w := range sl.warnings {
+		r := sl.newResult(RuleWarning, w.Name, errors.New(w.Message))
+		r.Level = "warning"
+		run.Results = append(run.Results, r)
+	}
+
+	e := json.NewEncoder(sl.out())
+	e.SetIndent("", "  ")
+	return e.Encode(sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs:    []sarifRun{run},
+	})
+}
@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestSARIFLogger(t *testing.T) {
+	testCases := []struct {
+		name     string
+		root     string
+		expected string
+	}{
+		{name: "NoRoot"},
+		{name: "Root", root: "/src/templates", expected: "file:///src/templates/"},
+		{name: "TrailingSlash", root: "/src/", expected: "file:///src/"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var out bytes.Buffer
+			logTestResults(t, &SARIFLogger{collector: collector{Out: &out}, Root: testCase.root})
+
+			var log sarifLog
+			if err := json.Unmarshal(out.Bytes(), &log); err != nil {
+				t.Fatalf("invalid SARIF log: %s\n%s", err, out.String())
+			}
+
+			if log.Version != sarifVersion || len(log.Runs) != 1 {
+				t.Fatalf("unexpected SARIF log: %+v", log)
+			}
+
+			run := log.Runs[0]
+			if actual := run.OriginalURIBaseIDs[sarifRootID].URI; actual != testCase.expected {
+				t.Errorf("expected root %q, got %q", testCase.expected, actual)
+			}
+
+			expected := []struct {
+				ruleID string
+				level  string
+				uri    string
+				line   int
+				text   string
+			}{
+				{ruleID: RuleTemplateError, level: "error", uri: "bad.tmpl", line: 3, text: "template: bad.tmpl:3:14: unexpected EOF"},
+				{ruleID: RuleSampleFailure, level: "error", uri: "failing.tmpl", text: "failing.yaml#1: assertion failed: first line\nsecond line"},
+				{ruleID: RuleWarning, level: "warning", uri: "orphan.yaml", text: "sample does not match any template"},
+			}
+
+			if len(run.Results) != len(expected) {
+				t.Fatalf("expected %d results, got %+v", len(expected), run.Results)
+			}
+
+			for i, e := range expected {
+				r := run.Results[i]
+				pl := r.Locations[0].PhysicalLocation
+				if r.RuleID != e.ruleID || r.Level != e.level || r.Message.Text != e.text || pl.ArtifactLocation.URI != e.uri {
+					t.Errorf("expected %+v, got %+v", e, r)
+				}
+
+				var line int
+				if pl.Region != nil {
+					line = pl.Region.StartLine
+				}
+
+				if line != e.line {
+					t.Errorf("%s: expected line %d, got %d", e.uri, e.line, line)
+				}
+			}
+		})
+	}
+}
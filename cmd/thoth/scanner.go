@@ -5,22 +5,81 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	"io/fs"
+	"path"
 
 	"github.com/xmidt-org/thoth"
 )
 
+// parsed is the outcome of parsing a single template file.
+type parsed struct {
+	name     string
+	template thoth.Template
+	err      error
+}
+
 type Scanner struct {
 	Root     fs.FS
 	Selector thoth.Selector
+	Samples  thoth.Matcher
+	Golden   *Golden
 	Logger   Logger
+
+	// Schemas holds the optional input and output schemas for templates.  If nil,
+	// no schema checks are done.
+	Schemas *Schemas
+
+	// MaxFailures is the number of failures after which the scan stops.  If this
+	// field is nonpositive, the scan always runs to completion.
+	MaxFailures int
+
+	// WarningsAsErrors indicates that warnings count as failures.
+	WarningsAsErrors bool
+
+	// Validate indicates that rendered output is checked for well-formedness
+	// according to each template's media type.
+	Validate bool
+
+	// Seed, if set, gives each execution its own source of random values, derived
+	// from the seed and the names of the template and the case.  The random values
+	// for a case therefore don't depend on which other templates and samples exist.
+	Seed *int64
+}
+
+// isSample tests if the given path should be treated as a sample file.  Golden
+// files, assertion files, schema files, and configuration files are never samples.
+func (s Scanner) isSample(p string) bool {
+	return s.Samples != nil && s.Samples.Match(p) &&
+		!isGolden(p) && !isExpect(p) && !s.Schemas.Contains(p) && path.Base(p) != ConfigFileName
 }
 
-func (s Scanner) Scan() ([]thoth.Template, Samples, error) {
+// stop tests if enough failures have occurred to end the scan early.
+func (s Scanner) stop(summary Summary) bool {
+	return s.MaxFailures > 0 && summary.Failures(s.WarningsAsErrors) >= s.MaxFailures
+}
+
+// warn reports a warning through the Logger and tallies it.
+func (s Scanner) warn(summary *Summary, name, message string) {
+	summary.Warnings++
+	s.Logger.Warning(name, message)
+}
+
+// Scan walks the Root file system, parsing each template and noting each sample.
+// Once the walk is complete, each successfully parsed template is executed against
+// all of its samples.  The results for each template are sent to the Logger.
+//
+// If MaxFailures is positive, the scan stops as soon as that many failures
+// have occurred.  The returned Summary is also sent to the Logger.
+func (s Scanner) Scan() ([]thoth.Template, Summary, error) {
 	var (
 		buffer    = bytes.NewBuffer(make([]byte, 0, 1024))
-		samples   Samples
+		samples   = &Samples{Root: s.Root}
+		results   []parsed
 		templates []thoth.Template
+		summary   Summary
 	)
 
 	err := fs.WalkDir(s.Root, ".", func(path string, entry fs.DirEntry, walkErr error) error {
@@ -39,19 +98,143 @@ func (s Scanner) Scan() ([]thoth.Template, Samples, error) {
 					t, err = p.Parse(path, buffer.String())
 				}
 
-				s.Logger.Result(TemplateResult{
-					Name: path,
-					Err:  err,
+				results = append(results, parsed{
+					name:     path,
+					template: t,
+					err:      err,
 				})
 
 				if err == nil {
 					templates = append(templates, t)
+				} else {
+					// tally parse errors as we go, so that the walk can stop early
+					summary.Errors++
+					if s.stop(summary) {
+						return fs.SkipAll
+					}
 				}
+			} else if s.isSample(path) {
+				samples.Add(path)
 			}
 		}
 
 		return nil // always continue
 	})
 
-	return templates, samples, err
+	// the parse errors are tallied again as each result is reported
+	summary.Errors = 0
+	for i := 0; i < len(results) && !summary.Stopped; i++ {
+		r := results[i]
+		tr := TemplateResult{
+			Name: r.name,
+			Err:  r.err,
+		}
+
+		// samples for a template that failed to parse still match it, so that
+		// they aren't also reported as not matching any template
+		names := samples.Match(r.name)
+		if r.err == nil {
+			for j := 0; j < len(names) && !summary.Stopped; j++ {
+				tr.SampleResults = append(tr.SampleResults,
+					s.execute(buffer, r.template, samples, names[j], &summary)...,
+				)
+
+				// check the limit against the counts including this template's results so far
+				pending := summary
+				pending.add(tr)
+				summary.Stopped = s.stop(pending)
+			}
+		}
+
+		s.Logger.Result(tr)
+		summary.add(tr)
+		if r.err == nil && len(tr.SampleResults) == 0 && samples.Len() > 0 {
+			s.warn(&summary, r.name, "template has no samples")
+		}
+
+		summary.Stopped = summary.Stopped || s.stop(summary)
+	}
+
+	if !summary.Stopped {
+		for _, name := range samples.Unmatched() {
+			s.warn(&summary, name, "sample does not match any template")
+		}
+	}
+
+	summary.Stopped = summary.Stopped || len(results) > summary.Templates
+	s.Logger.Summary(summary)
+	return templates, summary, err
+}
+
+// executeOptions returns the options for executing a template with a model.  When
+// there is a seed, the execution gets its own source of random values derived from
+// the seed and the names of the template and the model.
+func executeOptions(seed *int64, template, model string) []thoth.ExecuteOption {
+	if seed == nil {
+		return nil
+	}
+
+	return []thoth.ExecuteOption{
+		thoth.WithEntropy(thoth.SeededEntropy(*seed, template, model)),
+	}
+}
+
+// execute renders a template using each case in a sample file.  Each case's model is
+// checked against the template's input schema, if any, before rendering.  The output is
+// validated against the template's media type if so configured, and against the template's
+// output schema, if any.  Each case's assertions are checked, and if golden files are
+// configured, the rendered output of each case is checked against its golden file.  Cases
+// that expect an error have no golden file.  A missing golden file is a warning.
+func (s Scanner) execute(buffer *bytes.Buffer, t thoth.Template, samples *Samples, name string, summary *Summary) []SampleResult {
+	cases, err := samples.Load(name)
+	if err != nil {
+		return []SampleResult{{Name: name, Err: err}}
+	}
+
+	input, output := s.Schemas.Select(t.Name())
+	results := make([]SampleResult, 0, len(cases))
+	for _, c := range cases {
+		if input != nil {
+			// a model that violates its contract is a broken sample, which no assertion can expect
+			if err := input.Validate(c.Model); err != nil {
+				results = append(results, SampleResult{
+					Name: c.Name(),
+					Err:  fmt.Errorf("invalid sample model: %w", err),
+				})
+
+				continue
+			}
+		}
+
+		buffer.Reset()
+		err := thoth.ExecuteWith(context.Background(), t, buffer, c.Model, executeOptions(s.Seed, t.Name(), c.Name())...)
+		if err == nil && s.Validate {
+			err = thoth.Validate(thoth.MediaType(t), buffer.Bytes())
+		}
+
+		if err == nil && output != nil {
+			if err = output.ValidateOutput(thoth.MediaType(t), buffer.Bytes()); err != nil {
+				err = fmt.Errorf("invalid output: %w", err)
+			}
+		}
+
+		// validation errors are rendering errors, so cases may expect them
+		err = c.Expect.Check(buffer.Bytes(), err)
+		if err == nil && s.Golden != nil && (c.Expect == nil || len(c.Expect.Error) == 0) {
+			err = s.Golden.Check(goldenName(t, c), buffer.Bytes())
+		}
+
+		var mge *MissingGoldenError
+		if errors.As(err, &mge) {
+			s.warn(summary, c.Name(), mge.Error())
+			err = nil
+		}
+
+		results = append(results, SampleResult{
+			Name: c.Name(),
+			Err:  err,
+		})
+	}
+
+	return results
 }
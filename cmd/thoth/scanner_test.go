@@ -0,0 +1,267 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/xmidt-org/thoth"
+)
+
+// testLogger records everything sent to it.
+type testLogger struct {
+	results  []TemplateResult
+	warnings []string
+	summary  Summary
+	closed   bool
+}
+
+func (tl *testLogger) Debugf(string, ...interface{}) error { return nil }
+func (tl *testLogger) Errorf(string, ...interface{}) error { return nil }
+
+func (tl *testLogger) Result(tr TemplateResult) error {
+	tl.results = append(tl.results, tr)
+	return nil
+}
+
+func (tl *testLogger) Warning(name, message string) error {
+	tl.warnings = append(tl.warnings, name+": "+message)
+	return nil
+}
+
+func (tl *testLogger) Summary(s Summary) error {
+	tl.summary = s
+	return nil
+}
+
+func (tl *testLogger) Close() error {
+	tl.closed = true
+	return nil
+}
+
+// sampleErrors returns the outcome of each sample execution, keyed by
+// template and sample name.  Passing samples map to the empty string.
+func (tl *testLogger) sampleErrors() map[string]string {
+	m := make(map[string]string)
+	for _, tr := range tl.results {
+		for _, sr := range tr.SampleResults {
+			m[tr.Name+" "+sr.Name] = errorText(sr.Err)
+		}
+	}
+
+	return m
+}
+
+// newTestScanner creates a Scanner for .tmpl templates and .yaml or .json samples.
+func newTestScanner(t *testing.T, fsys fstest.MapFS) (Scanner, *testLogger) {
+	selector, err := thoth.NewSelector(thoth.SelectorConfig{
+		Patterns: []string{"*.tmpl", "*/*.tmpl"},
+	})
+
+	if err != nil {
+		t.Fatalf("unable to create selector: %s", err)
+	}
+
+	samples, err := thoth.ParsePatterns("*.yaml", "*.json", "*/*.yaml")
+	if err != nil {
+		t.Fatalf("unable to parse sample patterns: %s", err)
+	}
+
+	l := new(testLogger)
+	return Scanner{
+		Root:     fsys,
+		Selector: selector,
+		Samples:  samples,
+		Logger:   l,
+	}, l
+}
+
+func TestScannerScan(t *testing.T) {
+	fsys := fstest.MapFS{
+		"hello.tmpl":     {Data: []byte("hello {{.name}}")},
+		"hello.yaml":     {Data: []byte("name: world\n")},
+		"hello.bad.json": {Data: []byte(`{"name": `)},
+		"fail.tmpl":      {Data: []byte("{{.value.missing}}")},
+		"fail.yaml":      {Data: []byte("value: 1\n")},
+		"broken.tmpl":    {Data: []byte("{{.unclosed")},
+		"broken.yaml":    {Data: []byte("name: broken\n")},
+		"lonely.tmpl":    {Data: []byte("no samples")},
+		"orphan.yaml":    {Data: []byte("name: nobody\n")},
+		"sub/hello.tmpl": {Data: []byte("{{.name}}")},
+		"sub/hello.yaml": {Data: []byte("name: sub\n")},
+	}
+
+	s, l := newTestScanner(t, fsys)
+	templates, summary, err := s.Scan()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(templates) != 4 {
+		t.Errorf("expected 4 parsed templates, got %d", len(templates))
+	}
+
+	expected := Summary{
+		Templates: 5,
+		Samples:   4,
+		Passed:    2,
+		Failed:    2,
+		Errors:    1,
+		Warnings:  2,
+	}
+
+	if summary != expected {
+		t.Errorf("expected summary %+v, got %+v", expected, summary)
+	}
+
+	if l.summary != summary {
+		t.Errorf("the logger received summary %+v, expected %+v", l.summary, summary)
+	}
+
+	testCases := []struct {
+		name string
+		err  string
+	}{
+		{name: "hello.tmpl hello.yaml"},
+		{name: "hello.tmpl hello.bad.json", err: "unexpected EOF"},
+		{name: "fail.tmpl fail.yaml", err: "can't evaluate field missing"},
+		{name: "sub/hello.tmpl sub/hello.yaml"},
+	}
+
+	outcomes := l.sampleErrors()
+	for _, testCase := range testCases {
+		actual, ok := outcomes[testCase.name]
+		switch {
+		case !ok:
+			t.Errorf("%s: not executed", testCase.name)
+
+		case len(testCase.err) == 0 && len(actual) > 0:
+			t.Errorf("%s: unexpected error: %s", testCase.name, actual)
+
+		case !strings.Contains(actual, testCase.err):
+			t.Errorf("%s: expected an error containing %q, got %q", testCase.name, testCase.err, actual)
+		}
+	}
+
+	for _, tr := range l.results {
+		if tr.Name == "broken.tmpl" && tr.Err == nil {
+			t.Error("expected broken.tmpl to fail parsing")
+		}
+	}
+
+	// the sample for broken.tmpl matches it, even though it can't be executed
+	expectedWarnings := []string{
+		"lonely.tmpl: template has no samples",
+		"orphan.yaml: sample does not match any template",
+	}
+
+	if fmt.Sprint(l.warnings) != fmt.Sprint(expectedWarnings) {
+		t.Errorf("expected warnings %q, got %q", expectedWarnings, l.warnings)
+	}
+}
+
+func TestScannerMaxFailures(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.tmpl": {Data: []byte("{{.value.missing}}")},
+		"a.yaml": {Data: []byte("value: 1\n---\nvalue: 2\n")},
+		"b.tmpl": {Data: []byte("{{.broken")},
+		"c.tmpl": {Data: []byte("{{.value.missing}}")},
+		"c.yaml": {Data: []byte("value: 1\n")},
+		"d.tmpl": {Data: []byte("{{.value}}")},
+		"d.yaml": {Data: []byte("value: 1\n")},
+	}
+
+	testCases := []struct {
+		name        string
+		maxFailures int
+		expected    Summary
+	}{
+		{
+			name:     "Unlimited",
+			expected: Summary{Templates: 4, Samples: 4, Passed: 1, Failed: 3, Errors: 1},
+		},
+		{
+			// the limit is checked after each sample file, so every case in a.yaml runs
+			name:        "StopWithinTemplate",
+			maxFailures: 1,
+			expected:    Summary{Templates: 1, Samples: 2, Failed: 2, Stopped: true},
+		},
+		{
+			name:        "StopAfterParseError",
+			maxFailures: 3,
+			expected:    Summary{Templates: 2, Samples: 2, Failed: 2, Errors: 1, Stopped: true},
+		},
+		{
+			name:        "NotReached",
+			maxFailures: 10,
+			expected:    Summary{Templates: 4, Samples: 4, Passed: 1, Failed: 3, Errors: 1},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			s, l := newTestScanner(t, fsys)
+			s.MaxFailures = testCase.maxFailures
+			_, summary, err := s.Scan()
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if summary != testCase.expected {
+				t.Errorf("expected %+v, got %+v", testCase.expected, summary)
+			}
+
+			if len(l.results) != summary.Templates {
+				t.Errorf("expected %d reported templates, got %d", summary.Templates, len(l.results))
+			}
+		})
+	}
+}
+
+func TestScannerCases(t *testing.T) {
+	fsys := fstest.MapFS{
+		"hello.tmpl":  {Data: []byte(`{"hello": {{.value.inner}}}`)},
+		"hello.yaml":  {Data: []byte("name: first\nvalue: {inner: 1}\n---\nvalue: 2\n---\nname: third\nvalue: {inner: 3}\n")},
+		"hello.json":  {Data: []byte(`[{"value": {"inner": 4}}, {"name": "fifth", "value": {"inner": 5}}]`)},
+		"single.tmpl": {Data: []byte(`{}`)},
+		"single.yaml": {Data: []byte("value: 1\n")},
+	}
+
+	s, l := newTestScanner(t, fsys)
+	_, summary, err := s.Scan()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if summary.Samples != 6 || summary.Passed != 5 || summary.Failed != 1 {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+
+	testCases := []struct {
+		name   string
+		failed bool
+	}{
+		{name: "hello.tmpl hello.json#0"},
+		{name: "hello.tmpl hello.json#fifth"},
+		{name: "hello.tmpl hello.yaml#first"},
+		{name: "hello.tmpl hello.yaml#1", failed: true},
+		{name: "hello.tmpl hello.yaml#third"},
+		{name: "single.tmpl single.yaml"},
+	}
+
+	outcomes := l.sampleErrors()
+	if len(outcomes) != len(testCases) {
+		t.Errorf("expected %d outcomes, got %v", len(testCases), outcomes)
+	}
+
+	for _, testCase := range testCases {
+		actual, ok := outcomes[testCase.name]
+		if !ok || testCase.failed != (len(actual) > 0) {
+			t.Errorf("%s: unexpected outcome %q", testCase.name, actual)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"io/fs"
+	"path"
+
+	"github.com/xmidt-org/thoth"
+)
+
+type schemaEntry struct {
+	m      thoth.Matcher
+	input  *thoth.Schema
+	output *thoth.Schema
+}
+
+// Schemas holds the compiled input and output schemas for templates.  Templates
+// are matched using the same patterns, in the same order, as the Selector.
+type Schemas struct {
+	entries []schemaEntry
+	names   map[string]bool
+}
+
+// newSchemas compiles the schemas referenced by the given configurations.  Each
+// schema file is compiled only once, no matter how many configurations use it.
+func newSchemas(root fs.FS, configs []thoth.SelectorConfig) (*Schemas, error) {
+	var (
+		s = &Schemas{
+			entries: make([]schemaEntry, len(configs)),
+			names:   make(map[string]bool),
+		}
+		compiled = make(map[string]*thoth.Schema)
+	)
+
+	compile := func(name string) (schema *thoth.Schema, err error) {
+		if len(name) == 0 {
+			return
+		}
+
+		schema, found := compiled[name]
+		if !found {
+			schema, err = thoth.CompileSchema(root, name)
+			compiled[name] = schema
+			s.names[path.Clean(name)] = true
+		}
+
+		return
+	}
+
+	for i, c := range configs {
+		var err error
+		s.entries[i].m, err = thoth.ParsePatterns(c.Patterns...)
+		if err == nil {
+			s.entries[i].input, err = compile(c.InputSchema)
+		}
+
+		if err == nil {
+			s.entries[i].output, err = compile(c.OutputSchema)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// Select returns the input and output schemas for a template.  Either or both
+// schemas may be nil.
+func (s *Schemas) Select(name string) (input, output *thoth.Schema) {
+	if s == nil {
+		return
+	}
+
+	for _, e := range s.entries {
+		if e.m.Match(name) {
+			input, output = e.input, e.output
+			break
+		}
+	}
+
+	return
+}
+
+// Contains tests if the given file is one of the schemas.  Schema files are
+// never samples, even if they match the sample patterns.
+func (s *Schemas) Contains(name string) bool {
+	return s != nil && s.names[path.Clean(name)]
+}
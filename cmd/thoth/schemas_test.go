@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/xmidt-org/thoth"
+)
+
+var testSchemaFS = fstest.MapFS{
+	"input.json":  {Data: []byte(`{"type": "object", "required": ["id"]}`)},
+	"output.yaml": {Data: []byte("type: object\nrequired: [device]\n")},
+}
+
+func TestSchemasSelect(t *testing.T) {
+	s, err := newSchemas(testSchemaFS, []thoth.SelectorConfig{
+		{Patterns: []string{"both.tmpl"}, InputSchema: "input.json", OutputSchema: "output.yaml"},
+		{Patterns: []string{"input.tmpl", "*.in.tmpl"}, InputSchema: "input.json"},
+		{Patterns: []string{"*.tmpl"}},
+	})
+
+	if err != nil {
+		t.Fatalf("unable to compile schemas: %s", err)
+	}
+
+	testCases := []struct {
+		name   string
+		input  string
+		output string
+	}{
+		{name: "both.tmpl", input: "input.json", output: "output.yaml"},
+		{name: "input.tmpl", input: "input.json"},
+		{name: "x.in.tmpl", input: "input.json"},
+		{name: "other.tmpl"},
+		{name: "unmatched.txt"},
+	}
+
+	for _, testCase := range testCases {
+		input, output := s.Select(testCase.name)
+		if name := schemaName(input); name != testCase.input {
+			t.Errorf("%s: expected input schema %q, got %q", testCase.name, testCase.input, name)
+		}
+
+		if name := schemaName(output); name != testCase.output {
+			t.Errorf("%s: expected output schema %q, got %q", testCase.name, testCase.output, name)
+		}
+	}
+
+	for name, expected := range map[string]bool{"input.json": true, "./output.yaml": true, "both.tmpl": false} {
+		if actual := s.Contains(name); actual != expected {
+			t.Errorf("%s: expected Contains to return %t", name, expected)
+		}
+	}
+
+	var none *Schemas
+	if input, output := none.Select("both.tmpl"); input != nil || output != nil {
+		t.Error("nil Schemas should select nothing")
+	}
+
+	if none.Contains("input.json") {
+		t.Error("nil Schemas should contain nothing")
+	}
+}
+
+// schemaName returns the name of a possibly nil schema.
+func schemaName(s *thoth.Schema) string {
+	if s == nil {
+		return ""
+	}
+
+	return s.Name()
+}
+
+func TestNewSchemasErrors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config thoth.SelectorConfig
+	}{
+		{name: "MissingInput", config: thoth.SelectorConfig{Patterns: []string{"*"}, InputSchema: "missing.json"}},
+		{name: "MissingOutput", config: thoth.SelectorConfig{Patterns: []string{"*"}, OutputSchema: "missing.json"}},
+		{name: "BadPattern", config: thoth.SelectorConfig{Patterns: []string{"[a"}}},
+	}
+
+	for _, testCase := range testCases {
+		if _, err := newSchemas(testSchemaFS, []thoth.SelectorConfig{testCase.config}); err == nil {
+			t.Errorf("%s: expected an error", testCase.name)
+		}
+	}
+}
+
+func TestScannerSchemas(t *testing.T) {
+	fsys := fstest.MapFS{
+		"input.json":  testSchemaFS["input.json"],
+		"output.yaml": testSchemaFS["output.yaml"],
+		"device.tmpl": {Data: []byte(`{{if .wrap}}{"device": "{{.id}}"}{{else}}{"id": "{{.id}}"}{{end}}`)},
+		"device.yaml": {Data: []byte("name: valid\nid: a\nwrap: true\n---\nname: badInput\nwrap: true\n---\nname: badOutput\nid: a\nwrap: false\n---\nname: expected\nid: a\nwrap: false\nexpect: {error: violates schema}\n")},
+	}
+
+	configs := []thoth.SelectorConfig{
+		{Patterns: []string{"*.tmpl"}, InputSchema: "input.json", OutputSchema: "output.yaml"},
+	}
+
+	schemas, err := newSchemas(fsys, configs)
+	if err != nil {
+		t.Fatalf("unable to compile schemas: %s", err)
+	}
+
+	s, l := newTestScanner(t, fsys)
+	s.Schemas = schemas
+	if _, _, err := s.Scan(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	testCases := []struct {
+		name string
+		err  string
+	}{
+		{name: "device.tmpl device.yaml#valid"},
+		{name: "device.tmpl device.yaml#badInput", err: "invalid sample model: violates schema input.json: #: missing property 'id'"},
+		{name: "device.tmpl device.yaml#badOutput", err: "invalid output: violates schema output.yaml: #: missing property 'device'"},
+		{name: "device.tmpl device.yaml#expected"},
+	}
+
+	outcomes := l.sampleErrors()
+	for _, testCase := range testCases {
+		actual, ok := outcomes[testCase.name]
+		switch {
+		case !ok:
+			t.Errorf("%s: not executed", testCase.name)
+
+		case len(testCase.err) == 0 && len(actual) > 0:
+			t.Errorf("%s: unexpected error: %s", testCase.name, actual)
+
+		case !strings.Contains(actual, testCase.err):
+			t.Errorf("%s: expected an error containing %q, got %q", testCase.name, testCase.err, actual)
+		}
+	}
+
+	// the schema files match the sample patterns, but they are not samples
+	if len(l.warnings) > 0 {
+		t.Errorf("unexpected warnings: %q", l.warnings)
+	}
+}
@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/xmidt-org/thoth"
+)
+
+// shutdownTimeout is how long the server waits for in-flight requests when stopping.
+const shutdownTimeout = 10 * time.Second
+
+// ServeCmd is the command that runs an HTTP rendering service for every
+// template under the root.
+type ServeCmd struct {
+	Address     string `optional:"true" default:":8080" name:"address" short:"a" help:"address to listen on"`
+	MaxBodySize int64  `optional:"true" default:"1048576" name:"max-body-size" help:"maximum size in bytes of a model body"`
+
+	Reload time.Duration `optional:"true" default:"0s" name:"reload" help:"interval for polling the root for template changes, or 0 to disable reloading"`
+}
+
+// newRegistry loads the templates to serve.  When reloading is disabled, templates that
+// fail to parse are reported but do not prevent the remaining templates from being served.
+// When reloading is enabled, every template must parse before the server starts, and each
+// reload parses includes and layouts again.
+func (sc ServeCmd) newRegistry(cli CLI, l Logger, configs []thoth.SelectorConfig) (func() *thoth.Registry, *thoth.Reloader, error) {
+	fsys := os.DirFS(cli.Root)
+	if sc.Reload <= 0 {
+		selector, err := thoth.NewSelector(configs...)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		registry, err := thoth.NewRegistry(fsys, selector)
+		if err != nil {
+			l.Errorf("%s", err)
+		}
+
+		return func() *thoth.Registry { return registry }, nil, nil
+	}
+
+	reloader, err := thoth.NewReloader(thoth.ReloaderConfig{
+		FS:       fsys,
+		Configs:  configs,
+		Interval: sc.Reload,
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	go func() {
+		for e := range reloader.Events() {
+			if e.Err != nil {
+				l.Errorf("reload rejected, keeping %d existing templates: %s", e.Registry.Len(), e.Err)
+			} else {
+				l.Debugf("reloaded %d templates", e.Registry.Len())
+			}
+		}
+	}()
+
+	reloader.Start()
+	return reloader.Current, reloader, nil
+}
+
+func (sc ServeCmd) run(cli CLI) (int, error) {
+	l := &ConsoleLogger{
+		Out:     os.Stderr,
+		Err:     os.Stderr,
+		Verbose: cli.Verbose,
+	}
+
+	cfg, err := loadConfig(cli, l)
+	if err != nil {
+		return ExitBadConfig, err
+	}
+
+	registry, reloader, err := sc.newRegistry(cli, l, selectorConfigs(cli, cfg))
+	if err != nil {
+		return ExitBadConfig, err
+	}
+
+	if reloader != nil {
+		defer reloader.Stop()
+	}
+
+	s := &Server{
+		Registry:    registry,
+		MaxBodySize: sc.MaxBodySize,
+	}
+
+	server := &http.Server{
+		Addr:              sc.Address,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	l.Debugf("serving %d templates on %s", registry().Len(), sc.Address)
+	if err = server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return ExitServeFailed, err
+	}
+
+	return 0, nil
+}
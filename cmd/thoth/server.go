@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/xmidt-org/thoth"
+)
+
+// DefaultMaxBodySize is the default limit on the size of model bodies POSTed
+// to the Server.
+const DefaultMaxBodySize = 1 << 20
+
+// ErrNoSuchTemplate is returned to clients that request a template that does not exist.
+var ErrNoSuchTemplate = errors.New("no such template")
+
+// catalogEntry describes a single template in the GET /templates response.
+type catalogEntry struct {
+	Name      string `json:"name"`
+	MediaType string `json:"mediaType"`
+}
+
+// Server is an HTTP rendering service for the templates in a Registry.
+type Server struct {
+	// Registry returns the templates this Server renders.  This function is called
+	// once per request, so each request sees a consistent set of templates even
+	// when templates are being reloaded.
+	Registry func() *thoth.Registry
+
+	// MaxBodySize is the maximum size of a model body.  If unset,
+	// DefaultMaxBodySize is used.
+	MaxBodySize int64
+}
+
+// Handler returns the http.Handler that exposes this Server's endpoints:
+//
+//	GET  /health           reports that the server is up
+//	GET  /templates        lists the available templates
+//	POST /templates/{name} renders a template using the JSON model in the request body
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", s.health)
+	mux.HandleFunc("GET /templates", s.catalog)
+	mux.HandleFunc("POST /templates/{name...}", s.render)
+	return mux
+}
+
+func writeJSON(response http.ResponseWriter, code int, v interface{}) {
+	response.Header().Set("Content-Type", "application/json")
+	response.WriteHeader(code)
+	json.NewEncoder(response).Encode(v)
+}
+
+func writeError(response http.ResponseWriter, code int, err error) {
+	writeJSON(response, code, map[string]string{"error": err.Error()})
+}
+
+func (s *Server) health(response http.ResponseWriter, _ *http.Request) {
+	writeJSON(response, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) catalog(response http.ResponseWriter, _ *http.Request) {
+	registry := s.Registry()
+	names := registry.Names()
+	entries := make([]catalogEntry, 0, len(names))
+	for _, n := range names {
+		t, _ := registry.Lookup(n)
+		entries = append(entries, catalogEntry{
+			Name:      n,
+			MediaType: thoth.MediaType(t),
+		})
+	}
+
+	writeJSON(response, http.StatusOK, entries)
+}
+
+func (s *Server) render(response http.ResponseWriter, request *http.Request) {
+	t, found := s.Registry().Lookup(request.PathValue("name"))
+	if !found {
+		writeError(response, http.StatusNotFound, ErrNoSuchTemplate)
+		return
+	}
+
+	maxBodySize := s.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = DefaultMaxBodySize
+	}
+
+	// decode the same way as sample files, so templates see the same types
+	m, err := thoth.DecodeModel(http.MaxBytesReader(response, request.Body, maxBodySize), thoth.FormatJSON)
+	if err != nil {
+		writeError(response, http.StatusBadRequest, err)
+		return
+	}
+
+	// rendering stops if the client goes away
+	var output bytes.Buffer
+	if err := thoth.ExecuteContext(request.Context(), t, &output, m); err != nil {
+		writeError(response, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	response.Header().Set("Content-Type", thoth.MediaType(t))
+	response.WriteHeader(http.StatusOK)
+	response.Write(output.Bytes())
+}
@@ -0,0 +1,191 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/xmidt-org/thoth"
+)
+
+func newTestServer(t *testing.T) *Server {
+	selector, err := thoth.NewSelector(
+		thoth.SelectorConfig{
+			Patterns: []string{"*.html.tmpl"},
+			Parser:   thoth.ParserConfig{HTML: true, MediaType: "text/html"},
+		},
+		thoth.SelectorConfig{
+			Patterns: []string{"*.tmpl", "*/*.tmpl"},
+		},
+	)
+
+	if err != nil {
+		t.Fatalf("unable to create selector: %s", err)
+	}
+
+	registry, err := thoth.NewRegistry(fstest.MapFS{
+		"hello.tmpl":      {Data: []byte(`{"hello": "{{.name}}"}`)},
+		"page.html.tmpl":  {Data: []byte(`<p>{{.name}}</p>`)},
+		"types.tmpl":      {Data: []byte(`{{printf "%T %T %T" .i .f .m}}`)},
+		"dir/nested.tmpl": {Data: []byte(`{{.name}}`)},
+	}, selector)
+
+	if err != nil {
+		t.Fatalf("unable to create registry: %s", err)
+	}
+
+	return &Server{
+		Registry:    func() *thoth.Registry { return registry },
+		MaxBodySize: 64,
+	}
+}
+
+func TestServerHealth(t *testing.T) {
+	response := httptest.NewRecorder()
+	newTestServer(t).Handler().ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if response.Code != http.StatusOK || !strings.Contains(response.Body.String(), `"ok"`) {
+		t.Errorf("unexpected health response: %d %s", response.Code, response.Body)
+	}
+}
+
+func TestServerCatalog(t *testing.T) {
+	response := httptest.NewRecorder()
+	newTestServer(t).Handler().ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/templates", nil))
+	if response.Code != http.StatusOK {
+		t.Fatalf("unexpected status: %d", response.Code)
+	}
+
+	var entries []catalogEntry
+	if err := json.Unmarshal(response.Body.Bytes(), &entries); err != nil {
+		t.Fatalf("invalid catalog: %s", err)
+	}
+
+	expected := []catalogEntry{
+		{Name: "dir/nested.tmpl", MediaType: thoth.DefaultMediaType},
+		{Name: "hello.tmpl", MediaType: thoth.DefaultMediaType},
+		{Name: "page.html.tmpl", MediaType: "text/html"},
+		{Name: "types.tmpl", MediaType: thoth.DefaultMediaType},
+	}
+
+	if !reflect.DeepEqual(expected, entries) {
+		t.Errorf("expected %+v, got %+v", expected, entries)
+	}
+}
+
+func TestServerRender(t *testing.T) {
+	testCases := []struct {
+		name        string
+		path        string
+		body        string
+		code        int
+		contentType string
+		expected    string
+	}{
+		{
+			name:        "JSON",
+			path:        "/templates/hello.tmpl",
+			body:        `{"name": "world"}`,
+			code:        http.StatusOK,
+			contentType: thoth.DefaultMediaType,
+			expected:    `{"hello": "world"}`,
+		},
+		{
+			name:        "HTML",
+			path:        "/templates/page.html.tmpl",
+			body:        `{"name": "<b>"}`,
+			code:        http.StatusOK,
+			contentType: "text/html",
+			expected:    `<p>&lt;b&gt;</p>`,
+		},
+		{
+			name:        "Nested",
+			path:        "/templates/dir/nested.tmpl",
+			body:        `{"name": "nested"}`,
+			code:        http.StatusOK,
+			contentType: thoth.DefaultMediaType,
+			expected:    `nested`,
+		},
+		{
+			// models are decoded like sample files
+			name:        "Types",
+			path:        "/templates/types.tmpl",
+			body:        `{"i": 1, "f": 1.5, "m": {}}`,
+			code:        http.StatusOK,
+			contentType: thoth.DefaultMediaType,
+			expected:    `int64 float64 map[string]interface {}`,
+		},
+		{
+			name:     "NotFound",
+			path:     "/templates/missing.tmpl",
+			body:     `{}`,
+			code:     http.StatusNotFound,
+			expected: ErrNoSuchTemplate.Error(),
+		},
+		{
+			name:     "InvalidBody",
+			path:     "/templates/hello.tmpl",
+			body:     `{"name": `,
+			code:     http.StatusBadRequest,
+			expected: "error",
+		},
+		{
+			name:     "MultipleModels",
+			path:     "/templates/hello.tmpl",
+			body:     `[{}, {}]`,
+			code:     http.StatusBadRequest,
+			expected: "expected exactly one model",
+		},
+		{
+			name:     "TooLarge",
+			path:     "/templates/hello.tmpl",
+			body:     `{"name": "` + strings.Repeat("x", 100) + `"}`,
+			code:     http.StatusBadRequest,
+			expected: "too large",
+		},
+		{
+			name:     "ExecutionFailed",
+			path:     "/templates/hello.tmpl",
+			code:     http.StatusUnprocessableEntity,
+			expected: "map has no entry for key",
+		},
+	}
+
+	handler := newTestServer(t).Handler()
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			handler.ServeHTTP(response, httptest.NewRequest(http.MethodPost, testCase.path, strings.NewReader(testCase.body)))
+			if response.Code != testCase.code {
+				t.Errorf("expected status %d, got %d: %s", testCase.code, response.Code, response.Body)
+			}
+
+			if len(testCase.contentType) > 0 && response.Header().Get("Content-Type") != testCase.contentType {
+				t.Errorf("expected content type %s, got %s", testCase.contentType, response.Header().Get("Content-Type"))
+			}
+
+			if !strings.Contains(response.Body.String(), testCase.expected) {
+				t.Errorf("expected a body containing %q, got %q", testCase.expected, response.Body)
+			}
+		})
+	}
+}
+
+func TestServerRenderCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	request := httptest.NewRequest(http.MethodPost, "/templates/hello.tmpl", strings.NewReader(`{}`)).WithContext(ctx)
+	response := httptest.NewRecorder()
+	newTestServer(t).Handler().ServeHTTP(response, request)
+	if response.Code != http.StatusUnprocessableEntity || !strings.Contains(response.Body.String(), context.Canceled.Error()) {
+		t.Errorf("expected the canceled request to fail, got %d %s", response.Code, response.Body)
+	}
+}
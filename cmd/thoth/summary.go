@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import "fmt"
+
+// Summary holds the counts for an entire run.
+type Summary struct {
+	// Templates is the number of template files that were found.
+	Templates int `json:"templates"`
+
+	// Samples is the number of sample executions.
+	Samples int `json:"samples"`
+
+	// Passed is the number of sample executions that succeeded.
+	Passed int `json:"passed"`
+
+	// Failed is the number of sample executions that failed.
+	Failed int `json:"failed"`
+
+	// Errors is the number of templates that could not be parsed.
+	Errors int `json:"errors"`
+
+	// Warnings is the number of problems that do not, by default, fail a run.
+	Warnings int `json:"warnings"`
+
+	// Stopped indicates that the run ended early because too many failures occurred.
+	Stopped bool `json:"stopped,omitempty"`
+}
+
+// Failures returns the number of problems that fail the run.  If warningsAsErrors
+// is set, warnings are included in the count.
+func (s Summary) Failures(warningsAsErrors bool) int {
+	n := s.Failed + s.Errors
+	if warningsAsErrors {
+		n += s.Warnings
+	}
+
+	return n
+}
+
+// add tallies a single template result.
+func (s *Summary) add(tr TemplateResult) {
+	s.Templates++
+	if tr.Err != nil {
+		s.Errors++
+	}
+
+	for _, sr := range tr.SampleResults {
+		s.Samples++
+		if sr.Err != nil {
+			s.Failed++
+		} else {
+			s.Passed++
+		}
+	}
+}
+
+// String returns the one line, human-readable form of this summary.
+func (s Summary) String() string {
+	text := fmt.Sprintf(
+		"%d templates, %d samples: %d passed, %d failed, %d errors, %d warnings",
+		s.Templates, s.Samples, s.Passed, s.Failed, s.Errors, s.Warnings,
+	)
+
+	if s.Stopped {
+		text += " (stopped early)"
+	}
+
+	return text
+}
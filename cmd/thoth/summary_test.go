@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSummaryAdd(t *testing.T) {
+	var s Summary
+	s.add(TemplateResult{Name: "broken.tmpl", Err: errors.New("parse error")})
+	s.add(TemplateResult{
+		Name: "good.tmpl",
+		SampleResults: []SampleResult{
+			{Name: "a.yaml"},
+			{Name: "b.yaml", Err: errors.New("failed")},
+			{Name: "c.yaml"},
+		},
+	})
+
+	expected := Summary{Templates: 2, Samples: 3, Passed: 2, Failed: 1, Errors: 1}
+	if s != expected {
+		t.Errorf("expected %+v, got %+v", expected, s)
+	}
+}
+
+func TestSummaryFailures(t *testing.T) {
+	testCases := []struct {
+		name             string
+		summary          Summary
+		warningsAsErrors bool
+		expected         int
+	}{
+		{name: "Clean", summary: Summary{Templates: 1, Samples: 1, Passed: 1}},
+		{name: "Failed", summary: Summary{Failed: 2, Errors: 1}, expected: 3},
+		{name: "Warnings", summary: Summary{Warnings: 2}},
+		{name: "WarningsAsErrors", summary: Summary{Failed: 1, Warnings: 2}, warningsAsErrors: true, expected: 3},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if actual := testCase.summary.Failures(testCase.warningsAsErrors); actual != testCase.expected {
+				t.Errorf("expected %d, got %d", testCase.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSummaryString(t *testing.T) {
+	testCases := []struct {
+		summary  Summary
+		expected string
+	}{
+		{
+			summary:  Summary{Templates: 2, Samples: 3, Passed: 2, Failed: 1, Errors: 1, Warnings: 4},
+			expected: "2 templates, 3 samples: 2 passed, 1 failed, 1 errors, 4 warnings",
+		},
+		{
+			summary:  Summary{Templates: 1, Failed: 1, Stopped: true},
+			expected: "1 templates, 0 samples: 0 passed, 1 failed, 0 errors, 0 warnings (stopped early)",
+		},
+	}
+
+	for _, testCase := range testCases {
+		if actual := testCase.summary.String(); actual != testCase.expected {
+			t.Errorf("expected %q, got %q", testCase.expected, actual)
+		}
+	}
+}
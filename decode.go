@@ -0,0 +1,379 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// FormatJSON is the format for JSON model data.  The data may be a single object,
+	// an array of objects, or a sequence of either, such as newline-delimited JSON.
+	FormatJSON = "json"
+
+	// FormatYAML is the format for YAML model data.  The data may be a stream of
+	// documents separated by "---".
+	FormatYAML = "yaml"
+
+	// FormatTOML is the format for TOML model data.
+	FormatTOML = "toml"
+
+	// FormatDotenv is the format for .env files, which contain KEY=VALUE lines.
+	FormatDotenv = "dotenv"
+
+	// FormatCSV is the format for comma-separated values.  The first row is
+	// the header, and each subsequent row is a separate Model.
+	FormatCSV = "csv"
+)
+
+// UnsupportedFormatError indicates that model data was in an unrecognized format.
+type UnsupportedFormatError struct {
+	Format string
+}
+
+// Error satisfies the error interface.
+func (ufe *UnsupportedFormatError) Error() string {
+	return fmt.Sprintf("%s is not a supported model format", ufe.Format)
+}
+
+// ModelCountError indicates that data expected to hold exactly one Model did not.
+type ModelCountError struct {
+	Count int
+}
+
+// Error satisfies the error interface.
+func (mce *ModelCountError) Error() string {
+	return fmt.Sprintf("expected exactly one model, but found %d", mce.Count)
+}
+
+// FormatOf determines the model format from a file name's extension.  Files named
+// ".env", or with a ".env" extension, are in FormatDotenv.  Newline-delimited JSON
+// files, with a ".ndjson" or ".jsonl" extension, are in FormatJSON.  If the format cannot be
+// determined, this function returns false.
+func FormatOf(name string) (format string, ok bool) {
+	base := path.Base(name)
+	ext := strings.ToLower(path.Ext(base))
+	switch {
+	case ext == ".json" || ext == ".ndjson" || ext == ".jsonl":
+		format = FormatJSON
+
+	case ext == ".yaml" || ext == ".yml":
+		format = FormatYAML
+
+	case ext == ".toml":
+		format = FormatTOML
+
+	case ext == ".csv":
+		format = FormatCSV
+
+	case ext == ".env" || strings.HasPrefix(base, ".env."):
+		format = FormatDotenv
+	}
+
+	return format, len(format) > 0
+}
+
+// DecodeModels reads all the Models from r using the given format.  CSV data produces
+// one Model per row, YAML produces one Model per document, and JSON produces one Model
+// per object, including each object in a top-level array.  Other formats produce
+// exactly one Model.  Maps nested within the models are always map[string]interface{},
+// so templates can index them.
+func DecodeModels(r io.Reader, format string) ([]Model, error) {
+	switch format {
+	case FormatJSON:
+		return decodeJSON(r)
+
+	case FormatYAML:
+		return decodeYAML(r)
+
+	case FormatTOML:
+		return decodeTOML(r)
+
+	case FormatDotenv:
+		return decodeDotenv(r)
+
+	case FormatCSV:
+		return decodeCSV(r)
+
+	default:
+		return nil, &UnsupportedFormatError{Format: format}
+	}
+}
+
+// DecodeModel reads a single Model from r using the given format.  If the data
+// does not contain exactly one Model, a *ModelCountError is returned.
+func DecodeModel(r io.Reader, format string) (Model, error) {
+	ms, err := DecodeModels(r, format)
+	if err == nil && len(ms) != 1 {
+		err = &ModelCountError{Count: len(ms)}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ms[0], nil
+}
+
+// LoadModels reads all the Models from a file, using the file's extension
+// to determine the format.  Files with an unrecognized extension are YAML.
+func LoadModels(fsys fs.FS, name string) ([]Model, error) {
+	format, ok := FormatOf(name)
+	if !ok {
+		format = FormatYAML
+	}
+
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+	return DecodeModels(f, format)
+}
+
+// LoadModel reads a single Model from a file, using the file's extension
+// to determine the format.  Files with an unrecognized extension are YAML.
+func LoadModel(fsys fs.FS, name string) (Model, error) {
+	ms, err := LoadModels(fsys, name)
+	if err == nil && len(ms) != 1 {
+		err = &ModelCountError{Count: len(ms)}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ms[0], nil
+}
+
+// toModel converts a decoded value into a Model.  A nil value is an empty Model.
+func toModel(v interface{}) (Model, error) {
+	switch tv := v.(type) {
+	case nil:
+		return Model{}, nil
+
+	case Model:
+		return tv, nil
+
+	case map[string]interface{}:
+		if tv == nil {
+			return Model{}, nil
+		}
+
+		return Model(tv), nil
+
+	default:
+		return nil, fmt.Errorf("a model must be a map, not %T", v)
+	}
+}
+
+// normalizeJSON converts json.Number values into int64 when they are integers
+// and float64 otherwise.  Numbers too large for a float64 are an error.
+func normalizeJSON(v interface{}) (interface{}, error) {
+	var err error
+	switch tv := v.(type) {
+	case json.Number:
+		if i, err := tv.Int64(); err == nil {
+			return i, nil
+		}
+
+		return tv.Float64()
+
+	case map[string]interface{}:
+		for k, e := range tv {
+			if tv[k], err = normalizeJSON(e); err != nil {
+				return nil, err
+			}
+		}
+
+	case []interface{}:
+		for i, e := range tv {
+			if tv[i], err = normalizeJSON(e); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return v, nil
+}
+
+// decodeJSON reads a sequence of JSON values, which allows for both a single document
+// and newline-delimited JSON.  Each value is either an object, which is a Model, or an
+// array of objects, each of which is a Model.  Empty input is a single, empty Model,
+// while an empty array is no Models at all.
+func decodeJSON(r io.Reader) (models []Model, err error) {
+	d := json.NewDecoder(r)
+	d.UseNumber()
+	empty := true
+	for err == nil {
+		var v interface{}
+		if err = d.Decode(&v); err != nil {
+			break
+		}
+
+		empty = false
+
+		values, isArray := v.([]interface{})
+		if !isArray {
+			values = []interface{}{v}
+		}
+
+		for i := 0; err == nil && i < len(values); i++ {
+			var (
+				m Model
+				n interface{}
+			)
+
+			if n, err = normalizeJSON(values[i]); err == nil {
+				m, err = toModel(n)
+				models = append(models, m)
+			}
+		}
+	}
+
+	switch {
+	case !errors.Is(err, io.EOF):
+		return nil, err
+
+	case empty:
+		models = []Model{{}}
+	}
+
+	return models, nil
+}
+
+// decodeYAML reads a stream of YAML documents, each of which is a Model.
+// Empty input is a single, empty Model.
+func decodeYAML(r io.Reader) (models []Model, err error) {
+	d := yaml.NewDecoder(r)
+	for err == nil {
+		var m Model
+		if err = d.Decode(&m); err == nil {
+			if m == nil {
+				m = Model{}
+			}
+
+			models = append(models, m)
+		}
+	}
+
+	switch {
+	case !errors.Is(err, io.EOF):
+		return nil, err
+
+	case len(models) == 0:
+		models = []Model{{}}
+	}
+
+	return models, nil
+}
+
+func decodeTOML(r io.Reader) ([]Model, error) {
+	var m map[string]interface{}
+	if err := toml.NewDecoder(r).Decode(&m); err != nil {
+		return nil, err
+	}
+
+	result, err := toModel(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return []Model{result}, nil
+}
+
+// decodeDotenv parses KEY=VALUE lines.  Blank lines and lines starting with '#'
+// are ignored, as is an "export " prefix.  Values may be single-quoted, which
+// are taken literally, or double-quoted, which support the usual escapes.
+// Unquoted values end at a " #" comment.
+func decodeDotenv(r io.Reader) ([]Model, error) {
+	var (
+		m       = Model{}
+		scanner = bufio.NewScanner(r)
+		line    int
+	)
+
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if len(text) == 0 || text[0] == '#' {
+			continue
+		}
+
+		text = strings.TrimPrefix(text, "export ")
+		key, value, ok := strings.Cut(text, "=")
+		key = strings.TrimSpace(key)
+		if !ok || len(key) == 0 {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", line)
+		}
+
+		value = strings.TrimSpace(value)
+		switch {
+		case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+			value = value[1 : len(value)-1]
+
+		case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+
+			value = unquoted
+
+		default:
+			if i := strings.Index(value, " #"); i >= 0 {
+				value = strings.TrimSpace(value[:i])
+			}
+		}
+
+		m[key] = value
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return []Model{m}, nil
+}
+
+// decodeCSV reads a header row followed by records, each of which is a Model keyed
+// by the header.  As with the other formats, input with no records is a single,
+// empty Model.
+func decodeCSV(r io.Reader) ([]Model, error) {
+	cr := csv.NewReader(r)
+	records, err := cr.ReadAll()
+	switch {
+	case err != nil:
+		return nil, err
+
+	case len(records) < 2:
+		return []Model{{}}, nil
+	}
+
+	header := records[0]
+	models := make([]Model, 0, len(records)-1)
+	for _, record := range records[1:] {
+		m := make(Model, len(header))
+		for i, column := range header {
+			m[column] = record[i]
+		}
+
+		models = append(models, m)
+	}
+
+	return models, nil
+}
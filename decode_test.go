@@ -0,0 +1,277 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFormatOf(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected string
+	}{
+		{name: "model.json", expected: FormatJSON},
+		{name: "model.JSON", expected: FormatJSON},
+		{name: "model.ndjson", expected: FormatJSON},
+		{name: "model.jsonl", expected: FormatJSON},
+		{name: "model.yaml", expected: FormatYAML},
+		{name: "dir/model.yml", expected: FormatYAML},
+		{name: "model.toml", expected: FormatTOML},
+		{name: "model.csv", expected: FormatCSV},
+		{name: ".env", expected: FormatDotenv},
+		{name: "dir/.env.local", expected: FormatDotenv},
+		{name: "model.env", expected: FormatDotenv},
+		{name: "model.txt"},
+		{name: "model"},
+	}
+
+	for _, testCase := range testCases {
+		format, ok := FormatOf(testCase.name)
+		if format != testCase.expected || ok != (len(testCase.expected) > 0) {
+			t.Errorf("%s: expected %q, got %q, %t", testCase.name, testCase.expected, format, ok)
+		}
+	}
+}
+
+func TestDecodeModels(t *testing.T) {
+	type m = map[string]interface{}
+
+	testCases := []struct {
+		name     string
+		format   string
+		data     string
+		expected []Model
+		err      string
+	}{
+		{
+			name:     "JSONEmpty",
+			format:   FormatJSON,
+			data:     "",
+			expected: []Model{{}},
+		},
+		{
+			name:     "JSONEmptyArray",
+			format:   FormatJSON,
+			data:     "[]",
+			expected: nil,
+		},
+		{
+			name:     "JSONObject",
+			format:   FormatJSON,
+			data:     `{"i": 1, "f": 1.5, "big": 12345678901234567890, "n": null, "m": {"l": [1, {"x": 2}]}}`,
+			expected: []Model{{"i": int64(1), "f": 1.5, "big": 12345678901234567890.0, "n": nil, "m": m{"l": []interface{}{int64(1), m{"x": int64(2)}}}}},
+		},
+		{
+			name:     "JSONArray",
+			format:   FormatJSON,
+			data:     `[{"a": 1}, {"a": 2}]`,
+			expected: []Model{{"a": int64(1)}, {"a": int64(2)}},
+		},
+		{
+			name:     "JSONLines",
+			format:   FormatJSON,
+			data:     "{\"a\": 1}\n{\"a\": 2}\n[{\"a\": 3}]\n",
+			expected: []Model{{"a": int64(1)}, {"a": int64(2)}, {"a": int64(3)}},
+		},
+		{
+			name:   "JSONInvalid",
+			format: FormatJSON,
+			data:   `{"a": `,
+			err:    "unexpected EOF",
+		},
+		{
+			name:   "JSONNumberOutOfRange",
+			format: FormatJSON,
+			data:   `{"a": [1e400]}`,
+			err:    "value out of range",
+		},
+		{
+			name:   "JSONNotAnObject",
+			format: FormatJSON,
+			data:   `[1]`,
+			err:    "a model must be a map",
+		},
+		{
+			name:     "YAMLEmpty",
+			format:   FormatYAML,
+			data:     "",
+			expected: []Model{{}},
+		},
+		{
+			name:     "YAMLDocuments",
+			format:   FormatYAML,
+			data:     "a: 1\n---\n---\nb: {c: x}\n",
+			expected: []Model{{"a": 1}, {}, {"b": m{"c": "x"}}},
+		},
+		{
+			name:   "YAMLInvalid",
+			format: FormatYAML,
+			data:   "a: [",
+			err:    "yaml",
+		},
+		{
+			name:     "TOML",
+			format:   FormatTOML,
+			data:     "a = 1\n[b]\nc = \"x\"\n",
+			expected: []Model{{"a": int64(1), "b": m{"c": "x"}}},
+		},
+		{
+			name:     "TOMLEmpty",
+			format:   FormatTOML,
+			data:     "",
+			expected: []Model{{}},
+		},
+		{
+			name:   "TOMLInvalid",
+			format: FormatTOML,
+			data:   "a = ",
+			err:    "toml",
+		},
+		{
+			name:   "Dotenv",
+			format: FormatDotenv,
+			data:   "# comment\n\nexport A=1\nB = 'single # literal'\nC=\"double\\nline\"\nD=value # comment\nE=\n",
+			expected: []Model{{
+				"A": "1",
+				"B": "single # literal",
+				"C": "double\nline",
+				"D": "value",
+				"E": "",
+			}},
+		},
+		{
+			name:   "DotenvInvalid",
+			format: FormatDotenv,
+			data:   "A=1\nnot a pair\n",
+			err:    "line 2: expected KEY=VALUE",
+		},
+		{
+			name:   "DotenvInvalidQuotes",
+			format: FormatDotenv,
+			data:   `A="\q"`,
+			err:    "line 1",
+		},
+		{
+			name:     "CSV",
+			format:   FormatCSV,
+			data:     "a,b\n1,x\n2,y\n",
+			expected: []Model{{"a": "1", "b": "x"}, {"a": "2", "b": "y"}},
+		},
+		{
+			name:     "CSVHeaderOnly",
+			format:   FormatCSV,
+			data:     "a,b\n",
+			expected: []Model{{}},
+		},
+		{
+			name:     "CSVEmpty",
+			format:   FormatCSV,
+			data:     "",
+			expected: []Model{{}},
+		},
+		{
+			name:   "CSVInvalid",
+			format: FormatCSV,
+			data:   "a,b\n1\n",
+			err:    "wrong number of fields",
+		},
+		{
+			name:   "Unsupported",
+			format: "xml",
+			data:   "<a/>",
+			err:    "xml is not a supported model format",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			actual, err := DecodeModels(strings.NewReader(testCase.data), testCase.format)
+			if len(testCase.err) > 0 {
+				if err == nil || !strings.Contains(err.Error(), testCase.err) {
+					t.Errorf("expected an error containing %q, got %v", testCase.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !reflect.DeepEqual(testCase.expected, actual) {
+				t.Errorf("expected %#v, got %#v", testCase.expected, actual)
+			}
+		})
+	}
+}
+
+func TestDecodeModel(t *testing.T) {
+	testCases := []struct {
+		name  string
+		data  string
+		count int
+	}{
+		{name: "One", data: `{"a": 1}`, count: -1},
+		{name: "None", data: `[]`, count: 0},
+		{name: "Several", data: `[{}, {}]`, count: 2},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			m, err := DecodeModel(strings.NewReader(testCase.data), FormatJSON)
+			if testCase.count < 0 {
+				if err != nil || m["a"] != int64(1) {
+					t.Errorf("unexpected result %v, %v", m, err)
+				}
+
+				return
+			}
+
+			var mce *ModelCountError
+			if !errors.As(err, &mce) || mce.Count != testCase.count {
+				t.Errorf("expected a ModelCountError with count %d, got %v", testCase.count, err)
+			}
+		})
+	}
+}
+
+func TestLoadModels(t *testing.T) {
+	fsys := fstest.MapFS{
+		"model.yaml":  {Data: []byte("a: 1\n")},
+		"models.json": {Data: []byte(`[{"a": 1}, {"a": 2}]`)},
+		"model.txt":   {Data: []byte("a: 1\n")},
+		"invalid.txt": {Data: []byte("a")},
+	}
+
+	testCases := []struct {
+		name  string
+		count int
+		err   bool
+	}{
+		{name: "model.yaml", count: 1},
+		{name: "models.json", count: 2},
+		{name: "model.txt", count: 1},
+		{name: "invalid.txt", err: true},
+		{name: "missing.yaml", err: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			models, err := LoadModels(fsys, testCase.name)
+			if testCase.err != (err != nil) || len(models) != testCase.count {
+				t.Errorf("unexpected result %v, %v", models, err)
+			}
+
+			_, err = LoadModel(fsys, testCase.name)
+			if (testCase.err || testCase.count != 1) != (err != nil) {
+				t.Errorf("unexpected LoadModel error %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+const (
+	// EngineGoText is the engine for the golang text/template package.
+	EngineGoText = "go-text"
+
+	// EngineGoHTML is the engine for the golang html/template package.
+	EngineGoHTML = "go-html"
+
+	// EngineMustache is the engine for logic-less Mustache templates.
+	EngineMustache = "mustache"
+
+	// EngineStructured is the engine for structured templates, where each template is a
+	// JSON or YAML document with expressions in its string values.
+	EngineStructured = "structured"
+)
+
+// UnsupportedEngineError indicates that ParserConfig referred to an unknown engine.
+type UnsupportedEngineError struct {
+	Engine string
+}
+
+// Error satisfies the error interface.
+func (uee *UnsupportedEngineError) Error() string {
+	return fmt.Sprintf("%s is not a supported template engine", uee.Engine)
+}
+
+// PositionError indicates a problem at a particular place in a template.
+type PositionError struct {
+	// Name is the template name.
+	Name string
+
+	// Line is the 1-based line within the template.
+	Line int
+
+	// Column is the 1-based column within the template.
+	Column int
+
+	// Err is the underlying problem.
+	Err error
+}
+
+// Error satisfies the error interface.  The format matches the errors from
+// the golang template packages.
+func (pe *PositionError) Error() string {
+	return fmt.Sprintf("template: %s:%d:%d: %s", pe.Name, pe.Line, pe.Column, pe.Err)
+}
+
+// Unwrap returns the underlying error.
+func (pe *PositionError) Unwrap() error {
+	return pe.Err
+}
+
+// Engine creates Parsers for one kind of template.
+type Engine interface {
+	// NewParser creates a Parser from a configuration.  The returned Parser's
+	// templates should implement MediaTyper using the configured MediaType.
+	NewParser(c ParserConfig) (Parser, error)
+}
+
+// EngineFunc is a function type that implements Engine.
+type EngineFunc func(ParserConfig) (Parser, error)
+
+// NewParser invokes this function.
+func (ef EngineFunc) NewParser(c ParserConfig) (Parser, error) {
+	return ef(c)
+}
+
+var (
+	enginesLock sync.RWMutex
+	engines     = map[string]Engine{
+		EngineGoText:     EngineFunc(newTextParser),
+		EngineGoHTML:     EngineFunc(newHTMLParser),
+		EngineMustache:   EngineFunc(newMustacheParser),
+		EngineStructured: EngineFunc(newStructuredParser),
+	}
+)
+
+// RegisterEngine associates an Engine with a name, replacing any existing Engine
+// with that name, including the built-in ones.  A nil Engine removes the name.
+func RegisterEngine(name string, e Engine) {
+	enginesLock.Lock()
+	defer enginesLock.Unlock()
+	if e != nil {
+		engines[name] = e
+	} else {
+		delete(engines, name)
+	}
+}
+
+// EngineFor returns the Engine registered with a name.
+func EngineFor(name string) (e Engine, found bool) {
+	enginesLock.RLock()
+	defer enginesLock.RUnlock()
+	e, found = engines[name]
+	return
+}
+
+// Engines returns the sorted names of all registered engines.
+func Engines() []string {
+	enginesLock.RLock()
+	defer enginesLock.RUnlock()
+	names := make([]string, 0, len(engines))
+	for name := range engines {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPositionError(t *testing.T) {
+	cause := errors.New("cause")
+	pe := &PositionError{Name: "t", Line: 2, Column: 7, Err: cause}
+	if pe.Error() != "template: t:2:7: cause" {
+		t.Errorf("unexpected error text: %s", pe.Error())
+	}
+
+	if !errors.Is(pe, cause) {
+		t.Error("a PositionError should unwrap to its cause")
+	}
+}
+
+func TestNewParserEngines(t *testing.T) {
+	testCases := []struct {
+		name     string
+		config   ParserConfig
+		template string
+		expected string
+		err      error
+	}{
+		{
+			name:     "Default",
+			template: "{{.x}}",
+			expected: "<b>",
+		},
+		{
+			name:     "GoText",
+			config:   ParserConfig{Engine: EngineGoText},
+			template: "{{.x}}",
+			expected: "<b>",
+		},
+		{
+			name:     "HTML",
+			config:   ParserConfig{HTML: true},
+			template: "{{.x}}",
+			expected: "&lt;b&gt;",
+		},
+		{
+			name:     "GoHTML",
+			config:   ParserConfig{Engine: EngineGoHTML},
+			template: "{{.x}}",
+			expected: "&lt;b&gt;",
+		},
+		{
+			name:     "Mustache",
+			config:   ParserConfig{Engine: EngineMustache},
+			template: "{{x}}",
+			expected: "&lt;b&gt;",
+		},
+		{
+			name:     "Structured",
+			config:   ParserConfig{Engine: EngineStructured},
+			template: `"${.x}"`,
+			expected: "\"<b>\"\n",
+		},
+		{
+			name:   "HTMLWithEngine",
+			config: ParserConfig{Engine: EngineMustache, HTML: true},
+			err:    ErrHTMLWithEngine,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			p, err := NewParser(testCase.config)
+			if testCase.err != nil {
+				if !errors.Is(err, testCase.err) {
+					t.Errorf("expected %v, got %v", testCase.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unable to create parser: %s", err)
+			}
+
+			tmpl, err := p.Parse("t", testCase.template)
+			if err != nil {
+				t.Fatalf("unable to parse template: %s", err)
+			}
+
+			var o strings.Builder
+			if err := tmpl.Execute(&o, Model{"x": "<b>"}); err != nil || o.String() != testCase.expected {
+				t.Errorf("expected %q, got %q (%v)", testCase.expected, o.String(), err)
+			}
+		})
+	}
+}
+
+func TestRegisterEngine(t *testing.T) {
+	const name = "test-engine"
+	var created ParserConfig
+	RegisterEngine(name, EngineFunc(func(c ParserConfig) (Parser, error) {
+		created = c
+		return newTextParser(c)
+	}))
+
+	defer RegisterEngine(name, nil)
+
+	found := false
+	for _, e := range Engines() {
+		found = found || e == name
+	}
+
+	if !found {
+		t.Errorf("%s is missing from %v", name, Engines())
+	}
+
+	if _, err := NewParser(ParserConfig{Engine: name, MediaType: "text/plain"}); err != nil {
+		t.Fatalf("unable to create parser: %s", err)
+	} else if created.MediaType != "text/plain" {
+		t.Errorf("the engine did not receive the configuration: %#v", created)
+	}
+
+	RegisterEngine(name, nil)
+	if _, found := EngineFor(name); found {
+		t.Error("a nil engine should remove the name")
+	}
+
+	var uee *UnsupportedEngineError
+	if _, err := NewParser(ParserConfig{Engine: name}); !errors.As(err, &uee) || uee.Engine != name {
+		t.Errorf("expected an UnsupportedEngineError, got %v", err)
+	} else if err.Error() != "test-engine is not a supported template engine" {
+		t.Errorf("unexpected error text: %s", err)
+	}
+}
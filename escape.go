@@ -0,0 +1,250 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	ttemplate "text/template"
+	"text/template/parse"
+)
+
+const (
+	// jsonStringFunc is the function appended to pipelines whose output
+	// appears inside a JSON string literal.
+	jsonStringFunc = "_thoth_json_string"
+
+	// jsonValueFunc is the function appended to pipelines whose output
+	// appears where a JSON value is expected.
+	jsonValueFunc = "_thoth_json_value"
+)
+
+// jsonFuncs are the escaping functions used by the JSON escaping mode.
+var jsonFuncs = map[string]interface{}{
+	jsonStringFunc: JSONEscapeString,
+	jsonValueFunc:  JSONEscapeValue,
+}
+
+// JSONEscapeError indicates that a template could not be escaped as JSON, usually
+// because a control structure changes whether output is inside a string literal.
+type JSONEscapeError struct {
+	// Location is the template name and position of the problem.
+	Location string
+
+	// Message describes the problem.
+	Message string
+}
+
+// Error satisfies the error interface.  The format matches the errors from
+// the golang template packages.
+func (jee *JSONEscapeError) Error() string {
+	return fmt.Sprintf("template: %s: %s", jee.Location, jee.Message)
+}
+
+// encodeJSON marshals a value without escaping HTML characters, which have no
+// special meaning in JSON documents.
+func encodeJSON(v interface{}) ([]byte, error) {
+	var b bytes.Buffer
+	e := json.NewEncoder(&b)
+	e.SetEscapeHTML(false)
+	if err := e.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(b.Bytes(), []byte{'\n'}), nil
+}
+
+// JSONEscapeString formats a value as text/template would print it, then escapes
+// the result so that it can appear inside a JSON string literal.  The surrounding
+// quotes are not included.
+func JSONEscapeString(v interface{}) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		s = fmt.Sprint(v)
+	}
+
+	encoded, err := encodeJSON(s)
+	if err != nil {
+		return "", err
+	}
+
+	return string(encoded[1 : len(encoded)-1]), nil
+}
+
+// JSONEscapeValue encodes a value as a complete JSON value.  A json.RawMessage
+// is written as is, after checking that it is valid JSON.
+func JSONEscapeValue(v interface{}) (string, error) {
+	encoded, err := encodeJSON(v)
+	return string(encoded), err
+}
+
+// jsonState is where template output falls within a JSON document.
+type jsonState int
+
+const (
+	// jsonBare means output is outside any string literal.
+	jsonBare jsonState = iota
+
+	// jsonString means output is inside a string literal.
+	jsonString
+
+	// jsonStringEscape means output immediately follows a backslash
+	// inside a string literal.
+	jsonStringEscape
+)
+
+// next computes the state after the given literal text.
+func (s jsonState) next(text []byte) jsonState {
+	for _, c := range text {
+		switch s {
+		case jsonBare:
+			if c == '"' {
+				s = jsonString
+			}
+
+		case jsonString:
+			if c == '\\' {
+				s = jsonStringEscape
+			} else if c == '"' {
+				s = jsonBare
+			}
+
+		case jsonStringEscape:
+			s = jsonString
+		}
+	}
+
+	return s
+}
+
+// jsonEscaper rewrites a template's parse tree so that the output of each action
+// is escaped according to where it falls within a JSON document.
+type jsonEscaper struct {
+	tree *parse.Tree
+}
+
+func (je jsonEscaper) errorf(n parse.Node, format string, args ...interface{}) error {
+	location, _ := je.tree.ErrorContext(n)
+	return &JSONEscapeError{
+		Location: location,
+		Message:  fmt.Sprintf(format, args...),
+	}
+}
+
+// list escapes each node in a list, returning the state after the list.
+func (je jsonEscaper) list(s jsonState, l *parse.ListNode) (jsonState, error) {
+	if l == nil {
+		return s, nil
+	}
+
+	var err error
+	for i := 0; err == nil && i < len(l.Nodes); i++ {
+		s, err = je.node(s, l.Nodes[i])
+	}
+
+	return s, err
+}
+
+// node escapes a single node, returning the state after that node.
+func (je jsonEscaper) node(s jsonState, n parse.Node) (jsonState, error) {
+	switch tn := n.(type) {
+	case *parse.TextNode:
+		return s.next(tn.Text), nil
+
+	case *parse.ActionNode:
+		return s, je.action(s, tn)
+
+	case *parse.IfNode:
+		return je.branch(s, &tn.BranchNode, false)
+
+	case *parse.RangeNode:
+		return je.branch(s, &tn.BranchNode, true)
+
+	case *parse.WithNode:
+		return je.branch(s, &tn.BranchNode, false)
+
+	case *parse.ListNode:
+		return je.list(s, tn)
+
+	case *parse.TemplateNode:
+		if s != jsonBare {
+			return s, je.errorf(n, "cannot invoke template %q inside a JSON string", tn.Name)
+		}
+
+		return s, nil
+
+	default:
+		return s, nil
+	}
+}
+
+// action appends the escaping function appropriate to the given state.  Actions
+// that declare or assign variables produce no output, so they are left alone.
+func (je jsonEscaper) action(s jsonState, a *parse.ActionNode) error {
+	if a.Pipe == nil || len(a.Pipe.Decl) > 0 {
+		return nil
+	}
+
+	f := jsonValueFunc
+	switch s {
+	case jsonString:
+		f = jsonStringFunc
+
+	case jsonStringEscape:
+		return je.errorf(a, "action follows a backslash inside a JSON string")
+	}
+
+	a.Pipe.Cmds = append(a.Pipe.Cmds, &parse.CommandNode{
+		NodeType: parse.NodeCommand,
+		Pos:      a.Pos,
+		Args: []parse.Node{
+			parse.NewIdentifier(f).SetTree(je.tree).SetPos(a.Pos),
+		},
+	})
+
+	return nil
+}
+
+// branch escapes the lists of an if, range, or with.  Every way through the branch
+// must end in the same state.  For a range, the body must also end in the state it
+// began in, since it may execute any number of times.
+func (je jsonEscaper) branch(s jsonState, b *parse.BranchNode, loop bool) (jsonState, error) {
+	after, err := je.list(s, b.List)
+	if err == nil && loop && after != s {
+		err = je.errorf(b, "range body changes whether output is inside a JSON string")
+	}
+
+	if err == nil {
+		var elseAfter jsonState
+		elseAfter, err = je.list(s, b.ElseList)
+		if err == nil && elseAfter != after {
+			err = je.errorf(b, "branches end in different JSON contexts")
+		}
+	}
+
+	return after, err
+}
+
+// escapeJSON rewrites the given templates so that action output is escaped
+// for JSON.  Each template is assumed to start outside of any string literal.
+func escapeJSON(ts []*ttemplate.Template) error {
+	for _, at := range ts {
+		if at.Tree == nil || at.Tree.Root == nil {
+			continue
+		}
+
+		je := jsonEscaper{tree: at.Tree}
+		s, err := je.list(jsonBare, at.Tree.Root)
+		if err == nil && s != jsonBare {
+			err = je.errorf(at.Tree.Root, "template ends inside a JSON string")
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,180 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestJSONEscapeString(t *testing.T) {
+	testCases := []struct {
+		value    interface{}
+		expected string
+	}{
+		{value: "plain", expected: "plain"},
+		{value: `quote " and backslash \`, expected: `quote \" and backslash \\`},
+		{value: "line\nbreak\ttab", expected: `line\nbreak\ttab`},
+		{value: "<b>&</b>", expected: "<b>&</b>"},
+		{value: "\u0001", expected: `\u0001`},
+		{value: 12, expected: "12"},
+		{value: []int{1, 2}, expected: "[1 2]"},
+		{value: nil, expected: "<nil>"},
+	}
+
+	for _, testCase := range testCases {
+		actual, err := JSONEscapeString(testCase.value)
+		if err != nil || actual != testCase.expected {
+			t.Errorf("%#v: expected %q, got %q (%v)", testCase.value, testCase.expected, actual, err)
+		}
+	}
+}
+
+func TestJSONEscapeValue(t *testing.T) {
+	testCases := []struct {
+		value    interface{}
+		expected string
+		err      bool
+	}{
+		{value: "text\"", expected: `"text\""`},
+		{value: "<b>", expected: `"<b>"`},
+		{value: 1.5, expected: "1.5"},
+		{value: nil, expected: "null"},
+		{value: []interface{}{1, "a"}, expected: `[1,"a"]`},
+		{value: map[string]interface{}{"a": true}, expected: `{"a":true}`},
+		{value: json.RawMessage(`{"raw": 1}`), expected: `{"raw":1}`},
+		{value: json.RawMessage(`{`), err: true},
+		{value: func() {}, err: true},
+	}
+
+	for _, testCase := range testCases {
+		actual, err := JSONEscapeValue(testCase.value)
+		switch {
+		case testCase.err && err == nil:
+			t.Errorf("%#v: expected an error", testCase.value)
+
+		case !testCase.err && (err != nil || actual != testCase.expected):
+			t.Errorf("%#v: expected %q, got %q (%v)", testCase.value, testCase.expected, actual, err)
+		}
+	}
+}
+
+func TestJSONEscaping(t *testing.T) {
+	testCases := []struct {
+		name     string
+		template string
+		data     Model
+		expected string
+		err      string
+	}{
+		{
+			name:     "String",
+			template: `{"name": "{{.name}}"}`,
+			data:     Model{"name": "a \"quoted\"\nvalue"},
+			expected: `{"name": "a \"quoted\"\nvalue"}`,
+		},
+		{
+			name:     "Value",
+			template: `{"name": {{.name}}, "list": {{.list}}, "none": {{.none}}}`,
+			data:     Model{"name": "x\"y", "list": []interface{}{1, "a"}, "none": nil},
+			expected: `{"name": "x\"y", "list": [1,"a"], "none": null}`,
+		},
+		{
+			name:     "EscapedQuoteInText",
+			template: `{"a": "say \"{{.name}}\""}`,
+			data:     Model{"name": `"hi"`},
+			expected: `{"a": "say \"\"hi\"\""}`,
+		},
+		{
+			name:     "Branches",
+			template: `{"a": {{if .flag}}"{{.name}}"{{else}}{{.name}}{{end}}}`,
+			data:     Model{"flag": true, "name": `x"`},
+			expected: `{"a": "x\""}`,
+		},
+		{
+			name:     "Range",
+			template: `[{{range $i, $v := .list}}{{if $i}}, {{end}}"{{$v}}"{{end}}]`,
+			data:     Model{"list": []interface{}{"a\"", "b"}},
+			expected: `["a\"", "b"]`,
+		},
+		{
+			name:     "Variables",
+			template: `{{$x := .name}}{"a": "{{$x}}"}`,
+			data:     Model{"name": `"`},
+			expected: `{"a": "\""}`,
+		},
+		{
+			name:     "Define",
+			template: `{{define "item"}}"{{.}}"{{end}}[{{template "item" .name}}]`,
+			data:     Model{"name": `"`},
+			expected: `["\""]`,
+		},
+		{
+			name:     "HTMLCharacters",
+			template: `{"a": "{{.name}}", "b": {{.name}}}`,
+			data:     Model{"name": "<&>"},
+			expected: `{"a": "<&>", "b": "<&>"}`,
+		},
+		{
+			name:     "MismatchedBranches",
+			template: `{"a": {{if .flag}}"{{end}}x"}`,
+			err:      "branches end in different JSON contexts",
+		},
+		{
+			name:     "RangeChangesContext",
+			template: `[{{range .list}}"{{end}}]`,
+			err:      "range body changes whether output is inside a JSON string",
+		},
+		{
+			name:     "TemplateInString",
+			template: `{{define "x"}}x{{end}}{"a": "{{template "x"}}"}`,
+			err:      `cannot invoke template "x" inside a JSON string`,
+		},
+		{
+			name:     "ActionAfterBackslash",
+			template: `{"a": "\{{.name}}"}`,
+			err:      "action follows a backslash inside a JSON string",
+		},
+		{
+			name:     "EndsInString",
+			template: `{"a": "{{.name}}}`,
+			err:      "template ends inside a JSON string",
+		},
+	}
+
+	p, err := NewParser(ParserConfig{JSON: true})
+	if err != nil {
+		t.Fatalf("unable to create parser: %s", err)
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			tmpl, err := p.Parse("t.json", testCase.template)
+			if len(testCase.err) > 0 {
+				var jee *JSONEscapeError
+				if !errors.As(err, &jee) || !strings.Contains(err.Error(), testCase.err) || !strings.HasPrefix(err.Error(), "template: t.json:") {
+					t.Errorf("expected a JSONEscapeError containing %q, got %v", testCase.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			var o bytes.Buffer
+			if err := tmpl.Execute(&o, testCase.data); err != nil || o.String() != testCase.expected {
+				t.Errorf("expected %s, got %s (%v)", testCase.expected, o.String(), err)
+			}
+
+			if !json.Valid(o.Bytes()) {
+				t.Errorf("invalid JSON output: %s", o.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// UnsupportedExecuteOptionError indicates that an ExecuteOption was used with
+// a Template that does not support it.
+type UnsupportedExecuteOptionError struct {
+	// Name is the name of the template.
+	Name string
+
+	// Option describes the unsupported option.
+	Option string
+}
+
+// Error satisfies the error interface.
+func (ueoe *UnsupportedExecuteOptionError) Error() string {
+	return fmt.Sprintf("template %s does not support %s", ueoe.Name, ueoe.Option)
+}
+
+// ExecuteConfig is the set of options for a single execution of a Template.
+type ExecuteConfig struct {
+	// Funcs are functions that replace the template's functions of the same names
+	// for this execution only.  Other executions of the same Template, including
+	// concurrent ones, are unaffected.  Names that the template does not use are ignored.
+	Funcs map[string]interface{}
+
+	// Entropy replaces the source of random bytes for the template's functions
+	// for this execution only.  See ParserConfig.Entropy.
+	Entropy io.Reader
+}
+
+// ExecuteOption tailors a single execution of a Template.
+type ExecuteOption func(*ExecuteConfig)
+
+// WithFuncs replaces the template's functions of the same names as those in the
+// given map for one execution.  This option may be used more than once, in which
+// case later functions take precedence.
+func WithFuncs(fm map[string]interface{}) ExecuteOption {
+	return func(ec *ExecuteConfig) {
+		if ec.Funcs == nil {
+			ec.Funcs = make(map[string]interface{}, len(fm))
+		}
+
+		for k, v := range fm {
+			ec.Funcs[k] = v
+		}
+	}
+}
+
+// WithFunc replaces a single named function for one execution.
+func WithFunc(name string, f interface{}) ExecuteOption {
+	return WithFuncs(map[string]interface{}{name: f})
+}
+
+// WithEntropy replaces the source of random bytes, such as a SeededEntropy, for the
+// template's functions for one execution.  Giving each execution its own source keeps
+// the random values one execution sees independent of any other executions.
+func WithEntropy(r io.Reader) ExecuteOption {
+	return func(ec *ExecuteConfig) {
+		ec.Entropy = r
+	}
+}
+
+// NewExecuteConfig applies a sequence of options to an empty ExecuteConfig.
+func NewExecuteConfig(opts ...ExecuteOption) (ec ExecuteConfig) {
+	for _, o := range opts {
+		o(&ec)
+	}
+
+	return
+}
+
+// ExtendedTemplate is a Template that supports per-execution options.  All the
+// templates produced by this package's Parsers, and by its decorators such as
+// EnrichTemplate and ValidatingTemplate, implement this interface.
+type ExtendedTemplate interface {
+	Template
+
+	// ExecuteWith renders this template using the supplied data, tailored by
+	// the given options.  Execution does not begin if the context is already done,
+	// and it stops with the context's error if the context is done while executing.
+	// See ParserConfig.Timeout for how often the context is checked.
+	ExecuteWith(ctx context.Context, output io.Writer, data interface{}, opts ...ExecuteOption) error
+}
+
+// ExecuteWith renders a template with per-execution options.  If the template is
+// not an ExtendedTemplate, it is executed normally as long as no option requires
+// its support.  Otherwise, an *UnsupportedExecuteOptionError is returned.
+func ExecuteWith(ctx context.Context, t Template, output io.Writer, data interface{}, opts ...ExecuteOption) error {
+	if et, ok := t.(ExtendedTemplate); ok {
+		return et.ExecuteWith(ctx, output, data, opts...)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	ec := NewExecuteConfig(opts...)
+	switch {
+	case len(ec.Funcs) > 0:
+		return &UnsupportedExecuteOptionError{Name: t.Name(), Option: "function overrides"}
+
+	case ec.Entropy != nil:
+		return &UnsupportedExecuteOptionError{Name: t.Name(), Option: "entropy"}
+	}
+
+	return t.Execute(output, data)
+}
+
+// ExecuteContext renders a template, stopping with the context's error if the context
+// is done before execution finishes.  This is the same as ExecuteWith with no options.
+func ExecuteContext(ctx context.Context, t Template, output io.Writer, data interface{}) error {
+	return ExecuteWith(ctx, t, output, data)
+}
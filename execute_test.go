@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// plainTemplate is a Template that does not support per-execution options.
+type plainTemplate struct{}
+
+func (plainTemplate) Name() string { return "plain" }
+
+func (plainTemplate) Execute(output io.Writer, _ interface{}) error {
+	_, err := io.WriteString(output, "plain")
+	return err
+}
+
+func TestNewExecuteConfig(t *testing.T) {
+	a := func() string { return "a" }
+	b := func() string { return "b" }
+	entropy := SeededEntropy(1)
+
+	ec := NewExecuteConfig()
+	if ec.Funcs != nil || ec.Entropy != nil {
+		t.Errorf("expected an empty configuration, got %+v", ec)
+	}
+
+	ec = NewExecuteConfig(
+		WithFuncs(map[string]interface{}{"a": a, "b": a}),
+		WithFunc("b", b),
+		WithEntropy(entropy),
+	)
+
+	if len(ec.Funcs) != 2 || ec.Funcs["a"].(func() string)() != "a" || ec.Funcs["b"].(func() string)() != "b" {
+		t.Errorf("later functions should take precedence: %v", ec.Funcs)
+	}
+
+	if ec.Entropy != entropy {
+		t.Error("the entropy was not set")
+	}
+
+	fm := map[string]interface{}{"a": a}
+	ec = NewExecuteConfig(WithFuncs(fm), WithFunc("b", b))
+	if len(fm) != 1 || len(ec.Funcs) != 2 {
+		t.Error("WithFuncs should copy its map rather than modify it")
+	}
+}
+
+func TestExecuteWith(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	testCases := []struct {
+		name     string
+		ctx      context.Context
+		opts     []ExecuteOption
+		expected string
+		err      error
+	}{
+		{name: "NoOptions", ctx: context.Background(), expected: "plain"},
+		{name: "Canceled", ctx: canceled, err: context.Canceled},
+		{
+			name: "Funcs",
+			ctx:  context.Background(),
+			opts: []ExecuteOption{WithFunc("f", strings.ToUpper)},
+			err:  &UnsupportedExecuteOptionError{Name: "plain", Option: "function overrides"},
+		},
+		{
+			name: "Entropy",
+			ctx:  context.Background(),
+			opts: []ExecuteOption{WithEntropy(SeededEntropy(1))},
+			err:  &UnsupportedExecuteOptionError{Name: "plain", Option: "entropy"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var o strings.Builder
+			err := ExecuteWith(testCase.ctx, plainTemplate{}, &o, nil, testCase.opts...)
+			var ueoe *UnsupportedExecuteOptionError
+			switch {
+			case testCase.err == nil:
+				if err != nil || o.String() != testCase.expected {
+					t.Errorf("expected %q, got %q (%v)", testCase.expected, o.String(), err)
+				}
+
+			case errors.As(testCase.err, &ueoe):
+				if err == nil || err.Error() != testCase.err.Error() {
+					t.Errorf("expected %v, got %v", testCase.err, err)
+				}
+
+			case !errors.Is(err, testCase.err):
+				t.Errorf("expected %v, got %v", testCase.err, err)
+			}
+		})
+	}
+
+	var o strings.Builder
+	if err := ExecuteContext(canceled, plainTemplate{}, &o, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,197 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+const (
+	// FuncSetStrings is the name of the built-in set of string functions.
+	FuncSetStrings = "strings"
+
+	// FuncSetMath is the name of the built-in set of arithmetic functions.
+	FuncSetMath = "math"
+
+	// FuncSetCollections is the name of the built-in set of list and map functions.
+	FuncSetCollections = "collections"
+
+	// FuncSetEncoding is the name of the built-in set of base64, JSON, and YAML functions.
+	FuncSetEncoding = "encoding"
+
+	// FuncSetTime is the name of the built-in set of time functions.
+	FuncSetTime = "time"
+
+	// FuncSetRandom is the name of the built-in set of random value functions.
+	FuncSetRandom = "random"
+)
+
+// UnknownFuncSetError indicates that ParserConfig referred to a function set
+// that has not been registered.
+type UnknownFuncSetError struct {
+	Name string
+}
+
+// Error satisfies the error interface.
+func (ufse *UnknownFuncSetError) Error() string {
+	return fmt.Sprintf("%s is not a registered function set", ufse.Name)
+}
+
+// FuncSet is a named collection of template functions.
+type FuncSet interface {
+	// Funcs returns the functions for a parser configuration.  A FuncSet may
+	// use the configuration to tailor its functions.  The returned map is
+	// owned by the caller.
+	Funcs(c ParserConfig) map[string]interface{}
+}
+
+// FuncSetFunc is a function type that implements FuncSet.
+type FuncSetFunc func(ParserConfig) map[string]interface{}
+
+// Funcs invokes this function.
+func (fsf FuncSetFunc) Funcs(c ParserConfig) map[string]interface{} {
+	return fsf(c)
+}
+
+// Funcs is a FuncSet with a fixed set of functions.
+type Funcs map[string]interface{}
+
+// Funcs returns a copy of this map.  The configuration is ignored.
+func (f Funcs) Funcs(ParserConfig) map[string]interface{} {
+	fm := make(map[string]interface{}, len(f))
+	for k, v := range f {
+		fm[k] = v
+	}
+
+	return fm
+}
+
+var (
+	funcSetsLock sync.RWMutex
+	funcSets     = map[string]FuncSet{
+		FuncSetStrings:     FuncSetFunc(stringFuncs),
+		FuncSetMath:        mathFuncs,
+		FuncSetCollections: collectionFuncs,
+		FuncSetEncoding:    encodingFuncs,
+		FuncSetTime:        FuncSetFunc(timeFuncs),
+		FuncSetRandom:      FuncSetFunc(randomFuncs),
+	}
+)
+
+// RegisterFuncSet associates a FuncSet with a name, replacing any existing FuncSet
+// with that name, including the built-in ones.  A nil FuncSet removes the name.
+func RegisterFuncSet(name string, fs FuncSet) {
+	funcSetsLock.Lock()
+	defer funcSetsLock.Unlock()
+	if fs != nil {
+		funcSets[name] = fs
+	} else {
+		delete(funcSets, name)
+	}
+}
+
+// FuncSetFor returns the FuncSet registered with a name.
+func FuncSetFor(name string) (fs FuncSet, found bool) {
+	funcSetsLock.RLock()
+	defer funcSetsLock.RUnlock()
+	fs, found = funcSets[name]
+	return
+}
+
+// FuncSets returns the sorted names of all registered function sets.
+func FuncSets() []string {
+	funcSetsLock.RLock()
+	defer funcSetsLock.RUnlock()
+	names := make([]string, 0, len(funcSets))
+	for name := range funcSets {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// funcMap builds the complete function map for a configuration.  The named function
+// sets are applied in order, so later sets replace any functions with the same names
+// from earlier sets.  The configuration's FuncMap is applied last.
+func funcMap(c ParserConfig) (map[string]interface{}, error) {
+	if len(c.Functions) == 0 {
+		return c.FuncMap, nil
+	}
+
+	fm := make(map[string]interface{})
+	for _, name := range c.Functions {
+		fs, found := FuncSetFor(name)
+		if !found {
+			return nil, &UnknownFuncSetError{Name: name}
+		}
+
+		for k, v := range fs.Funcs(c) {
+			fm[k] = v
+		}
+	}
+
+	for k, v := range c.FuncMap {
+		fm[k] = v
+	}
+
+	return fm, nil
+}
+
+// funcSetParser decorates the templates from another Parser so that their function
+// sets are rebuilt for executions that have their own entropy.
+type funcSetParser struct {
+	Parser
+
+	// config is the configuration the function sets are built from, with
+	// the FuncMap as it was before the function sets were merged into it
+	config ParserConfig
+}
+
+func (fp funcSetParser) Parse(name, content string) (t Template, err error) {
+	t, err = fp.Parser.Parse(name, content)
+	if err == nil {
+		t = funcSetTemplate{
+			Template: t,
+			config:   fp.config,
+		}
+	}
+
+	return
+}
+
+// funcSetTemplate is a Template whose function sets are rebuilt for each execution
+// with an entropy option.  The rebuilt functions are installed as function overrides,
+// so overrides given to the same execution still take precedence.
+type funcSetTemplate struct {
+	Template
+	config ParserConfig
+}
+
+// MediaType returns the media type of the decorated template.
+func (ft funcSetTemplate) MediaType() string {
+	return MediaType(ft.Template)
+}
+
+// ExecuteWith executes the decorated template with the given options.  If the options
+// include an entropy, the function sets are rebuilt to use it.
+func (ft funcSetTemplate) ExecuteWith(ctx context.Context, output io.Writer, data interface{}, opts ...ExecuteOption) error {
+	if ec := NewExecuteConfig(opts...); ec.Entropy != nil {
+		c := ft.config
+		c.Entropy = ec.Entropy
+		fm, err := funcMap(c)
+		if err != nil {
+			return err
+		}
+
+		// the entropy has been applied, so the decorated template never sees it
+		opts = append(append([]ExecuteOption{WithFuncs(fm)}, opts...), WithEntropy(nil))
+	}
+
+	return ExecuteWith(ctx, ft.Template, output, data, opts...)
+}
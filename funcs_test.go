@@ -0,0 +1,168 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFuncs(t *testing.T) {
+	f := Funcs{"a": strings.ToUpper}
+	fm := f.Funcs(ParserConfig{})
+	fm["b"] = strings.ToLower
+	if _, exists := f["b"]; exists || len(fm) != 2 {
+		t.Error("Funcs should return a copy of its map")
+	}
+}
+
+func TestRegisterFuncSet(t *testing.T) {
+	const name = "test-funcs"
+	for _, builtin := range []string{FuncSetStrings, FuncSetMath, FuncSetCollections, FuncSetEncoding, FuncSetTime, FuncSetRandom} {
+		if _, found := FuncSetFor(builtin); !found {
+			t.Errorf("the built-in function set %s is not registered", builtin)
+		}
+	}
+
+	RegisterFuncSet(name, FuncSetFunc(func(c ParserConfig) map[string]interface{} {
+		return map[string]interface{}{"mediaType": func() string { return c.MediaType }}
+	}))
+
+	defer RegisterFuncSet(name, nil)
+	found := false
+	for _, n := range FuncSets() {
+		found = found || n == name
+	}
+
+	if !found {
+		t.Errorf("%s is missing from %v", name, FuncSets())
+	}
+
+	p, err := NewParser(ParserConfig{Functions: []string{name}, MediaType: "text/plain"})
+	if err != nil {
+		t.Fatalf("unable to create parser: %s", err)
+	}
+
+	tmpl, err := p.Parse("t", "{{mediaType}}")
+	if err != nil {
+		t.Fatalf("unable to parse template: %s", err)
+	}
+
+	var o strings.Builder
+	if err := tmpl.Execute(&o, nil); err != nil || o.String() != "text/plain" {
+		t.Errorf("the function set should be built from the configuration, got %q (%v)", o.String(), err)
+	}
+
+	RegisterFuncSet(name, nil)
+	if _, found := FuncSetFor(name); found {
+		t.Error("a nil function set should remove the name")
+	}
+}
+
+func TestFuncMap(t *testing.T) {
+	RegisterFuncSet("test-first", Funcs{"a": func() string { return "first" }, "b": func() string { return "first" }})
+	RegisterFuncSet("test-second", Funcs{"b": func() string { return "second" }, "c": func() string { return "second" }})
+	defer RegisterFuncSet("test-first", nil)
+	defer RegisterFuncSet("test-second", nil)
+
+	testCases := []struct {
+		name     string
+		config   ParserConfig
+		expected string
+		err      error
+	}{
+		{
+			name:     "Ordered",
+			config:   ParserConfig{Functions: []string{"test-first", "test-second"}},
+			expected: "first second second",
+		},
+		{
+			name:     "Reversed",
+			config:   ParserConfig{Functions: []string{"test-second", "test-first"}},
+			expected: "first first second",
+		},
+		{
+			name: "FuncMapLast",
+			config: ParserConfig{
+				Functions: []string{"test-first", "test-second"},
+				FuncMap:   map[string]interface{}{"c": func() string { return "funcmap" }},
+			},
+			expected: "first second funcmap",
+		},
+		{
+			name:   "Unknown",
+			config: ParserConfig{Functions: []string{"test-first", "nosuch"}},
+			err:    &UnknownFuncSetError{Name: "nosuch"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			p, err := NewParser(testCase.config)
+			if testCase.err != nil {
+				var ufse *UnknownFuncSetError
+				if !errors.As(err, &ufse) || err.Error() != testCase.err.Error() {
+					t.Errorf("expected %v, got %v", testCase.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unable to create parser: %s", err)
+			}
+
+			tmpl, err := p.Parse("t", "{{a}} {{b}} {{c}}")
+			if err != nil {
+				t.Fatalf("unable to parse template: %s", err)
+			}
+
+			var o strings.Builder
+			if err := tmpl.Execute(&o, nil); err != nil || o.String() != testCase.expected {
+				t.Errorf("expected %q, got %q (%v)", testCase.expected, o.String(), err)
+			}
+		})
+	}
+
+	fm := map[string]interface{}{"x": strings.ToUpper}
+	if actual, err := funcMap(ParserConfig{FuncMap: fm}); err != nil || len(actual) != 1 {
+		t.Errorf("without function sets, the FuncMap should be used as is: %v (%v)", actual, err)
+	}
+}
+
+func TestFunctionsWithEngines(t *testing.T) {
+	testCases := []struct {
+		engine   string
+		template string
+		expected string
+	}{
+		{engine: EngineGoText, template: `{{upper .x}}`, expected: "ABC"},
+		{engine: EngineGoHTML, template: `{{upper .x}}`, expected: "ABC"},
+		{engine: EngineStructured, template: `"${upper .x}"`, expected: "\"ABC\"\n"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.engine, func(t *testing.T) {
+			p, err := NewParser(ParserConfig{Engine: testCase.engine, Functions: []string{FuncSetStrings}})
+			if err != nil {
+				t.Fatalf("unable to create parser: %s", err)
+			}
+
+			tmpl, err := p.Parse("t", testCase.template)
+			if err != nil {
+				t.Fatalf("unable to parse template: %s", err)
+			}
+
+			var o strings.Builder
+			if err := tmpl.Execute(&o, Model{"x": "abc"}); err != nil || o.String() != testCase.expected {
+				t.Errorf("expected %q, got %q (%v)", testCase.expected, o.String(), err)
+			}
+		})
+	}
+
+	if _, err := NewParser(ParserConfig{Engine: EngineMustache, Functions: []string{FuncSetStrings}}); err != nil {
+		t.Errorf("Mustache templates should accept function sets they cannot use: %s", err)
+	}
+}
@@ -5,5 +5,9 @@ go 1.24
 require (
 	github.com/alecthomas/kong v1.12.0
 	github.com/gobwas/glob v0.2.3
+	github.com/pelletier/go-toml/v2 v2.2.4
+	github.com/santhosh-tekuri/jsonschema/v6 v6.0.2
 	gopkg.in/yaml.v3 v3.0.1
 )
+
+require golang.org/x/text v0.14.0 // indirect
@@ -0,0 +1,232 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"context"
+	htemplate "html/template"
+	"io"
+	"sync"
+	ttemplate "text/template"
+)
+
+// instance is a copy of a template set with the functions that enforce limits installed.
+type instance struct {
+	set   includeSet
+	state *execState
+}
+
+// instances is a pool of instances of a template set.  Installing the functions that
+// enforce limits requires a copy of the set, which for html/template must be escaped
+// again, so instances are reused by executions one at a time rather than created for
+// each execution.
+type instances struct {
+	pool   sync.Pool
+	create func(*execState) (includeSet, error)
+}
+
+// execute executes a named template from an instance of the set.
+func (is *instances) execute(ctx context.Context, output io.Writer, name string, data interface{}) error {
+	i, _ := is.pool.Get().(*instance)
+	if i == nil {
+		state := new(execState)
+		set, err := is.create(state)
+		if err != nil {
+			return err
+		}
+
+		i = &instance{set: set, state: state}
+	}
+
+	i.state.ctx, i.state.depth, i.state.err = ctx, 0, nil
+	err := i.state.result(i.set.ExecuteTemplate(output, name, data))
+
+	// don't hold on to the context while pooled
+	i.state.ctx = nil
+	is.pool.Put(i)
+	return err
+}
+
+// textTemplate is a template executed from a parsed text/template set.
+type textTemplate struct {
+	// Template is the template within its set that is executed, which is
+	// the outermost layout when the parsed template extends a layout
+	*ttemplate.Template
+
+	// name is the name of the parsed template
+	name string
+
+	// pristine is the unexecuted source for the sets that include renders
+	// with, or nil if the built-in include function is not bound
+	pristine *ttemplate.Template
+
+	// instances are used by executions that need the functions that enforce limits
+	instances *instances
+
+	mediaType string
+	limits    limits
+}
+
+func (tt textTemplate) Name() string {
+	return tt.name
+}
+
+func (tt textTemplate) MediaType() string {
+	return tt.mediaType
+}
+
+// Execute executes this template within its configured limits.
+func (tt textTemplate) Execute(output io.Writer, data interface{}) error {
+	return tt.ExecuteWith(context.Background(), output, data)
+}
+
+// instance creates a copy of the template set with the functions that enforce limits,
+// along with any function overrides, installed.
+func (tt textTemplate) instance(state *execState, overrides map[string]interface{}) (includeSet, error) {
+	c, err := tt.Template.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	funcs := mergeFuncs(overrides, tt.limits.funcs(state))
+	c.Funcs(funcs)
+	if _, overridden := funcs[IncludeFunc]; !overridden && tt.pristine != nil {
+		bindTextInclude(c, tt.pristine, funcs, newIncludeLevels(state, tt.limits))
+	}
+
+	return c, nil
+}
+
+// ExecuteWith executes this template with per-execution options, within its configured
+// limits.  Function overrides are installed into a copy of the template set made for
+// this execution.  Executions that need the functions that enforce limits, which depend
+// on the context and keep state, use a pooled copy of the set.
+func (tt textTemplate) ExecuteWith(ctx context.Context, output io.Writer, data interface{}, opts ...ExecuteOption) error {
+	ctx, cancel, err := tt.limits.context(ctx)
+	defer cancel()
+	if err != nil {
+		return err
+	}
+
+	output = tt.limits.writer(ctx, output)
+	overrides := NewExecuteConfig(opts...).Funcs
+	switch {
+	case len(overrides) > 0:
+		state := &execState{ctx: ctx}
+		set, err := tt.instance(state, overrides)
+		if err != nil {
+			return err
+		}
+
+		return state.result(set.ExecuteTemplate(output, tt.Template.Name(), data))
+
+	case tt.limits.stateful(ctx):
+		return tt.instances.execute(ctx, output, tt.Template.Name(), data)
+
+	default:
+		return tt.Template.Execute(output, data)
+	}
+}
+
+// htmlTemplate is a template executed from a parsed html/template set.
+type htmlTemplate struct {
+	// Template is the template within its set that is executed, which is
+	// the outermost layout when the parsed template extends a layout
+	*htemplate.Template
+
+	// name is the name of the parsed template
+	name string
+
+	// pristine is an unexecuted copy of the set.  An html/template set cannot
+	// be cloned once it has been executed, so this is the source for the sets
+	// used by function overrides, by limits, and by include.
+	pristine *htemplate.Template
+
+	// include indicates that the built-in include function is bound
+	include bool
+
+	// instances are used by executions that need the functions that enforce limits
+	instances *instances
+
+	mediaType string
+	limits    limits
+}
+
+func (ht htmlTemplate) Name() string {
+	return ht.name
+}
+
+func (ht htmlTemplate) MediaType() string {
+	return ht.mediaType
+}
+
+// Execute executes this template within its configured limits.
+func (ht htmlTemplate) Execute(output io.Writer, data interface{}) error {
+	return ht.ExecuteWith(context.Background(), output, data)
+}
+
+// instance creates a copy of the unexecuted template set with the functions that
+// enforce limits, along with any function overrides, installed.  The copy is
+// escaped again when it is first executed.
+func (ht htmlTemplate) instance(state *execState, overrides map[string]interface{}) (includeSet, error) {
+	c, err := ht.pristine.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	funcs := mergeFuncs(overrides, ht.limits.funcs(state))
+	c.Funcs(funcs)
+	if _, overridden := funcs[IncludeFunc]; !overridden && ht.include {
+		bindHTMLInclude(c, ht.pristine, funcs, newIncludeLevels(state, ht.limits))
+	}
+
+	return c, nil
+}
+
+// ExecuteWith executes this template with per-execution options, within its configured
+// limits.  Function overrides are installed into a copy of the template set made for
+// this execution.  Executions that need the functions that enforce limits, which depend
+// on the context and keep state, use a pooled copy of the set.
+func (ht htmlTemplate) ExecuteWith(ctx context.Context, output io.Writer, data interface{}, opts ...ExecuteOption) error {
+	ctx, cancel, err := ht.limits.context(ctx)
+	defer cancel()
+	if err != nil {
+		return err
+	}
+
+	output = ht.limits.writer(ctx, output)
+	overrides := NewExecuteConfig(opts...).Funcs
+	switch {
+	case len(overrides) > 0:
+		state := &execState{ctx: ctx}
+		set, err := ht.instance(state, overrides)
+		if err != nil {
+			return err
+		}
+
+		return state.result(set.ExecuteTemplate(output, ht.Template.Name(), data))
+
+	case ht.limits.stateful(ctx):
+		return ht.instances.execute(ctx, output, ht.Template.Name(), data)
+
+	default:
+		return ht.Template.Execute(output, data)
+	}
+}
+
+// mergeFuncs combines function maps, with later maps taking precedence.
+// The result is nil if there are no functions.
+func mergeFuncs(fms ...map[string]interface{}) (merged map[string]interface{}) {
+	for _, fm := range fms {
+		for k, v := range fm {
+			if merged == nil {
+				merged = make(map[string]interface{})
+			}
+
+			merged[k] = v
+		}
+	}
+
+	return
+}
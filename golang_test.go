@@ -0,0 +1,210 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// overrideConfigs are the parser configurations that support function overrides.
+var overrideConfigs = []struct {
+	name     string
+	config   ParserConfig
+	template string
+}{
+	{
+		name:     "Text",
+		config:   ParserConfig{MediaType: "text/plain"},
+		template: `{{define "item"}}{{greet .}}{{end}}{{greet .name}}|{{include "item" .name}}{{range .list}}|{{.}}{{end}}`,
+	},
+	{
+		name:     "HTML",
+		config:   ParserConfig{HTML: true},
+		template: `{{define "item"}}{{greet .}}{{end}}{{greet .name}}|{{include "item" .name}}{{range .list}}|{{.}}{{end}}`,
+	},
+	{
+		name:     "JSON",
+		config:   ParserConfig{JSON: true},
+		template: `{{define "item"}}{{greet .}}{{end}}["{{greet .name}}", "{{include "item" .name}}"{{range .list}}, "{{.}}"{{end}}]`,
+	},
+	{
+		name:     "Limited",
+		config:   ParserConfig{MediaType: "text/plain", MaxDepth: 10, MaxOutput: 1000, Timeout: time.Minute},
+		template: `{{define "item"}}{{greet .}}{{end}}{{greet .name}}|{{include "item" .name}}{{range .list}}|{{.}}{{end}}`,
+	},
+	{
+		name:     "Defaults",
+		config:   ParserConfig{MediaType: "text/plain", Defaults: Model{"list": []interface{}{"d"}}},
+		template: `{{define "item"}}{{greet .}}{{end}}{{greet .name}}|{{include "item" .name}}{{range .list}}|{{.}}{{end}}`,
+	},
+}
+
+// greeter returns a greet function that uses the given greeting.
+func greeter(greeting string) func(string) string {
+	return func(name string) string {
+		return greeting + " " + name
+	}
+}
+
+// expectedGreeting is the output of the override templates for a greeting.
+func expectedGreeting(name, greeting string, list ...string) string {
+	g := greeting + " " + strings.ToLower(name)
+	if name == "JSON" {
+		items := []string{`"` + g + `"`, `"` + g + `"`}
+		for _, l := range list {
+			items = append(items, `"`+l+`"`)
+		}
+
+		return "[" + strings.Join(items, ", ") + "]"
+	}
+
+	o := g + "|" + g
+	for _, l := range list {
+		o += "|" + l
+	}
+
+	return o
+}
+
+func newOverrideTemplate(t *testing.T, c ParserConfig, template string) Template {
+	c.FuncMap = map[string]interface{}{"greet": greeter("hello")}
+	p, err := NewParser(c)
+	if err != nil {
+		t.Fatalf("unable to create parser: %s", err)
+	}
+
+	tmpl, err := p.Parse("t", template)
+	if err != nil {
+		t.Fatalf("unable to parse template: %s", err)
+	}
+
+	return tmpl
+}
+
+func TestGolangExecuteWith(t *testing.T) {
+	for _, oc := range overrideConfigs {
+		t.Run(oc.name, func(t *testing.T) {
+			tmpl := newOverrideTemplate(t, oc.config, oc.template)
+			data := func(name string) Model {
+				return Model{"name": strings.ToLower(name), "list": []interface{}{"x"}}
+			}
+
+			var o strings.Builder
+			if err := ExecuteWith(context.Background(), tmpl, &o, data(oc.name), WithFunc("greet", greeter("bonjour"))); err != nil {
+				t.Fatalf("unable to execute template: %s", err)
+			} else if expected := expectedGreeting(oc.name, "bonjour", "x"); o.String() != expected {
+				t.Errorf("expected the override %q, got %q", expected, o.String())
+			}
+
+			o.Reset()
+			if err := tmpl.Execute(&o, data(oc.name)); err != nil {
+				t.Fatalf("unable to execute template: %s", err)
+			} else if expected := expectedGreeting(oc.name, "hello", "x"); o.String() != expected {
+				t.Errorf("an override should not affect later executions: expected %q, got %q", expected, o.String())
+			}
+
+			o.Reset()
+			err := ExecuteWith(context.Background(), tmpl, &o, data(oc.name), WithFunc(IncludeFunc, func(string, interface{}) string { return "included" }))
+			if err != nil || !strings.Contains(o.String(), "included") {
+				t.Errorf("include should be overridable, got %q (%v)", o.String(), err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			o.Reset()
+			if err := ExecuteWith(ctx, tmpl, &o, data(oc.name)); !errors.Is(err, context.Canceled) || o.Len() > 0 {
+				t.Errorf("expected context.Canceled and no output, got %q (%v)", o.String(), err)
+			}
+		})
+	}
+}
+
+func TestGolangExecuteWithConcurrent(t *testing.T) {
+	const executions = 50
+	for _, oc := range overrideConfigs {
+		t.Run(oc.name, func(t *testing.T) {
+			tmpl := newOverrideTemplate(t, oc.config, oc.template)
+			var wg sync.WaitGroup
+			errs := make(chan error, executions)
+			for i := 0; i < executions; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					var (
+						o        strings.Builder
+						item     = fmt.Sprintf("item%d", i)
+						data     = Model{"name": strings.ToLower(oc.name), "list": []interface{}{item}}
+						greeting = "hello"
+						opts     []ExecuteOption
+						ctx      = context.Background()
+					)
+
+					// mix the kinds of execution, so that overrides, pooled instances,
+					// and shared templates all run at once
+					switch i % 3 {
+					case 0:
+						greeting = fmt.Sprintf("greeting%d", i)
+						opts = append(opts, WithFunc("greet", greeter(greeting)))
+
+					case 1:
+						var cancel context.CancelFunc
+						ctx, cancel = context.WithCancel(ctx)
+						defer cancel()
+					}
+
+					err := ExecuteWith(ctx, tmpl, &o, data, opts...)
+					if expected := expectedGreeting(oc.name, greeting, item); err != nil || o.String() != expected {
+						errs <- fmt.Errorf("execution %d: expected %q, got %q (%v)", i, expected, o.String(), err)
+					}
+				}(i)
+			}
+
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestMergeFuncs(t *testing.T) {
+	testCases := []struct {
+		name     string
+		fms      []map[string]interface{}
+		expected []string
+	}{
+		{name: "None"},
+		{name: "Empty", fms: []map[string]interface{}{nil, {}}},
+		{name: "Later", fms: []map[string]interface{}{{"a": "first", "b": "first"}, nil, {"b": "second"}}, expected: []string{"a=first", "b=second"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			merged := mergeFuncs(testCase.fms...)
+			if len(testCase.expected) == 0 {
+				if merged != nil {
+					t.Errorf("expected nil, got %v", merged)
+				}
+
+				return
+			}
+
+			var actual []string
+			for _, k := range []string{"a", "b"} {
+				actual = append(actual, fmt.Sprintf("%s=%v", k, merged[k]))
+			}
+
+			if strings.Join(actual, ",") != strings.Join(testCase.expected, ",") || len(merged) != len(testCase.expected) {
+				t.Errorf("expected %v, got %v", testCase.expected, merged)
+			}
+		})
+	}
+}
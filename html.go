@@ -0,0 +1,190 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+var (
+	// htmlVoid are the elements that never have content or an end tag.
+	htmlVoid = map[string]bool{
+		"area": true, "base": true, "br": true, "col": true, "embed": true,
+		"hr": true, "img": true, "input": true, "link": true, "meta": true,
+		"source": true, "track": true, "wbr": true,
+	}
+
+	// htmlOptionalEnd are the elements whose end tags may be omitted.
+	htmlOptionalEnd = map[string]bool{
+		"html": true, "head": true, "body": true, "p": true, "li": true,
+		"dt": true, "dd": true, "option": true, "optgroup": true, "tr": true,
+		"td": true, "th": true, "thead": true, "tbody": true, "tfoot": true,
+		"colgroup": true, "caption": true, "rt": true, "rp": true,
+	}
+
+	// htmlRawText are the elements whose content is not parsed as markup.
+	htmlRawText = map[string]bool{
+		"script": true, "style": true, "textarea": true, "title": true,
+	}
+)
+
+// htmlElement is an open element awaiting its end tag.
+type htmlElement struct {
+	name string
+	line int
+}
+
+// htmlTagName extracts the lowercase tag name from the text following '<' or '</'.
+func htmlTagName(text []byte) string {
+	end := bytes.IndexFunc(text, func(r rune) bool {
+		return r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '/' || r == '>'
+	})
+
+	if end < 0 {
+		end = len(text)
+	}
+
+	return strings.ToLower(string(text[:end]))
+}
+
+// ValidateHTML checks that output is well-formed HTML:  every tag is terminated,
+// and every element is closed in the proper order.  Void elements and elements
+// whose end tags are optional in HTML are allowed to remain open.
+func ValidateHTML(output []byte) error {
+	var (
+		stack []htmlElement
+		line  = 1
+		pos   = 0
+	)
+
+	advance := func(n int) {
+		line += bytes.Count(output[pos:pos+n], []byte{'\n'})
+		pos += n
+	}
+
+	for pos < len(output) {
+		next := bytes.IndexByte(output[pos:], '<')
+		if next < 0 {
+			break
+		}
+
+		advance(next)
+		rest := output[pos:]
+		switch {
+		case bytes.HasPrefix(rest, []byte("<!--")):
+			end := bytes.Index(rest, []byte("-->"))
+			if end < 0 {
+				return fmt.Errorf("line %d: unterminated comment", line)
+			}
+
+			advance(end + 3)
+
+		case bytes.HasPrefix(rest, []byte("<!")) || bytes.HasPrefix(rest, []byte("<?")):
+			end := bytes.IndexByte(rest, '>')
+			if end < 0 {
+				return fmt.Errorf("line %d: unterminated declaration", line)
+			}
+
+			advance(end + 1)
+
+		case bytes.HasPrefix(rest, []byte("</")):
+			end := bytes.IndexByte(rest, '>')
+			if end < 0 {
+				return fmt.Errorf("line %d: unterminated end tag", line)
+			}
+
+			name := htmlTagName(rest[2:end])
+			if err := htmlClose(&stack, name, line); err != nil {
+				return err
+			}
+
+			advance(end + 1)
+
+		default:
+			name := htmlTagName(rest[1:])
+			if len(name) == 0 || !isHTMLNameStart(name[0]) {
+				// a bare '<' in text
+				advance(1)
+				continue
+			}
+
+			end := htmlTagEnd(rest)
+			if end < 0 {
+				return fmt.Errorf("line %d: unterminated <%s> tag", line, name)
+			}
+
+			selfClosing := rest[end-1] == '/'
+			startLine := line
+			advance(end + 1)
+			if htmlVoid[name] || selfClosing {
+				continue
+			}
+
+			if htmlRawText[name] {
+				closing := []byte("</" + name)
+				i := bytes.Index(bytes.ToLower(output[pos:]), closing)
+				if i < 0 {
+					return fmt.Errorf("line %d: <%s> is never closed", startLine, name)
+				}
+
+				advance(i)
+			}
+
+			stack = append(stack, htmlElement{name: name, line: startLine})
+		}
+	}
+
+	for i := len(stack) - 1; i >= 0; i-- {
+		if !htmlOptionalEnd[stack[i].name] {
+			return fmt.Errorf("line %d: <%s> is never closed", stack[i].line, stack[i].name)
+		}
+	}
+
+	return nil
+}
+
+func isHTMLNameStart(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// htmlTagEnd finds the '>' that ends a start tag, skipping quoted attribute values.
+func htmlTagEnd(tag []byte) int {
+	var quote byte
+	for i := 1; i < len(tag); i++ {
+		switch c := tag[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+
+		case c == '"' || c == '\'':
+			quote = c
+
+		case c == '>':
+			return i
+		}
+	}
+
+	return -1
+}
+
+// htmlClose pops the element that an end tag closes.  Any elements opened after it
+// must have optional end tags.
+func htmlClose(stack *[]htmlElement, name string, line int) error {
+	s := *stack
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i].name == name {
+			*stack = s[:i]
+			return nil
+		}
+
+		if !htmlOptionalEnd[s[i].name] {
+			return fmt.Errorf("line %d: </%s> does not match <%s> opened on line %d", line, name, s[i].name, s[i].line)
+		}
+	}
+
+	return fmt.Errorf("line %d: </%s> has no matching start tag", line, name)
+}
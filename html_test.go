@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateHTML(t *testing.T) {
+	testCases := []struct {
+		name   string
+		output string
+		err    string
+	}{
+		{name: "Empty", output: ""},
+		{name: "Text", output: "just text"},
+		{name: "Document", output: "<!DOCTYPE html>\n<html><head><title>a < b</title></head>\n<body><p>text</p></body></html>"},
+		{name: "VoidElements", output: "<div><br><img src=\"a.png\"><input type=text></div>"},
+		{name: "SelfClosing", output: "<div><span/></div>"},
+		{name: "OptionalEnds", output: "<ul><li>one<li>two</ul><p>paragraph"},
+		{name: "QuotedBracket", output: `<a title="x > y" href='#'>link</a>`},
+		{name: "Comment", output: "<!-- <div> --><p></p>"},
+		{name: "ProcessingInstruction", output: "<?xml version=\"1.0\"?><div></div>"},
+		{name: "RawText", output: "<script>if (a < b && c > d) { x = '</div>'; }</script>"},
+		{name: "UppercaseRawText", output: "<SCRIPT>a < b</SCRIPT>"},
+		{name: "BareLessThan", output: "<p>1 < 2</p>"},
+		{name: "CaseInsensitive", output: "<DIV></div>"},
+		{
+			name:   "Unclosed",
+			output: "<div>\n<span>text</div>",
+			err:    "line 2: </div> does not match <span> opened on line 2",
+		},
+		{
+			name:   "NeverClosed",
+			output: "<div>\n\n<section>",
+			err:    "line 3: <section> is never closed",
+		},
+		{
+			name:   "UnmatchedEnd",
+			output: "<p>text</p>\n</div>",
+			err:    "line 2: </div> has no matching start tag",
+		},
+		{
+			name:   "UnterminatedTag",
+			output: "<div class=\"a\"",
+			err:    "line 1: unterminated <div> tag",
+		},
+		{
+			name:   "UnterminatedEndTag",
+			output: "<div></div",
+			err:    "unterminated end tag",
+		},
+		{
+			name:   "UnterminatedComment",
+			output: "<!-- comment",
+			err:    "unterminated comment",
+		},
+		{
+			name:   "UnterminatedDeclaration",
+			output: "<!DOCTYPE html",
+			err:    "unterminated declaration",
+		},
+		{
+			name:   "UnclosedRawText",
+			output: "<script>x = 1;",
+			err:    "<script> is never closed",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := ValidateHTML([]byte(testCase.output))
+			switch {
+			case len(testCase.err) == 0 && err != nil:
+				t.Errorf("unexpected error: %s", err)
+
+			case len(testCase.err) > 0 && (err == nil || !strings.Contains(err.Error(), testCase.err)):
+				t.Errorf("expected an error containing %q, got %v", testCase.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"text/template/parse"
+)
+
+// ErrIncludesWithoutFS indicates that a ParserConfig had Includes but no FS to read them from.
+var ErrIncludesWithoutFS = errors.New("includes require a file system")
+
+// RedefinitionError indicates that a template definition was made in more than one file.
+type RedefinitionError struct {
+	// Name is the name of the redefined template or partial.
+	Name string
+
+	// File is the file with the redefinition.
+	File string
+
+	// Previous is the file with the original definition.
+	Previous string
+}
+
+// Error satisfies the error interface.  The format matches the errors from
+// the golang template packages.
+func (re *RedefinitionError) Error() string {
+	return fmt.Sprintf("template: %s: redefinition of template %q, which is already defined in %s", re.File, re.Name, re.Previous)
+}
+
+// include is a file that is parsed once into a Parser's prototype.
+type include struct {
+	name    string
+	content string
+}
+
+// readIncludes reads the files from the configured FS that match any of the
+// Includes patterns, in lexical order.
+func readIncludes(c ParserConfig) ([]include, error) {
+	if len(c.Includes) == 0 {
+		return nil, nil
+	} else if c.FS == nil {
+		return nil, ErrIncludesWithoutFS
+	}
+
+	m, err := ParsePatterns(c.Includes...)
+	if err != nil {
+		return nil, err
+	}
+
+	var includes []include
+	err = fs.WalkDir(c.FS, ".", func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || !m.Match(path) {
+			return err
+		}
+
+		content, err := fs.ReadFile(c.FS, path)
+		if err == nil {
+			includes = append(includes, include{name: path, content: string(content)})
+		}
+
+		return err
+	})
+
+	return includes, err
+}
+
+// definitions maps the names of the templates defined by includes
+// onto the files that define them.
+type definitions map[string]string
+
+// golangDefinitions returns the names of the nonempty templates that golang
+// template source defines, not counting the source itself.
+func golangDefinitions(name, content, leftDelim, rightDelim string) ([]string, error) {
+	trees := make(map[string]*parse.Tree)
+	t := parse.New(name)
+	t.Mode = parse.SkipFuncCheck
+	if _, err := t.Parse(content, leftDelim, rightDelim, trees); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(trees))
+	for n, tree := range trees {
+		if n != name && !parse.IsEmptyTree(tree.Root) {
+			names = append(names, n)
+		}
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+// check returns a RedefinitionError if a file defines any name that some other
+// file has already defined.
+func (d definitions) check(file string, names []string) error {
+	for _, n := range names {
+		if previous, exists := d[n]; exists && previous != file {
+			return &RedefinitionError{Name: n, File: file, Previous: previous}
+		}
+	}
+
+	return nil
+}
+
+// add records the names a file defines, after checking them for redefinitions.
+func (d definitions) add(file string, names []string) error {
+	err := d.check(file, names)
+	if err == nil {
+		for _, n := range names {
+			d[n] = file
+		}
+	}
+
+	return err
+}
+
+// parseIncludes reads the configured includes and checks their golang template
+// definitions for redefinitions.  Each include is then passed to the add closure,
+// which adds it to a prototype.
+func parseIncludes(c ParserConfig, add func(name, content string) error) (definitions, error) {
+	includes, err := readIncludes(c)
+	defs := make(definitions)
+	for i := 0; err == nil && i < len(includes); i++ {
+		var names []string
+		names, err = golangDefinitions(includes[i].name, includes[i].content, c.LeftDelim, c.RightDelim)
+		if err == nil {
+			err = defs.add(includes[i].name, names)
+		}
+
+		if err == nil {
+			err = add(includes[i].name, includes[i].content)
+		}
+	}
+
+	return defs, err
+}
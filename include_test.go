@@ -0,0 +1,219 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// testIncludes are the shared files available to tests.
+var testIncludes = fstest.MapFS{
+	"includes/a.inc":    {Data: []byte(`{{define "greet"}}hello, {{.}}{{end}}{{define "empty"}}{{end}}`)},
+	"includes/b.inc":    {Data: []byte(`{{define "shout"}}{{template "greet" .}}!{{end}}`)},
+	"includes/html.inc": {Data: []byte(`{{define "link"}}<a href="{{.}}">{{.}}</a>{{end}}`)},
+	"includes/json.inc": {Data: []byte(`{{define "obj"}}{"v": "{{.}}"}{{end}}`)},
+	"other/c.inc":       {Data: []byte(`{{define "greet"}}again{{end}}`)},
+	"other/bad.inc":     {Data: []byte(`{{define "x"}}`)},
+	"ignored.txt":       {Data: []byte(`{{define "greet"}}ignored{{end}}`)},
+}
+
+func TestGolangDefinitions(t *testing.T) {
+	testCases := []struct {
+		content  string
+		expected []string
+		err      bool
+	}{
+		{content: "no definitions"},
+		{content: `{{define "b"}}b{{end}}{{define "a"}}a{{end}}`, expected: []string{"a", "b"}},
+		{content: `{{define "empty"}}{{end}}{{block "blk" .}}x{{end}}`, expected: []string{"blk"}},
+		{content: `{{nosuchfunc}}{{define "a"}}a{{end}}`, expected: []string{"a"}},
+		{content: `{{define "a"}}`, err: true},
+	}
+
+	for _, testCase := range testCases {
+		names, err := golangDefinitions("t", testCase.content, "", "")
+		switch {
+		case testCase.err:
+			if err == nil {
+				t.Errorf("%q: expected an error", testCase.content)
+			}
+
+		case err != nil || strings.Join(names, ",") != strings.Join(testCase.expected, ","):
+			t.Errorf("%q: expected %v, got %v (%v)", testCase.content, testCase.expected, names, err)
+		}
+	}
+}
+
+func TestDefinitions(t *testing.T) {
+	defs := make(definitions)
+	if err := defs.add("a.inc", []string{"x", "y"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err := defs.check("a.inc", []string{"x"}); err != nil {
+		t.Errorf("a file may define its own names again: %s", err)
+	}
+
+	var re *RedefinitionError
+	err := defs.add("b.inc", []string{"z", "y"})
+	if !errors.As(err, &re) || re.Name != "y" || re.File != "b.inc" || re.Previous != "a.inc" {
+		t.Fatalf("expected a RedefinitionError, got %v", err)
+	}
+
+	if err.Error() != `template: b.inc: redefinition of template "y", which is already defined in a.inc` {
+		t.Errorf("unexpected error text: %s", err)
+	}
+
+	if _, exists := defs["z"]; exists {
+		t.Error("a failed add should record nothing")
+	}
+}
+
+func TestReadIncludes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		config   ParserConfig
+		expected []string
+		err      error
+	}{
+		{name: "None", config: ParserConfig{FS: testIncludes}},
+		{name: "Ordered", config: ParserConfig{FS: testIncludes, Includes: []string{"other/c.inc", "includes/*.inc"}}, expected: []string{"includes/a.inc", "includes/b.inc", "includes/html.inc", "includes/json.inc", "other/c.inc"}},
+		{name: "NoMatches", config: ParserConfig{FS: testIncludes, Includes: []string{"nosuch/*"}}},
+		{name: "WithoutFS", config: ParserConfig{Includes: []string{"*.inc"}}, err: ErrIncludesWithoutFS},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			includes, err := readIncludes(testCase.config)
+			if !errors.Is(err, testCase.err) {
+				t.Fatalf("expected %v, got %v", testCase.err, err)
+			}
+
+			names := make([]string, 0, len(includes))
+			for _, inc := range includes {
+				names = append(names, inc.name)
+				if string(testIncludes[inc.name].Data) != inc.content {
+					t.Errorf("%s: unexpected content %q", inc.name, inc.content)
+				}
+			}
+
+			if strings.Join(names, ",") != strings.Join(testCase.expected, ",") {
+				t.Errorf("expected %v, got %v", testCase.expected, names)
+			}
+		})
+	}
+}
+
+func TestParserIncludes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		config   ParserConfig
+		template string
+		expected string
+		err      string
+	}{
+		{
+			name:     "Text",
+			config:   ParserConfig{Includes: []string{"includes/*.inc"}},
+			template: `{{template "shout" .x}}`,
+			expected: "hello, <b>!",
+		},
+		{
+			name:     "HTML",
+			config:   ParserConfig{HTML: true, Includes: []string{"includes/*.inc"}},
+			template: `{{template "link" .x}}`,
+			expected: `<a href="%3cb%3e">&lt;b&gt;</a>`,
+		},
+		{
+			name:     "JSON",
+			config:   ParserConfig{JSON: true, Includes: []string{"includes/*.inc"}},
+			template: `[{{template "obj" .q}}]`,
+			expected: `[{"v": "\""}]`,
+		},
+		{
+			name:     "RedefineEmpty",
+			config:   ParserConfig{Includes: []string{"includes/*.inc"}},
+			template: `{{define "empty"}}filled{{end}}{{template "empty"}}`,
+			expected: "filled",
+		},
+		{
+			name:     "IncludesAreIsolated",
+			config:   ParserConfig{Includes: []string{"includes/*.inc"}},
+			template: `{{define "local"}}local{{end}}{{template "local"}}`,
+			expected: "local",
+		},
+		{
+			name:   "RedefinitionAcrossIncludes",
+			config: ParserConfig{Includes: []string{"includes/*.inc", "other/c.inc"}},
+			err:    `template: other/c.inc: redefinition of template "greet", which is already defined in includes/a.inc`,
+		},
+		{
+			name:     "RedefinitionInTemplate",
+			config:   ParserConfig{Includes: []string{"includes/*.inc"}},
+			template: `{{define "greet"}}mine{{end}}`,
+			err:      `template: t: redefinition of template "greet", which is already defined in includes/a.inc`,
+		},
+		{
+			name:   "BadInclude",
+			config: ParserConfig{Includes: []string{"other/bad.inc"}},
+			err:    "template: other/bad.inc:1: unexpected EOF",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.config.FS = testIncludes
+			p, err := NewParser(testCase.config)
+			var tmpl Template
+			if err == nil {
+				tmpl, err = p.Parse("t", testCase.template)
+			}
+
+			if len(testCase.err) > 0 {
+				if err == nil || err.Error() != testCase.err {
+					t.Errorf("expected %q, got %v", testCase.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unable to parse template: %s", err)
+			}
+
+			var o strings.Builder
+			if err := tmpl.Execute(&o, Model{"x": "<b>", "q": `"`}); err != nil || o.String() != testCase.expected {
+				t.Errorf("expected %q, got %q (%v)", testCase.expected, o.String(), err)
+			}
+		})
+	}
+
+	// each template parsed from the same Parser starts from the same includes
+	p, err := NewParser(ParserConfig{FS: testIncludes, Includes: []string{"includes/*.inc"}})
+	if err != nil {
+		t.Fatalf("unable to create parser: %s", err)
+	}
+
+	first, err := p.Parse("first", `{{define "local"}}first{{end}}{{template "local"}}`)
+	if err != nil {
+		t.Fatalf("unable to parse template: %s", err)
+	}
+
+	second, err := p.Parse("second", `{{template "local"}}`)
+	if err != nil {
+		t.Fatalf("unable to parse template: %s", err)
+	}
+
+	var o strings.Builder
+	if err := first.Execute(&o, nil); err != nil || o.String() != "first" {
+		t.Errorf("expected first, got %q (%v)", o.String(), err)
+	}
+
+	if err := second.Execute(&o, nil); err == nil {
+		t.Error("a definition in one template should not be visible to another")
+	}
+}
@@ -0,0 +1,144 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// defaultLeftDelim is the golang template left delimiter when none is configured.
+	defaultLeftDelim = "{{"
+
+	// defaultRightDelim is the golang template right delimiter when none is configured.
+	defaultRightDelim = "}}"
+)
+
+// ErrLayoutWithoutFS indicates that a template extends a layout, but its ParserConfig
+// has no FS to read the layout from.
+var ErrLayoutWithoutFS = errors.New("layouts require a file system")
+
+// LayoutCycleError indicates that a chain of layouts eventually extends itself.
+type LayoutCycleError struct {
+	// Chain is the sequence of templates and layouts, beginning with the template
+	// being parsed and ending with the first repeated layout.
+	Chain []string
+}
+
+// Error satisfies the error interface.
+func (lce *LayoutCycleError) Error() string {
+	return fmt.Sprintf("template: %s: layout cycle: %s", lce.Chain[0], strings.Join(lce.Chain, " -> "))
+}
+
+// LayoutSelectionError indicates that a template extends a layout that its Selector
+// assigns to a different configuration.  A layout is parsed into the same template
+// set as the templates that extend it, so it must be parsed by the same Parser.
+type LayoutSelectionError struct {
+	// Name is the template that extends the layout.
+	Name string
+
+	// Layout is the layout that is selected by a different configuration.
+	Layout string
+}
+
+// Error satisfies the error interface.
+func (lse *LayoutSelectionError) Error() string {
+	return fmt.Sprintf("template: %s: layout %s is selected by a different configuration", lse.Name, lse.Layout)
+}
+
+// layoutFile is one file in a chain of layouts.
+type layoutFile struct {
+	name    string
+	content string
+}
+
+// layouts resolves the extends directives in golang templates.  A template extends
+// a layout with a comment at its very beginning, e.g. {{/* extends "layouts/base.tmpl" */}}.
+type layouts struct {
+	fsys     fs.FS
+	selector *matchSelector
+	extends  *regexp.Regexp
+}
+
+// newLayouts creates the layout resolver for a configuration's file system and delimiters.
+func newLayouts(c ParserConfig) layouts {
+	left, right := c.LeftDelim, c.RightDelim
+	if len(left) == 0 {
+		left = defaultLeftDelim
+	}
+
+	if len(right) == 0 {
+		right = defaultRightDelim
+	}
+
+	return layouts{
+		fsys:     c.FS,
+		selector: c.selector,
+		extends: regexp.MustCompile(
+			`^\s*` + regexp.QuoteMeta(left) + `-?\s*/\*\s*extends\s+("(?:[^"\\]|\\.)*")\s*\*/\s*-?` + regexp.QuoteMeta(right),
+		),
+	}
+}
+
+// layout returns the name of the layout that some template source extends,
+// or the empty string if the source does not extend a layout.
+func (l layouts) layout(content string) (string, error) {
+	m := l.extends.FindStringSubmatch(content)
+	if m == nil {
+		return "", nil
+	}
+
+	return strconv.Unquote(m[1])
+}
+
+// chain returns the files that make up a template, beginning with the outermost
+// layout and ending with the template itself.  A template that does not extend
+// a layout is its own chain.
+//
+// The outermost layout is the template that is executed, but the parsed template
+// keeps the name of the template that extends it.  The golang template types hold
+// that name themselves, rather than a decorator renaming the layout, so that the
+// per-execution methods of the executed template are not hidden behind a wrapper.
+func (l layouts) chain(name, content string) ([]layoutFile, error) {
+	var (
+		chain   = []layoutFile{{name: name, content: content}}
+		visited = map[string]bool{name: true}
+		names   = []string{name}
+	)
+
+	for {
+		parent, err := l.layout(chain[0].content)
+		switch {
+		case err != nil:
+			return nil, fmt.Errorf("template: %s: invalid extends directive: %w", chain[0].name, err)
+
+		case len(parent) == 0:
+			return chain, nil
+
+		case l.fsys == nil:
+			return nil, ErrLayoutWithoutFS
+
+		case !l.selector.sameParser(name, parent):
+			return nil, &LayoutSelectionError{Name: chain[0].name, Layout: parent}
+		}
+
+		names = append(names, parent)
+		if visited[parent] {
+			return nil, &LayoutCycleError{Chain: names}
+		}
+
+		visited[parent] = true
+		data, err := fs.ReadFile(l.fsys, parent)
+		if err != nil {
+			return nil, fmt.Errorf("template: %s: unable to read layout %s: %w", chain[0].name, parent, err)
+		}
+
+		chain = append([]layoutFile{{name: parent, content: string(data)}}, chain...)
+	}
+}
@@ -0,0 +1,246 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// testLayouts are the layouts available to tests.
+var testLayouts = fstest.MapFS{
+	"layouts/base.tmpl":   {Data: []byte(`<html>{{block "title" .}}untitled{{end}}|{{block "body" .}}{{end}}</html>`)},
+	"layouts/page.tmpl":   {Data: []byte(`{{/* extends "layouts/base.tmpl" */}}{{define "body"}}<main>{{block "content" .}}{{end}}</main>{{end}}`)},
+	"layouts/square.tmpl": {Data: []byte(`[[/* extends "layouts/base.tmpl" */]]`)},
+	"layouts/a.tmpl":      {Data: []byte(`{{/* extends "layouts/b.tmpl" */}}`)},
+	"layouts/b.tmpl":      {Data: []byte(`{{/* extends "layouts/a.tmpl" */}}`)},
+	"layouts/bad.tmpl":    {Data: []byte(`{{/* extends "layouts/base.tmpl" */}}{{define "body"}}`)},
+}
+
+func TestLayoutsLayout(t *testing.T) {
+	testCases := []struct {
+		name     string
+		config   ParserConfig
+		content  string
+		expected string
+		err      bool
+	}{
+		{name: "None", content: "no layout"},
+		{name: "Extends", content: `{{/* extends "layouts/base.tmpl" */}}rest`, expected: "layouts/base.tmpl"},
+		{name: "Whitespace", content: "\n  {{- /*   extends   \"x.tmpl\"   */ -}}", expected: "x.tmpl"},
+		{name: "Escapes", content: `{{/* extends "a\"b.tmpl" */}}`, expected: `a"b.tmpl`},
+		{name: "NotFirst", content: `text{{/* extends "x.tmpl" */}}`},
+		{name: "Delimiters", config: ParserConfig{LeftDelim: "[[", RightDelim: "]]"}, content: `[[/* extends "x.tmpl" */]]`, expected: "x.tmpl"},
+		{name: "WrongDelimiters", config: ParserConfig{LeftDelim: "[[", RightDelim: "]]"}, content: `{{/* extends "x.tmpl" */}}`},
+		{name: "BadQuote", content: `{{/* extends "\q" */}}`, err: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			actual, err := newLayouts(testCase.config).layout(testCase.content)
+			switch {
+			case testCase.err:
+				if err == nil {
+					t.Error("expected an error")
+				}
+
+			case err != nil || actual != testCase.expected:
+				t.Errorf("expected %q, got %q (%v)", testCase.expected, actual, err)
+			}
+		})
+	}
+}
+
+func TestLayoutsChain(t *testing.T) {
+	testCases := []struct {
+		name     string
+		fsys     fs.FS
+		content  string
+		expected []string
+		err      string
+	}{
+		{name: "NoLayout", content: "plain", expected: []string{"t"}},
+		{name: "One", fsys: testLayouts, content: `{{/* extends "layouts/base.tmpl" */}}`, expected: []string{"layouts/base.tmpl", "t"}},
+		{name: "Nested", fsys: testLayouts, content: `{{/* extends "layouts/page.tmpl" */}}`, expected: []string{"layouts/base.tmpl", "layouts/page.tmpl", "t"}},
+		{name: "WithoutFS", content: `{{/* extends "layouts/base.tmpl" */}}`, err: ErrLayoutWithoutFS.Error()},
+		{name: "Missing", fsys: testLayouts, content: `{{/* extends "nosuch.tmpl" */}}`, err: "template: t: unable to read layout nosuch.tmpl: "},
+		{name: "Cycle", fsys: testLayouts, content: `{{/* extends "layouts/a.tmpl" */}}`, err: "template: t: layout cycle: t -> layouts/a.tmpl -> layouts/b.tmpl -> layouts/a.tmpl"},
+		{name: "SelfCycle", fsys: testLayouts, content: `{{/* extends "t" */}}`, err: "template: t: layout cycle: t -> t"},
+		{name: "InvalidDirective", fsys: testLayouts, content: `{{/* extends "\q" */}}`, err: "template: t: invalid extends directive: "},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			chain, err := newLayouts(ParserConfig{FS: testCase.fsys}).chain("t", testCase.content)
+			if len(testCase.err) > 0 {
+				if err == nil || !strings.HasPrefix(err.Error(), testCase.err) {
+					t.Errorf("expected an error beginning with %q, got %v", testCase.err, err)
+				}
+
+				return
+			}
+
+			names := make([]string, 0, len(chain))
+			for _, lf := range chain {
+				names = append(names, lf.name)
+			}
+
+			if err != nil || strings.Join(names, ",") != strings.Join(testCase.expected, ",") {
+				t.Errorf("expected %v, got %v (%v)", testCase.expected, names, err)
+			}
+
+			if chain[len(chain)-1].content != testCase.content {
+				t.Errorf("the chain should end with the template itself, got %q", chain[len(chain)-1].content)
+			}
+		})
+	}
+
+	var lce *LayoutCycleError
+	_, err := newLayouts(ParserConfig{FS: testLayouts}).chain("t", `{{/* extends "layouts/a.tmpl" */}}`)
+	if !errors.As(err, &lce) || len(lce.Chain) != 4 {
+		t.Errorf("expected a LayoutCycleError, got %v", err)
+	}
+}
+
+func TestParserLayouts(t *testing.T) {
+	testCases := []struct {
+		name     string
+		config   ParserConfig
+		template string
+		expected string
+		err      string
+	}{
+		{
+			name:     "Defaults",
+			template: `{{/* extends "layouts/base.tmpl" */}}ignored`,
+			expected: "<html>untitled|</html>",
+		},
+		{
+			name:     "Overrides",
+			template: `{{/* extends "layouts/base.tmpl" */}}{{define "title"}}{{.title}}{{end}}{{define "body"}}body{{end}}`,
+			expected: "<html><T>|body</html>",
+		},
+		{
+			name:     "Nested",
+			template: `{{/* extends "layouts/page.tmpl" */}}{{define "content"}}content{{end}}`,
+			expected: "<html>untitled|<main>content</main></html>",
+		},
+		{
+			name:     "HTML",
+			config:   ParserConfig{HTML: true},
+			template: `{{/* extends "layouts/base.tmpl" */}}{{define "title"}}{{.title}}{{end}}`,
+			expected: "<html>&lt;T&gt;|</html>",
+		},
+		{
+			name:     "Delimiters",
+			config:   ParserConfig{LeftDelim: "[[", RightDelim: "]]"},
+			template: `[[/* extends "layouts/square.tmpl" */]][[define "body"]]square[[end]]`,
+			expected: `<html>{{block "title" .}}untitled{{end}}|{{block "body" .}}{{end}}</html>`,
+		},
+		{
+			name:     "Cycle",
+			template: `{{/* extends "layouts/a.tmpl" */}}`,
+			err:      "template: t: layout cycle: t -> layouts/a.tmpl -> layouts/b.tmpl -> layouts/a.tmpl",
+		},
+		{
+			name:     "BadLayout",
+			template: `{{/* extends "layouts/bad.tmpl" */}}`,
+			err:      "template: layouts/bad.tmpl:1: unexpected EOF",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.config.FS = testLayouts
+			p, err := NewParser(testCase.config)
+			if err != nil {
+				t.Fatalf("unable to create parser: %s", err)
+			}
+
+			tmpl, err := p.Parse("t", testCase.template)
+			if len(testCase.err) > 0 {
+				if err == nil || err.Error() != testCase.err {
+					t.Errorf("expected %q, got %v", testCase.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unable to parse template: %s", err)
+			}
+
+			if tmpl.Name() != "t" {
+				t.Errorf("the template should keep its own name, got %s", tmpl.Name())
+			}
+
+			var o strings.Builder
+			if err := tmpl.Execute(&o, Model{"title": "<T>"}); err != nil || o.String() != testCase.expected {
+				t.Errorf("expected %q, got %q (%v)", testCase.expected, o.String(), err)
+			}
+		})
+	}
+}
+
+func TestSelectorLayouts(t *testing.T) {
+	fsys := fstest.MapFS{
+		"layouts/base.tmpl": {Data: []byte(`<p>{{block "body" .}}{{end}}</p>`)},
+		"layouts/base.html": {Data: []byte(`<p>{{block "body" .}}{{end}}</p>`)},
+		"shared/base.txt":   {Data: []byte(`[{{block "body" .}}{{end}}]`)},
+	}
+
+	selector, err := NewSelector(
+		SelectorConfig{Patterns: []string{"*.tmpl", "layouts/*.tmpl"}, Parser: ParserConfig{FS: fsys}},
+		SelectorConfig{Patterns: []string{"*.html", "layouts/*.html"}, Parser: ParserConfig{FS: fsys, HTML: true}},
+	)
+
+	if err != nil {
+		t.Fatalf("unable to create selector: %s", err)
+	}
+
+	testCases := []struct {
+		name     string
+		template string
+		layout   string
+		expected string
+		err      error
+	}{
+		{name: "SameConfiguration", template: "page.tmpl", layout: "layouts/base.tmpl", expected: "<p><b></p>"},
+		{name: "HTML", template: "page.html", layout: "layouts/base.html", expected: "<p>&lt;b&gt;</p>"},
+		{name: "NotSelected", template: "page.tmpl", layout: "shared/base.txt", expected: "[<b>]"},
+		{name: "DifferentConfiguration", template: "page.tmpl", layout: "layouts/base.html", err: &LayoutSelectionError{Name: "page.tmpl", Layout: "layouts/base.html"}},
+		{name: "DifferentEngine", template: "page.html", layout: "layouts/base.tmpl", err: &LayoutSelectionError{Name: "page.html", Layout: "layouts/base.tmpl"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			p, found := selector.Select(testCase.template)
+			if !found {
+				t.Fatalf("no parser selected for %s", testCase.template)
+			}
+
+			tmpl, err := p.Parse(testCase.template, `{{/* extends "`+testCase.layout+`" */}}{{define "body"}}{{.}}{{end}}`)
+			if testCase.err != nil {
+				var lse *LayoutSelectionError
+				if !errors.As(err, &lse) || *lse != *testCase.err.(*LayoutSelectionError) {
+					t.Errorf("expected %v, got %v", testCase.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unable to parse template: %s", err)
+			}
+
+			var o strings.Builder
+			if err := tmpl.Execute(&o, "<b>"); err != nil || o.String() != testCase.expected {
+				t.Errorf("expected %q, got %q (%v)", testCase.expected, o.String(), err)
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"text/template/parse"
+	"time"
+)
+
+const (
+	// checkFunc is the function called at the beginning of each range body,
+	// which stops execution once the context is done.
+	checkFunc = "_thoth_check"
+
+	// enterFunc is the function called at the beginning of each template,
+	// which tracks the depth of nested template invocations.
+	enterFunc = "_thoth_enter"
+
+	// exitFunc is the function called at the end of each template.
+	exitFunc = "_thoth_exit"
+)
+
+// limitFuncs are the functions installed in prototypes.  They do nothing, and are
+// replaced with working functions for executions that need them.
+var limitFuncs = map[string]interface{}{
+	checkFunc: func() bool { return false },
+	enterFunc: func(string) bool { return false },
+	exitFunc:  func() bool { return false },
+}
+
+// OutputLimitError indicates that an execution tried to write more than the
+// configured maximum output.
+type OutputLimitError struct {
+	MaxOutput int64
+}
+
+// Error satisfies the error interface.
+func (ole *OutputLimitError) Error() string {
+	return fmt.Sprintf("output exceeds the maximum of %d bytes", ole.MaxOutput)
+}
+
+// TemplateDepthError indicates that template invocations were nested too deeply,
+// which usually means that a template invokes itself without end.
+type TemplateDepthError struct {
+	// Name is the template that could not be invoked.
+	Name string
+
+	// MaxDepth is the maximum depth of nested template invocations.
+	MaxDepth int
+}
+
+// Error satisfies the error interface.
+func (tde *TemplateDepthError) Error() string {
+	return fmt.Sprintf("cannot invoke %q: exceeded the maximum template depth of %d", tde.Name, tde.MaxDepth)
+}
+
+// limitWriter enforces a maximum output size and stops writing once its context is done.
+type limitWriter struct {
+	ctx       context.Context
+	w         io.Writer
+	maxOutput int64
+	written   int64
+}
+
+func (lw *limitWriter) Write(p []byte) (int, error) {
+	if err := lw.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	if lw.maxOutput > 0 && lw.written+int64(len(p)) > lw.maxOutput {
+		return 0, &OutputLimitError{MaxOutput: lw.maxOutput}
+	}
+
+	n, err := lw.w.Write(p)
+	lw.written += int64(n)
+	return n, err
+}
+
+// limits are the resource bounds for executing templates.
+type limits struct {
+	timeout         time.Duration
+	maxOutput       int64
+	maxDepth        int
+	maxIncludeDepth int
+}
+
+// newLimits returns the limits from a configuration.
+func newLimits(c ParserConfig) limits {
+	l := limits{
+		timeout:         c.Timeout,
+		maxOutput:       c.MaxOutput,
+		maxDepth:        c.MaxDepth,
+		maxIncludeDepth: c.MaxIncludeDepth,
+	}
+
+	if l.maxIncludeDepth <= 0 {
+		l.maxIncludeDepth = DefaultMaxIncludeDepth
+	}
+
+	return l
+}
+
+// context checks that a context is not done, then applies the timeout to it.
+// The returned cancel function must always be called.
+func (l limits) context(ctx context.Context) (context.Context, context.CancelFunc, error) {
+	if err := ctx.Err(); err != nil {
+		return ctx, func() {}, err
+	}
+
+	if l.timeout > 0 {
+		ctx, cancel := context.WithTimeout(ctx, l.timeout)
+		return ctx, cancel, nil
+	}
+
+	return ctx, func() {}, nil
+}
+
+// writer limits output to the maximum size, and until the context is done.
+func (l limits) writer(ctx context.Context, w io.Writer) io.Writer {
+	if l.maxOutput <= 0 && ctx.Done() == nil {
+		return w
+	}
+
+	return &limitWriter{
+		ctx:       ctx,
+		w:         w,
+		maxOutput: l.maxOutput,
+	}
+}
+
+// stateful tests whether an execution with the given context needs the functions
+// that enforce limits, rather than the functions installed in prototypes that do nothing.
+func (l limits) stateful(ctx context.Context) bool {
+	return ctx.Done() != nil || l.maxDepth > 0
+}
+
+// execState is the state of one execution of a golang template, which the
+// functions that enforce limits read.
+type execState struct {
+	ctx   context.Context
+	depth int
+
+	// err is the first error returned by a function that enforces limits
+	err error
+}
+
+// fail records the first error returned by a function that enforces limits.
+func (state *execState) fail(err error) error {
+	if state.err == nil {
+		state.err = err
+	}
+
+	return err
+}
+
+// result is the error for an execution that returned err.  The golang template packages
+// wrap errors from functions with the location of the call, which for the functions that
+// enforce limits is only an internal detail, so the error those functions returned is
+// reported instead.
+func (state *execState) result(err error) error {
+	if err != nil && state.err != nil {
+		return state.err
+	}
+
+	return err
+}
+
+// funcs returns the functions that enforce limits for executions with the given state.
+// A template set with these functions installed must be executed by one execution at a time.
+func (l limits) funcs(state *execState) map[string]interface{} {
+	return map[string]interface{}{
+		checkFunc: func() (bool, error) {
+			if err := state.ctx.Err(); err != nil {
+				return false, state.fail(err)
+			}
+
+			return false, nil
+		},
+		enterFunc: func(name string) (bool, error) {
+			state.depth++
+			if l.maxDepth > 0 && state.depth > l.maxDepth {
+				return false, state.fail(&TemplateDepthError{Name: name, MaxDepth: l.maxDepth})
+			}
+
+			if err := state.ctx.Err(); err != nil {
+				return false, state.fail(err)
+			}
+
+			return false, nil
+		},
+		exitFunc: func() bool {
+			state.depth--
+			return false
+		},
+	}
+}
+
+// instrumenter inserts calls to the limit functions into a parse tree.  Each call
+// is the pipeline of an empty if, which produces no output in any escaping mode.
+type instrumenter struct {
+	check *parse.IfNode
+	enter *parse.IfNode
+	exit  *parse.IfNode
+}
+
+// newInstrumenter parses the calls inserted into a template once, and each insertion
+// copies them.  Printing an if, as error messages about the nodes that contain it do,
+// requires the tree the if was parsed from, and only the parser can provide one.
+func newInstrumenter(name string) instrumenter {
+	trees, _ := parse.Parse(
+		name,
+		fmt.Sprintf("{{if %s}}{{end}}{{if %s %s}}{{end}}{{if %s}}{{end}}", checkFunc, enterFunc, strconv.Quote(name), exitFunc),
+		"", "",
+		limitFuncs,
+	)
+
+	nodes := trees[name].Root.Nodes
+	return instrumenter{
+		check: nodes[0].(*parse.IfNode),
+		enter: nodes[1].(*parse.IfNode),
+		exit:  nodes[2].(*parse.IfNode),
+	}
+}
+
+// calls tests whether a node is an inserted call to a function.
+func calls(n parse.Node, f string) bool {
+	in, ok := n.(*parse.IfNode)
+	if !ok || in.Pipe == nil || len(in.Pipe.Cmds) != 1 || len(in.Pipe.Cmds[0].Args) == 0 {
+		return false
+	}
+
+	id, ok := in.Pipe.Cmds[0].Args[0].(*parse.IdentifierNode)
+	return ok && id.Ident == f
+}
+
+// list instruments the range bodies within a list.
+func (in instrumenter) list(l *parse.ListNode) {
+	if l == nil {
+		return
+	}
+
+	for _, n := range l.Nodes {
+		switch tn := n.(type) {
+		case *parse.IfNode:
+			in.list(tn.List)
+			in.list(tn.ElseList)
+
+		case *parse.WithNode:
+			in.list(tn.List)
+			in.list(tn.ElseList)
+
+		case *parse.RangeNode:
+			in.list(tn.List)
+			in.list(tn.ElseList)
+			if tn.List != nil && (len(tn.List.Nodes) == 0 || !calls(tn.List.Nodes[0], checkFunc)) {
+				tn.List.Nodes = append([]parse.Node{in.check.Copy()}, tn.List.Nodes...)
+			}
+		}
+	}
+}
+
+// root instruments a whole template.  Instrumenting is idempotent, so trees shared
+// with a prototype may be instrumented again.
+func (in instrumenter) root(r *parse.ListNode, depth bool) {
+	in.list(r)
+	if depth && !calls(r.Nodes[0], enterFunc) {
+		r.Nodes = append(append([]parse.Node{in.enter.Copy()}, r.Nodes...), in.exit.Copy())
+	}
+}
+
+// instrument inserts the calls to the limit functions into a template's tree.  Range
+// bodies always check for a done context.  When depth is set, the template also tracks
+// the depth of nested template invocations.  Empty templates are left empty, since
+// the golang template packages treat them specially.
+func instrument(name string, tree *parse.Tree, depth bool) {
+	if tree != nil && tree.Root != nil && !parse.IsEmptyTree(tree.Root) {
+		newInstrumenter(name).root(tree.Root, depth)
+	}
+}
@@ -0,0 +1,392 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"text/template/parse"
+	"time"
+)
+
+func TestLimitWriter(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	testCases := []struct {
+		name      string
+		ctx       context.Context
+		maxOutput int64
+		writes    []string
+		expected  string
+		err       error
+	}{
+		{name: "Unlimited", ctx: context.Background(), writes: []string{"abc", "def"}, expected: "abcdef"},
+		{name: "UnderLimit", ctx: context.Background(), maxOutput: 6, writes: []string{"abc", "def"}, expected: "abcdef"},
+		{name: "OverLimit", ctx: context.Background(), maxOutput: 5, writes: []string{"abc", "def"}, expected: "abc", err: &OutputLimitError{MaxOutput: 5}},
+		{name: "Canceled", ctx: canceled, writes: []string{"abc"}, err: context.Canceled},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var (
+				o   strings.Builder
+				w   = &limitWriter{ctx: testCase.ctx, w: &o, maxOutput: testCase.maxOutput}
+				err error
+			)
+
+			for i := 0; err == nil && i < len(testCase.writes); i++ {
+				_, err = w.Write([]byte(testCase.writes[i]))
+			}
+
+			var ole *OutputLimitError
+			switch {
+			case o.String() != testCase.expected:
+				t.Errorf("expected %q, got %q", testCase.expected, o.String())
+
+			case errors.As(testCase.err, &ole):
+				if !errors.As(err, &ole) || err.Error() != "output exceeds the maximum of 5 bytes" {
+					t.Errorf("expected %v, got %v", testCase.err, err)
+				}
+
+			case !errors.Is(err, testCase.err):
+				t.Errorf("expected %v, got %v", testCase.err, err)
+			}
+		})
+	}
+}
+
+func TestNewLimits(t *testing.T) {
+	l := newLimits(ParserConfig{Timeout: time.Second, MaxOutput: 10, MaxDepth: 3})
+	if l.timeout != time.Second || l.maxOutput != 10 || l.maxDepth != 3 || l.maxIncludeDepth != DefaultMaxIncludeDepth {
+		t.Errorf("unexpected limits: %+v", l)
+	}
+
+	if l = newLimits(ParserConfig{MaxIncludeDepth: 4}); l.maxIncludeDepth != 4 {
+		t.Errorf("expected an include depth of 4, got %d", l.maxIncludeDepth)
+	}
+
+	var o strings.Builder
+	if w := (limits{}).writer(context.Background(), &o); w != &o {
+		t.Error("unlimited output to a context that is never done should not be wrapped")
+	}
+
+	ctx, cancel, err := (limits{timeout: time.Minute}).context(context.Background())
+	defer cancel()
+	if _, ok := ctx.Deadline(); !ok || err != nil {
+		t.Errorf("the timeout should be applied to the context (%v)", err)
+	}
+
+	canceled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	_, cancel, err = (limits{timeout: time.Minute}).context(canceled)
+	cancel()
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+// countCalls counts the inserted calls to a function anywhere in a list.
+func countCalls(l *parse.ListNode, f string) (count int) {
+	if l == nil {
+		return
+	}
+
+	for _, n := range l.Nodes {
+		if calls(n, f) {
+			count++
+		}
+
+		switch tn := n.(type) {
+		case *parse.IfNode:
+			count += countCalls(tn.List, f) + countCalls(tn.ElseList, f)
+
+		case *parse.WithNode:
+			count += countCalls(tn.List, f) + countCalls(tn.ElseList, f)
+
+		case *parse.RangeNode:
+			count += countCalls(tn.List, f) + countCalls(tn.ElseList, f)
+		}
+	}
+
+	return
+}
+
+func TestInstrument(t *testing.T) {
+	testCases := []struct {
+		name     string
+		template string
+		depth    bool
+		checks   int
+		enters   int
+	}{
+		{name: "Empty", template: "", depth: true},
+		{name: "Text", template: "text"},
+		{name: "Range", template: "{{range .}}{{.}}{{end}}", checks: 1},
+		{name: "EmptyRange", template: "{{range .}}{{end}}", checks: 1},
+		{name: "NestedRanges", template: "{{if .}}{{range .}}{{with .}}{{range .}}x{{end}}{{end}}{{else}}{{range .}}{{end}}{{end}}{{end}}", checks: 3},
+		{name: "Depth", template: "{{range .}}{{.}}{{end}}", depth: true, checks: 1, enters: 1},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			trees, err := parse.Parse("t", testCase.template, "", "")
+			if err != nil {
+				t.Fatalf("unable to parse template: %s", err)
+			}
+
+			tree := trees["t"]
+
+			// instrumenting is idempotent
+			for i := 0; i < 2; i++ {
+				instrument("t", tree, testCase.depth)
+				if checks := countCalls(tree.Root, checkFunc); checks != testCase.checks {
+					t.Errorf("expected %d checks, got %d", testCase.checks, checks)
+				}
+
+				if enters, exits := countCalls(tree.Root, enterFunc), countCalls(tree.Root, exitFunc); enters != testCase.enters || exits != testCase.enters {
+					t.Errorf("expected %d enters and exits, got %d and %d", testCase.enters, enters, exits)
+				}
+			}
+
+			// printing the instrumented tree, as error messages do, must not panic
+			if s := tree.Root.String(); testCase.checks > 0 && !strings.Contains(s, checkFunc) {
+				t.Errorf("expected the printed tree to show the checks: %s", s)
+			}
+		})
+	}
+
+	instrument("nil", nil, true)
+	instrument("nil", &parse.Tree{}, true)
+}
+
+func TestLimits(t *testing.T) {
+	partials := fstest.MapFS{
+		"loop.mustache": {Data: []byte("{{>loop}}")},
+	}
+
+	long := make([]interface{}, 1000)
+	for i := range long {
+		long[i] = i
+	}
+
+	testCases := []struct {
+		name     string
+		config   ParserConfig
+		template string
+		opts     []ExecuteOption
+		err      error
+	}{
+		{
+			name:     "TextTimeout",
+			config:   ParserConfig{Timeout: time.Nanosecond},
+			template: `{{range .list}}{{.}}{{end}}`,
+			err:      context.DeadlineExceeded,
+		},
+		{
+			name:     "HTMLTimeout",
+			config:   ParserConfig{HTML: true, Timeout: time.Nanosecond},
+			template: `{{range .list}}{{.}}{{end}}`,
+			err:      context.DeadlineExceeded,
+		},
+		{
+			name:     "JSONTimeout",
+			config:   ParserConfig{JSON: true, Timeout: time.Nanosecond},
+			template: `[{{range .list}}{{.}},{{end}}0]`,
+			err:      context.DeadlineExceeded,
+		},
+		{
+			name:     "MustacheTimeout",
+			config:   ParserConfig{Engine: EngineMustache, Timeout: time.Nanosecond},
+			template: `{{#list}}{{.}}{{/list}}`,
+			err:      context.DeadlineExceeded,
+		},
+		{
+			name:     "StructuredTimeout",
+			config:   ParserConfig{Engine: EngineStructured, Timeout: time.Nanosecond},
+			template: `{"$each": "${.list}", "do": "${.}"}`,
+			err:      context.DeadlineExceeded,
+		},
+		{
+			name:     "TextMaxOutput",
+			config:   ParserConfig{MaxOutput: 100},
+			template: `{{range .list}}{{.}}{{end}}`,
+			err:      &OutputLimitError{MaxOutput: 100},
+		},
+		{
+			name:     "HTMLMaxOutput",
+			config:   ParserConfig{HTML: true, MaxOutput: 100},
+			template: `{{range .list}}{{.}}{{end}}`,
+			err:      &OutputLimitError{MaxOutput: 100},
+		},
+		{
+			name:     "IncludeMaxOutput",
+			config:   ParserConfig{MaxOutput: 100},
+			template: `{{define "all"}}{{range .list}}{{.}}{{end}}{{end}}{{include "all" . | len}}`,
+			err:      &OutputLimitError{MaxOutput: 100},
+		},
+		{
+			name:     "MustacheMaxOutput",
+			config:   ParserConfig{Engine: EngineMustache, MaxOutput: 100},
+			template: `{{#list}}{{.}}{{/list}}`,
+			err:      &OutputLimitError{MaxOutput: 100},
+		},
+		{
+			name:     "StructuredMaxOutput",
+			config:   ParserConfig{Engine: EngineStructured, MaxOutput: 100},
+			template: `{"$each": "${.list}", "do": "${.}"}`,
+			err:      &OutputLimitError{MaxOutput: 100},
+		},
+		{
+			name:     "TextMaxDepth",
+			config:   ParserConfig{MaxDepth: 5},
+			template: `{{define "r"}}{{template "r" .}}{{end}}{{template "r" .}}`,
+			err:      &TemplateDepthError{Name: "r", MaxDepth: 5},
+		},
+		{
+			name:     "HTMLMaxDepth",
+			config:   ParserConfig{HTML: true, MaxDepth: 5},
+			template: `{{define "r"}}x{{template "r" .}}{{end}}{{template "r" .}}`,
+			err:      &TemplateDepthError{Name: "r", MaxDepth: 5},
+		},
+		{
+			name:     "TextMaxDepthWithOverrides",
+			config:   ParserConfig{MaxDepth: 5},
+			template: `{{define "r"}}{{template "r" .}}{{end}}{{template "r" .}}`,
+			opts:     []ExecuteOption{WithFunc("upper", strings.ToUpper)},
+			err:      &TemplateDepthError{Name: "r", MaxDepth: 5},
+		},
+		{
+			name:     "HTMLTimeoutWithOverrides",
+			config:   ParserConfig{HTML: true, Timeout: time.Nanosecond},
+			template: `{{range .list}}{{.}}{{end}}`,
+			opts:     []ExecuteOption{WithFunc("upper", strings.ToUpper)},
+			err:      context.DeadlineExceeded,
+		},
+		{
+			name:     "MustacheMaxDepth",
+			config:   ParserConfig{Engine: EngineMustache, MaxDepth: 5, FS: partials, Includes: []string{"*.mustache"}},
+			template: `{{>loop}}`,
+			err:      &TemplateDepthError{Name: "loop", MaxDepth: 5},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			p, err := NewParser(testCase.config)
+			if err != nil {
+				t.Fatalf("unable to create parser: %s", err)
+			}
+
+			tmpl, err := p.Parse("t", testCase.template)
+			if err != nil {
+				t.Fatalf("unable to parse template: %s", err)
+			}
+
+			var o strings.Builder
+			err = ExecuteWith(context.Background(), tmpl, &o, Model{"list": long}, testCase.opts...)
+			var (
+				ole *OutputLimitError
+				tde *TemplateDepthError
+			)
+
+			switch {
+			case errors.As(testCase.err, &ole):
+				if !errors.As(err, &ole) || *ole != *testCase.err.(*OutputLimitError) {
+					t.Errorf("expected %v, got %v", testCase.err, err)
+				}
+
+				if int64(o.Len()) > ole.MaxOutput {
+					t.Errorf("wrote %d bytes, more than the maximum", o.Len())
+				}
+
+			case errors.As(testCase.err, &tde):
+				// the error is reported as is, rather than as a failed call to an internal function
+				if tde, ok := err.(*TemplateDepthError); !ok || *tde != *testCase.err.(*TemplateDepthError) {
+					t.Errorf("expected %v, got %v", testCase.err, err)
+				}
+
+			case !errors.Is(err, testCase.err) || strings.Contains(err.Error(), "_thoth"):
+				t.Errorf("expected %v, got %v", testCase.err, err)
+			}
+
+			// the limits apply to each execution, so a small execution still succeeds
+			o.Reset()
+			if testCase.config.MaxDepth == 0 && testCase.config.Timeout == 0 {
+				if err := tmpl.Execute(&o, Model{"list": long[:2]}); err != nil {
+					t.Errorf("a small execution should succeed: %s", err)
+				}
+			}
+		})
+	}
+}
+
+func TestMustacheCanceledWhileRendering(t *testing.T) {
+	p, err := NewParser(ParserConfig{Engine: EngineMustache})
+	if err != nil {
+		t.Fatalf("unable to create parser: %s", err)
+	}
+
+	tmpl, err := p.Parse("t", `{{#list}}{{.}}{{/list}}`)
+	if err != nil {
+		t.Fatalf("unable to parse template: %s", err)
+	}
+
+	list := make([]interface{}, 100000)
+	for i := range list {
+		list[i] = "x"
+	}
+
+	// a context canceled while rendering stops the execution
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := ExecuteWith(ctx, tmpl, &cancelingWriter{cancel: cancel}, Model{"list": list}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+// cancelingWriter cancels a context on its first write.
+type cancelingWriter struct {
+	cancel context.CancelFunc
+}
+
+func (cw *cancelingWriter) Write(p []byte) (int, error) {
+	cw.cancel()
+	return len(p), nil
+}
+
+func TestInstrumentedEscapeErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		config   ParserConfig
+		template string
+		err      string
+	}{
+		{name: "HTML", config: ParserConfig{HTML: true}, template: `{{range .}}<a {{end}}`, err: "on range loop re-entry"},
+		{name: "HTMLLimited", config: ParserConfig{HTML: true, MaxDepth: 3, Timeout: time.Minute}, template: `{{range .}}<a {{end}}`, err: "on range loop re-entry"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			p, err := NewParser(testCase.config)
+			if err != nil {
+				t.Fatalf("unable to create parser: %s", err)
+			}
+
+			// the escaper prints the instrumented range when it reports the error
+			var o strings.Builder
+			tmpl, err := p.Parse("t", testCase.template)
+			if err == nil {
+				err = tmpl.Execute(&o, []int{1})
+			}
+
+			if err == nil || !strings.Contains(err.Error(), testCase.err) {
+				t.Errorf("expected an error containing %q, got %v", testCase.err, err)
+			}
+		})
+	}
+}
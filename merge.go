@@ -0,0 +1,440 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// PathSeparator separates the segments of a path key, such as "device.model",
+	// when key/value pairs are applied deeply.
+	PathSeparator = "."
+
+	// DeleteTag is the YAML tag that marks a value as Delete, e.g. "legacyField: !delete".
+	DeleteTag = "!delete"
+)
+
+// ListStrategy determines how a deep merge combines two lists.
+type ListStrategy string
+
+const (
+	// ListReplace uses the list with precedence as is.  This is the default.
+	ListReplace ListStrategy = "replace"
+
+	// ListAppend concatenates the lists, with the list with precedence last.
+	ListAppend ListStrategy = "append"
+
+	// ListMergeIndex merges the elements at each index, so that maps within
+	// lists are themselves merged deeply.  An overriding Delete marker removes
+	// the element at its index.
+	ListMergeIndex ListStrategy = "mergeIndex"
+)
+
+// InvalidListStrategyError indicates that an unrecognized ListStrategy was configured.
+type InvalidListStrategyError struct {
+	Value ListStrategy
+}
+
+// Error satisfies the error interface.
+func (ilse *InvalidListStrategyError) Error() string {
+	return fmt.Sprintf("%s is not a valid list strategy", ilse.Value)
+}
+
+// MergeConfig controls how defaults and overrides are applied to template data.
+type MergeConfig struct {
+	// Deep indicates that keys are paths, such as "device.model", and that values
+	// are merged recursively into nested maps.  If false, which is the default,
+	// only top-level keys are applied and nested maps are replaced wholesale.
+	Deep bool `json:"deep" yaml:"deep"`
+
+	// Lists is the strategy for combining lists during a deep merge.  If unset,
+	// ListReplace is used.
+	Lists ListStrategy `json:"lists" yaml:"lists"`
+}
+
+// Validate checks this configuration for unrecognized values.
+func (mc MergeConfig) Validate() error {
+	switch mc.Lists {
+	case "", ListReplace, ListAppend, ListMergeIndex:
+		return nil
+
+	default:
+		return &InvalidListStrategyError{Value: mc.Lists}
+	}
+}
+
+// deletion is the type of the Delete marker.
+type deletion struct{}
+
+// Delete is a marker value which, when used as an override, removes the
+// corresponding key from the data.
+var Delete interface{} = deletion{}
+
+func isDelete(v interface{}) bool {
+	_, ok := v.(deletion)
+	return ok
+}
+
+// asMap returns the given value as a map, if it is one.
+func asMap(v interface{}) (map[string]interface{}, bool) {
+	switch m := v.(type) {
+	case Model:
+		return m, true
+
+	case map[string]interface{}:
+		return m, true
+
+	default:
+		return nil, false
+	}
+}
+
+// copyMap returns a shallow copy of a map.  The copy has room for extra keys.
+func copyMap(m map[string]interface{}, extra int) map[string]interface{} {
+	c := make(map[string]interface{}, len(m)+extra)
+	for k, v := range m {
+		c[k] = v
+	}
+
+	return c
+}
+
+// clean returns a value with any Delete markers removed from nested maps.
+// The value itself is assumed not to be Delete.
+func clean(v interface{}) interface{} {
+	switch tv := v.(type) {
+	case Model, map[string]interface{}:
+		m, _ := asMap(tv)
+		c := make(map[string]interface{}, len(m))
+		for k, e := range m {
+			if !isDelete(e) {
+				c[k] = clean(e)
+			}
+		}
+
+		return c
+
+	case []interface{}:
+		c := make([]interface{}, 0, len(tv))
+		for _, e := range tv {
+			if !isDelete(e) {
+				c = append(c, clean(e))
+			}
+		}
+
+		return c
+
+	default:
+		return v
+	}
+}
+
+// merger performs deep merges for a single list strategy.  When override is true,
+// values from the source take precedence.  Otherwise, the source supplies defaults
+// and values already in the destination take precedence.
+type merger struct {
+	lists    ListStrategy
+	override bool
+}
+
+// mergeLists combines two lists according to the list strategy.
+func (mr merger) mergeLists(dst, src []interface{}) []interface{} {
+	switch mr.lists {
+	case ListAppend:
+		first, second := dst, clean(src).([]interface{})
+		if !mr.override {
+			first, second = second, first
+		}
+
+		merged := make([]interface{}, 0, len(first)+len(second))
+		merged = append(merged, first...)
+		return append(merged, second...)
+
+	case ListMergeIndex:
+		// elements removed by a Delete marker shift the remaining elements down
+		merged := make([]interface{}, 0, max(len(dst), len(src)))
+		for i := 0; i < cap(merged); i++ {
+			switch {
+			case i < len(dst) && i < len(src):
+				if v, remove := mr.merge(dst[i], src[i]); !remove {
+					merged = append(merged, v)
+				}
+
+			case i < len(dst):
+				merged = append(merged, dst[i])
+
+			case !isDelete(src[i]):
+				merged = append(merged, clean(src[i]))
+			}
+		}
+
+		return merged
+
+	default:
+		if mr.override {
+			return clean(src).([]interface{})
+		}
+
+		return dst
+	}
+}
+
+// merge combines an existing value with a value from the source.  The returned
+// flag is true if the key holding the value should be removed.
+func (mr merger) merge(dst, src interface{}) (interface{}, bool) {
+	if isDelete(src) {
+		// deletion only makes sense for overrides
+		return dst, mr.override
+	}
+
+	if dstMap, ok := asMap(dst); ok {
+		if srcMap, ok := asMap(src); ok {
+			merged := copyMap(dstMap, len(srcMap))
+			for k, v := range srcMap {
+				mr.mergeKey(merged, k, v)
+			}
+
+			return merged, false
+		}
+	}
+
+	if dstList, ok := dst.([]interface{}); ok {
+		if srcList, ok := src.([]interface{}); ok {
+			return mr.mergeLists(dstList, srcList), false
+		}
+	}
+
+	if mr.override {
+		return clean(src), false
+	}
+
+	return dst, false
+}
+
+// mergeKey merges a single source value into a map, which must be owned by the caller.
+func (mr merger) mergeKey(m map[string]interface{}, key string, src interface{}) {
+	existing, present := m[key]
+	switch {
+	case present:
+		merged, remove := mr.merge(existing, src)
+		if remove {
+			delete(m, key)
+		} else {
+			m[key] = merged
+		}
+
+	case !isDelete(src):
+		m[key] = clean(src)
+	}
+}
+
+// mergePath merges a value at a path of keys.  Each nested map along the path is
+// copied before it is modified, so maps shared with the caller's data are never
+// changed.  Missing or non-map intermediate values are replaced with new maps,
+// unless this merger supplies defaults, in which case they are left alone.
+func (mr merger) mergePath(m map[string]interface{}, path []string, src interface{}) {
+	for _, segment := range path[:len(path)-1] {
+		next, ok := asMap(m[segment])
+		switch {
+		case ok:
+			next = copyMap(next, 1)
+
+		case isDelete(src):
+			// nothing to delete beneath a missing map
+			return
+
+		case !mr.override && m[segment] != nil:
+			// a default never replaces an existing value
+			return
+
+		default:
+			next = make(map[string]interface{})
+		}
+
+		m[segment] = next
+		m = next
+	}
+
+	mr.mergeKey(m, path[len(path)-1], src)
+}
+
+// ApplyDefaultsDeep is like ApplyDefaults, but treats each key as a path and merges
+// recursively.  For example, the key "device.model" supplies a default for the model
+// key within the device map.  Values already in the model always take precedence,
+// including within nested maps.  Lists are combined using the given strategy.
+//
+// Nested maps within the model are copied before being modified, so maps shared
+// with other data are never changed.
+func (kvs KeyValues) ApplyDefaultsDeep(m Model, lists ListStrategy) {
+	mr := merger{lists: lists}
+	for _, e := range kvs.entries {
+		mr.mergePath(m, strings.Split(e.key, PathSeparator), e.value)
+	}
+}
+
+// ApplyOverridesDeep is like ApplyOverrides, but treats each key as a path and merges
+// recursively.  For example, an override of "device.model" replaces only the model key
+// within the device map, leaving the rest of the device map intact.  An override whose
+// value is Delete removes the key.  Lists are combined using the given strategy.
+//
+// Nested maps within the model are copied before being modified, so maps shared
+// with other data are never changed.
+func (kvs KeyValues) ApplyOverridesDeep(m Model, lists ListStrategy) {
+	mr := merger{lists: lists, override: true}
+	for _, e := range kvs.entries {
+		mr.mergePath(m, strings.Split(e.key, PathSeparator), e.value)
+	}
+}
+
+// maxAliasExpansions is the maximum number of aliases expanded within a single
+// YAML document.  Aliases that refer to other aliases expand exponentially, so
+// without a limit a small document can exhaust memory.
+const maxAliasExpansions = 10000
+
+// aliasExpander expands YAML aliases, rejecting aliases that refer to a node
+// containing themselves and documents that expand too many aliases.
+type aliasExpander struct {
+	active map[*yaml.Node]bool
+	count  int
+}
+
+// enter begins expanding an alias.  Each successful call must be followed by a
+// call to exit once the node that the alias refers to has been expanded.
+func (ae *aliasExpander) enter(alias *yaml.Node) error {
+	if ae.active[alias.Alias] {
+		return fmt.Errorf("alias *%s refers to a node that contains it", alias.Value)
+	}
+
+	ae.count++
+	if ae.count > maxAliasExpansions {
+		return fmt.Errorf("expanded more than %d aliases", maxAliasExpansions)
+	}
+
+	if ae.active == nil {
+		ae.active = make(map[*yaml.Node]bool)
+	}
+
+	ae.active[alias.Alias] = true
+	return nil
+}
+
+// exit finishes expanding an alias.
+func (ae *aliasExpander) exit(alias *yaml.Node) {
+	delete(ae.active, alias.Alias)
+}
+
+// nodeDecoder converts YAML nodes into model values, translating values
+// tagged with DeleteTag into Delete.
+type nodeDecoder struct {
+	aliases aliasExpander
+}
+
+// decode converts a YAML node into a model value.
+func (nd *nodeDecoder) decode(node *yaml.Node) (v interface{}, err error) {
+	switch {
+	case node.Tag == DeleteTag:
+		v = Delete
+
+	case node.Kind == yaml.DocumentNode && len(node.Content) > 0:
+		v, err = nd.decode(node.Content[0])
+
+	case node.Kind == yaml.AliasNode:
+		if err = nd.aliases.enter(node); err != nil {
+			return nil, fmt.Errorf("line %d: %w", node.Line, err)
+		}
+
+		v, err = nd.decode(node.Alias)
+		nd.aliases.exit(node)
+
+	case node.Kind == yaml.MappingNode:
+		m := make(map[string]interface{}, len(node.Content)/2)
+		err = nd.mapping(node, m)
+		v = m
+
+	case node.Kind == yaml.SequenceNode:
+		l := make([]interface{}, 0, len(node.Content))
+		for i := 0; err == nil && i < len(node.Content); i++ {
+			var value interface{}
+			value, err = nd.decode(node.Content[i])
+			l = append(l, value)
+		}
+
+		v = l
+
+	default:
+		err = node.Decode(&v)
+	}
+
+	return
+}
+
+// mapping decodes the key/value pairs of a YAML mapping node into m.  Merge keys,
+// e.g. "<<: *base", supply values for keys that the mapping does not define itself.
+func (nd *nodeDecoder) mapping(node *yaml.Node, m map[string]interface{}) (err error) {
+	var merges []*yaml.Node
+	for i := 0; err == nil && i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.Tag == "!!merge" {
+			merges = append(merges, value)
+			continue
+		}
+
+		m[key.Value], err = nd.decode(value)
+	}
+
+	for i := 0; err == nil && i < len(merges); i++ {
+		var merged interface{}
+		merged, err = nd.decode(merges[i])
+		if err != nil {
+			break
+		}
+
+		sources, isList := merged.([]interface{})
+		if !isList {
+			sources = []interface{}{merged}
+		}
+
+		for _, source := range sources {
+			sm, ok := source.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("line %d: merge key values must be maps", merges[i].Line)
+			}
+
+			for k, v := range sm {
+				if _, exists := m[k]; !exists {
+					m[k] = v
+				}
+			}
+		}
+	}
+
+	return
+}
+
+// UnmarshalYAML allows a Model to contain Delete markers, written using the
+// DeleteTag.  Nested maps are always decoded as map[string]interface{}.
+func (m *Model) UnmarshalYAML(node *yaml.Node) error {
+	var nd nodeDecoder
+	v, err := nd.decode(node)
+	if err != nil {
+		return err
+	}
+
+	switch tv := v.(type) {
+	case nil:
+		*m = nil
+		return nil
+
+	case map[string]interface{}:
+		*m = tv
+		return nil
+
+	default:
+		return fmt.Errorf("a model must be a map, not %T", v)
+	}
+}
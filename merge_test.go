@@ -0,0 +1,248 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestMergeConfigValidate(t *testing.T) {
+	for _, valid := range []ListStrategy{"", ListReplace, ListAppend, ListMergeIndex} {
+		if err := (MergeConfig{Lists: valid}).Validate(); err != nil {
+			t.Errorf("%q: unexpected error: %s", valid, err)
+		}
+	}
+
+	err := MergeConfig{Lists: "bogus"}.Validate()
+	if ilse, ok := err.(*InvalidListStrategyError); !ok || ilse.Value != "bogus" {
+		t.Errorf("expected an InvalidListStrategyError, got %v", err)
+	}
+}
+
+func TestApplyDeep(t *testing.T) {
+	type m = map[string]interface{}
+	type l = []interface{}
+
+	testCases := []struct {
+		name     string
+		kvs      Model
+		lists    ListStrategy
+		model    Model
+		defaults Model
+		override Model
+	}{
+		{
+			name:     "Paths",
+			kvs:      Model{"a.b": 2, "a.c.d": 3},
+			model:    Model{"a": m{"b": 1, "x": 1}},
+			defaults: Model{"a": m{"b": 1, "c": m{"d": 3}, "x": 1}},
+			override: Model{"a": m{"b": 2, "c": m{"d": 3}, "x": 1}},
+		},
+		{
+			name:     "NestedMaps",
+			kvs:      Model{"a": m{"b": 2, "c": 3}},
+			model:    Model{"a": m{"b": 1, "x": 1}},
+			defaults: Model{"a": m{"b": 1, "c": 3, "x": 1}},
+			override: Model{"a": m{"b": 2, "c": 3, "x": 1}},
+		},
+		{
+			name:     "ScalarIntermediate",
+			kvs:      Model{"a.b": 2},
+			model:    Model{"a": "scalar"},
+			defaults: Model{"a": "scalar"},
+			override: Model{"a": m{"b": 2}},
+		},
+		{
+			name:     "ListReplace",
+			kvs:      Model{"a": l{3}},
+			lists:    ListReplace,
+			model:    Model{"a": l{1, 2}},
+			defaults: Model{"a": l{1, 2}},
+			override: Model{"a": l{3}},
+		},
+		{
+			name:     "ListAppend",
+			kvs:      Model{"a": l{3}},
+			lists:    ListAppend,
+			model:    Model{"a": l{1, 2}},
+			defaults: Model{"a": l{3, 1, 2}},
+			override: Model{"a": l{1, 2, 3}},
+		},
+		{
+			name:     "ListMergeIndex",
+			kvs:      Model{"a": l{m{"y": 2}, m{"z": 3}, 4}},
+			lists:    ListMergeIndex,
+			model:    Model{"a": l{m{"x": 1, "y": 1}}},
+			defaults: Model{"a": l{m{"x": 1, "y": 1}, m{"z": 3}, 4}},
+			override: Model{"a": l{m{"x": 1, "y": 2}, m{"z": 3}, 4}},
+		},
+		{
+			name:     "ListMergeIndexDelete",
+			kvs:      Model{"a": l{Delete, m{"y": 2}, 3, Delete}},
+			lists:    ListMergeIndex,
+			model:    Model{"a": l{1, m{"x": 1}}},
+			defaults: Model{"a": l{1, m{"x": 1, "y": 2}, 3}},
+			override: Model{"a": l{m{"x": 1, "y": 2}, 3}},
+		},
+		{
+			name:     "Delete",
+			kvs:      Model{"a.b": Delete, "c": Delete},
+			model:    Model{"a": m{"b": 1, "x": 1}, "c": 1},
+			defaults: Model{"a": m{"b": 1, "x": 1}, "c": 1},
+			override: Model{"a": m{"x": 1}},
+		},
+		{
+			name:     "DeleteMissing",
+			kvs:      Model{"a.b": Delete, "c": Delete},
+			model:    Model{"x": 1},
+			defaults: Model{"x": 1},
+			override: Model{"x": 1},
+		},
+		{
+			name:     "DeleteNested",
+			kvs:      Model{"a": m{"b": Delete, "c": m{"d": Delete, "e": 1}}},
+			model:    Model{"a": m{"b": 1, "x": 1}},
+			defaults: Model{"a": m{"b": 1, "c": m{"e": 1}, "x": 1}},
+			override: Model{"a": m{"c": m{"e": 1}, "x": 1}},
+		},
+		{
+			name:     "DeleteInList",
+			kvs:      Model{"a": l{1, Delete, m{"b": Delete}}},
+			model:    Model{},
+			defaults: Model{"a": l{1, m{}}},
+			override: Model{"a": l{1, m{}}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			kvs := KeyValues{}.Append(testCase.kvs)
+
+			// nested maps and lists in the original model must never change
+			original, _ := yaml.Marshal(testCase.model)
+
+			defaults := Model(copyMap(testCase.model, 0))
+			kvs.ApplyDefaultsDeep(defaults, testCase.lists)
+			if !reflect.DeepEqual(testCase.defaults, defaults) {
+				t.Errorf("defaults: expected %v, got %v", testCase.defaults, defaults)
+			}
+
+			overrides := Model(copyMap(testCase.model, 0))
+			kvs.ApplyOverridesDeep(overrides, testCase.lists)
+			if !reflect.DeepEqual(testCase.override, overrides) {
+				t.Errorf("overrides: expected %v, got %v", testCase.override, overrides)
+			}
+
+			if after, _ := yaml.Marshal(testCase.model); string(after) != string(original) {
+				t.Errorf("the original model was modified:\n%s", after)
+			}
+		})
+	}
+}
+
+func TestModelUnmarshalYAML(t *testing.T) {
+	type m = map[string]interface{}
+
+	testCases := []struct {
+		name     string
+		yaml     string
+		expected Model
+		err      string
+	}{
+		{
+			name:     "Empty",
+			yaml:     "",
+			expected: nil,
+		},
+		{
+			name:     "Nested",
+			yaml:     "a:\n  b: 1\n  c: [x, {d: true}]\n",
+			expected: Model{"a": m{"b": 1, "c": []interface{}{"x", m{"d": true}}}},
+		},
+		{
+			name:     "Delete",
+			yaml:     "a: !delete\nb:\n  c: !delete\n",
+			expected: Model{"a": Delete, "b": m{"c": Delete}},
+		},
+		{
+			name:     "Alias",
+			yaml:     "base: &base {x: 1}\ncopy: *base\n",
+			expected: Model{"base": m{"x": 1}, "copy": m{"x": 1}},
+		},
+		{
+			name:     "MergeKey",
+			yaml:     "base: &base {x: 1, y: 1}\nderived:\n  <<: *base\n  y: 2\n",
+			expected: Model{"base": m{"x": 1, "y": 1}, "derived": m{"x": 1, "y": 2}},
+		},
+		{
+			name:     "MergeKeyList",
+			yaml:     "a: &a {x: 1}\nb: &b {y: 1}\nderived:\n  <<: [*a, *b]\n",
+			expected: Model{"a": m{"x": 1}, "b": m{"y": 1}, "derived": m{"x": 1, "y": 1}},
+		},
+		{
+			name: "MergeKeyScalar",
+			yaml: "a: &a 1\nderived:\n  <<: *a\n",
+			err:  "merge key values must be maps",
+		},
+		{
+			name: "NotAMap",
+			yaml: "[1, 2]",
+			err:  "a model must be a map",
+		},
+		{
+			name: "AliasCycle",
+			yaml: "a: &a\n  b: *a\n",
+			err:  "line 2: alias *a refers to a node that contains it",
+		},
+		{
+			name: "AliasCycleInMergeKey",
+			yaml: "a: &a\n  <<: *a\n",
+			err:  "refers to a node that contains it",
+		},
+		{
+			name: "AliasExpansion",
+			yaml: billionLaughs(),
+			err:  "expanded more than",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var actual Model
+			err := yaml.Unmarshal([]byte(testCase.yaml), &actual)
+			if len(testCase.err) > 0 {
+				if err == nil || !strings.Contains(err.Error(), testCase.err) {
+					t.Errorf("expected an error containing %q, got %v", testCase.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !reflect.DeepEqual(testCase.expected, actual) {
+				t.Errorf("expected %v, got %v", testCase.expected, actual)
+			}
+		})
+	}
+}
+
+// billionLaughs returns a YAML document whose aliases expand exponentially.
+func billionLaughs() string {
+	var o strings.Builder
+	o.WriteString("a0: &a0 [x, x, x, x, x, x, x, x, x, x]\n")
+	for i := 1; i < 10; i++ {
+		previous := fmt.Sprintf("*a%d", i-1)
+		fmt.Fprintf(&o, "a%d: &a%d [%s%s]\n", i, i, strings.Repeat(previous+", ", 9), previous)
+	}
+
+	return o.String()
+}
@@ -3,6 +3,11 @@
 
 package thoth
 
+import (
+	"context"
+	"io"
+)
+
 // Model is a template model.
 type Model map[string]interface{}
 
@@ -73,19 +78,129 @@ func (kvs KeyValues) Extend(more KeyValues) KeyValues {
 // ApplyDefaults uses the key/value pairs in this list as defaults
 // for the given model.  Any key present in this list but not present
 // in the model will be set to the value in this list.  Existing keys
-// in the model that are not in this list are left untouched.
+// in the model that are not in this list are left untouched.  A default
+// whose value is Delete is ignored.
 func (kvs KeyValues) ApplyDefaults(m Model) {
 	for _, e := range kvs.entries {
-		if _, present := m[e.key]; !present {
-			m[e.key] = e.value
+		if _, present := m[e.key]; !present && !isDelete(e.value) {
+			m[e.key] = clean(e.value)
 		}
 	}
 }
 
 // ApplyOverrides unconditionally sets each key in this list on the given model.
-// Existing keys in the model that are in this list are overridden.
+// Existing keys in the model that are in this list are overridden.  An override
+// whose value is Delete removes the key from the model.
 func (kvs KeyValues) ApplyOverrides(m Model) {
 	for _, e := range kvs.entries {
-		m[e.key] = e.value
+		if isDelete(e.value) {
+			delete(m, e.key)
+		} else {
+			m[e.key] = clean(e.value)
+		}
+	}
+}
+
+// Len returns the number of key/value pairs in this list, including duplicates.
+func (kvs KeyValues) Len() int {
+	return len(kvs.entries)
+}
+
+// ModelTemplate is a Template that enriches its data with common key/value
+// pairs prior to execution.
+type ModelTemplate interface {
+	Template
+
+	// Defaults returns the key/value pairs that are applied to the data when
+	// the data does not already have those keys.
+	Defaults() KeyValues
+
+	// Overrides returns the key/value pairs that unconditionally replace
+	// keys in the data.
+	Overrides() KeyValues
+}
+
+type modelTemplate struct {
+	Template
+	defaults  KeyValues
+	overrides KeyValues
+	merge     MergeConfig
+}
+
+func (mt modelTemplate) Defaults() KeyValues {
+	return mt.defaults
+}
+
+func (mt modelTemplate) Overrides() KeyValues {
+	return mt.overrides
+}
+
+// MediaType returns the media type of the decorated template, which allows
+// a ModelTemplate to preserve the MediaTyper behavior of what it wraps.
+func (mt modelTemplate) MediaType() string {
+	return MediaType(mt.Template)
+}
+
+// enrich applies the defaults and then the overrides to a copy of data.  The caller's
+// data, including any nested maps, is never modified.  Data that is neither a Model nor
+// a map[string]interface{} is returned unchanged.  Nil data is treated as an empty Model.
+func (mt modelTemplate) enrich(data interface{}) interface{} {
+	var source map[string]interface{}
+	switch d := data.(type) {
+	case nil:
+		// start with an empty model
+
+	case Model:
+		source = d
+
+	case map[string]interface{}:
+		source = d
+
+	default:
+		return data
+	}
+
+	m := make(Model, len(source)+mt.defaults.Len()+mt.overrides.Len())
+	for k, v := range source {
+		m[k] = v
+	}
+
+	if mt.merge.Deep {
+		lists := mt.merge.Lists
+		if len(lists) == 0 {
+			lists = ListReplace
+		}
+
+		mt.defaults.ApplyDefaultsDeep(m, lists)
+		mt.overrides.ApplyOverridesDeep(m, lists)
+	} else {
+		mt.defaults.ApplyDefaults(m)
+		mt.overrides.ApplyOverrides(m)
+	}
+
+	return m
+}
+
+// Execute executes the decorated template with an enriched copy of data.
+func (mt modelTemplate) Execute(output io.Writer, data interface{}) error {
+	return mt.Template.Execute(output, mt.enrich(data))
+}
+
+// ExecuteWith executes the decorated template with an enriched copy of data
+// and the given options.
+func (mt modelTemplate) ExecuteWith(ctx context.Context, output io.Writer, data interface{}, opts ...ExecuteOption) error {
+	return ExecuteWith(ctx, mt.Template, output, mt.enrich(data), opts...)
+}
+
+// EnrichTemplate decorates a Template so that the given defaults and overrides
+// are applied to its data prior to each execution.  The MergeConfig determines
+// whether they are applied to top-level keys only or merged deeply.  The returned
+// ModelTemplate also implements MediaTyper, reporting the media type of t.
+func EnrichTemplate(t Template, defaults, overrides KeyValues, mc MergeConfig) ModelTemplate {
+	return modelTemplate{
+		Template:  t,
+		defaults:  defaults,
+		overrides: overrides,
+		merge:     mc,
 	}
 }
@@ -0,0 +1,188 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestKeyValuesAppendExtend(t *testing.T) {
+	var empty KeyValues
+	if empty.Append(nil).Len() != 0 || empty.Append(Model{}).Len() != 0 {
+		t.Error("appending nothing should not add entries")
+	}
+
+	first := empty.Append(Model{"a": 1})
+	second := first.Append(Model{"a": 2, "b": 3})
+	if first.Len() != 1 || second.Len() != 3 {
+		t.Errorf("unexpected lengths %d and %d", first.Len(), second.Len())
+	}
+
+	testCases := []struct {
+		name     string
+		kvs      KeyValues
+		expected int
+	}{
+		{name: "EmptyExtendEmpty", kvs: empty.Extend(empty), expected: 0},
+		{name: "EmptyExtend", kvs: empty.Extend(second), expected: 3},
+		{name: "ExtendEmpty", kvs: second.Extend(empty), expected: 3},
+		{name: "Extend", kvs: first.Extend(second), expected: 4},
+	}
+
+	for _, testCase := range testCases {
+		if testCase.kvs.Len() != testCase.expected {
+			t.Errorf("%s: expected %d entries, got %d", testCase.name, testCase.expected, testCase.kvs.Len())
+		}
+	}
+
+	if first.Len() != 1 {
+		t.Error("Append and Extend should not modify their receiver")
+	}
+
+	// the last duplicate wins
+	m := Model{}
+	first.Extend(empty.Append(Model{"a": 2})).ApplyOverrides(m)
+	if m["a"] != 2 {
+		t.Errorf("expected the last value of a duplicate key, got %v", m["a"])
+	}
+}
+
+func TestKeyValuesApply(t *testing.T) {
+	testCases := []struct {
+		name     string
+		kvs      Model
+		model    Model
+		defaults Model
+		override Model
+	}{
+		{
+			name:     "Empty",
+			kvs:      Model{},
+			model:    Model{"a": 1},
+			defaults: Model{"a": 1},
+			override: Model{"a": 1},
+		},
+		{
+			name:     "Disjoint",
+			kvs:      Model{"b": 2},
+			model:    Model{"a": 1},
+			defaults: Model{"a": 1, "b": 2},
+			override: Model{"a": 1, "b": 2},
+		},
+		{
+			name:     "Overlapping",
+			kvs:      Model{"a": 2},
+			model:    Model{"a": 1},
+			defaults: Model{"a": 1},
+			override: Model{"a": 2},
+		},
+		{
+			name:     "Delete",
+			kvs:      Model{"a": Delete, "b": Delete},
+			model:    Model{"a": 1},
+			defaults: Model{"a": 1},
+			override: Model{},
+		},
+		{
+			name:     "NestedDelete",
+			kvs:      Model{"a": map[string]interface{}{"x": Delete, "y": 2}},
+			model:    Model{},
+			defaults: Model{"a": map[string]interface{}{"y": 2}},
+			override: Model{"a": map[string]interface{}{"y": 2}},
+		},
+		{
+			name:     "Shallow",
+			kvs:      Model{"a": map[string]interface{}{"y": 2}},
+			model:    Model{"a": map[string]interface{}{"x": 1}},
+			defaults: Model{"a": map[string]interface{}{"x": 1}},
+			override: Model{"a": map[string]interface{}{"y": 2}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			kvs := KeyValues{}.Append(testCase.kvs)
+
+			defaults := copyMap(testCase.model, 0)
+			kvs.ApplyDefaults(defaults)
+			if !reflect.DeepEqual(testCase.defaults, Model(defaults)) {
+				t.Errorf("defaults: expected %v, got %v", testCase.defaults, defaults)
+			}
+
+			overrides := copyMap(testCase.model, 0)
+			kvs.ApplyOverrides(overrides)
+			if !reflect.DeepEqual(testCase.override, Model(overrides)) {
+				t.Errorf("overrides: expected %v, got %v", testCase.override, overrides)
+			}
+		})
+	}
+}
+
+func TestEnrichTemplate(t *testing.T) {
+	p, err := NewParser(ParserConfig{MediaType: "text/plain"})
+	if err != nil {
+		t.Fatalf("unable to create parser: %s", err)
+	}
+
+	base, err := p.Parse("t", `{{.a}} {{.b}}`)
+	if err != nil {
+		t.Fatalf("unable to parse template: %s", err)
+	}
+
+	mt := EnrichTemplate(
+		base,
+		KeyValues{}.Append(Model{"a": "default a", "b": "default b"}),
+		KeyValues{}.Append(Model{"b": "override b"}),
+		MergeConfig{},
+	)
+
+	if MediaType(mt) != "text/plain" {
+		t.Errorf("expected the decorated media type, got %s", MediaType(mt))
+	}
+
+	if mt.Defaults().Len() != 2 || mt.Overrides().Len() != 1 {
+		t.Error("unexpected defaults or overrides")
+	}
+
+	testCases := []struct {
+		name     string
+		data     interface{}
+		expected string
+	}{
+		{name: "Nil", data: nil, expected: "default a override b"},
+		{name: "Model", data: Model{"a": "a", "b": "b"}, expected: "a override b"},
+		{name: "Map", data: map[string]interface{}{"a": "a"}, expected: "a override b"},
+		{name: "Struct", data: struct{ A, B string }{}, expected: ""},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			before := Model{}
+			if m, ok := asMap(testCase.data); ok {
+				before = copyMap(m, 0)
+			}
+
+			var o bytes.Buffer
+			err := mt.Execute(&o, testCase.data)
+			if len(testCase.expected) == 0 {
+				// data that isn't a map is passed through unchanged
+				if err == nil {
+					t.Errorf("expected the template to fail with %T", testCase.data)
+				}
+
+				return
+			}
+
+			if err != nil || o.String() != testCase.expected {
+				t.Errorf("expected %q, got %q (%v)", testCase.expected, o.String(), err)
+			}
+
+			if m, ok := asMap(testCase.data); ok && !reflect.DeepEqual(Model(m), before) {
+				t.Errorf("the data was modified: %v", m)
+			}
+		})
+	}
+}
@@ -0,0 +1,600 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"reflect"
+	"strings"
+	ttemplate "text/template"
+)
+
+const (
+	// DefaultMustacheLeftDelim is the left delimiter for Mustache tags when none is configured.
+	DefaultMustacheLeftDelim = "{{"
+
+	// DefaultMustacheRightDelim is the right delimiter for Mustache tags when none is configured.
+	DefaultMustacheRightDelim = "}}"
+
+	// DefaultMaxPartialDepth is the maximum nesting of Mustache partials when
+	// ParserConfig.MaxDepth is not set.
+	DefaultMaxPartialDepth = 1000
+)
+
+// MissingVariableError indicates that a Mustache variable had no value
+// and the missing key option is error.
+type MissingVariableError struct {
+	Name string
+}
+
+// Error satisfies the error interface.
+func (mve *MissingVariableError) Error() string {
+	return fmt.Sprintf("no value for %s", mve.Name)
+}
+
+// mrenderer is the state of a single execution of a Mustache template.
+type mrenderer struct {
+	mt  *mustacheTemplate
+	ctx context.Context
+
+	// depth is the current nesting of partials
+	depth int
+}
+
+// mnode is a node in a parsed Mustache template.
+type mnode interface {
+	render(r *mrenderer, w io.Writer, stack []interface{}) error
+}
+
+type mtext string
+
+func (t mtext) render(_ *mrenderer, w io.Writer, _ []interface{}) error {
+	_, err := io.WriteString(w, string(t))
+	return err
+}
+
+// mvariable is an interpolation tag: {{name}}, {{{name}}}, or {{&name}}.
+type mvariable struct {
+	name   string
+	line   int
+	column int
+	raw    bool
+}
+
+func (v mvariable) render(r *mrenderer, w io.Writer, stack []interface{}) error {
+	mt := r.mt
+	value, found := mustacheLookup(stack, v.name)
+	if !found || value == nil {
+		if !found && mt.missingKeyError {
+			return &PositionError{
+				Name:   mt.name,
+				Line:   v.line,
+				Column: v.column,
+				Err:    &MissingVariableError{Name: v.name},
+			}
+		}
+
+		return nil
+	}
+
+	text, ok := value.(string)
+	if !ok {
+		text = fmt.Sprint(value)
+	}
+
+	if !v.raw {
+		var err error
+		text, err = mt.escape(text)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := io.WriteString(w, text)
+	return err
+}
+
+// msection is a section, {{#name}}...{{/name}}, or an inverted section, {{^name}}...{{/name}}.
+type msection struct {
+	name     string
+	inverted bool
+	nodes    []mnode
+}
+
+// render renders this section's nodes.  When the value is a list, the nodes are
+// rendered for each element, and the context is checked before each element.
+func (s *msection) render(r *mrenderer, w io.Writer, stack []interface{}) error {
+	value, found := mustacheLookup(stack, s.name)
+	truth := false
+	if found {
+		truth, _ = ttemplate.IsTrue(value)
+	}
+
+	if s.inverted {
+		if !truth {
+			return renderNodes(r, w, stack, s.nodes)
+		}
+
+		return nil
+	} else if !truth {
+		return nil
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		for i := 0; i < rv.Len(); i++ {
+			if err := r.ctx.Err(); err != nil {
+				return err
+			}
+
+			if err := renderNodes(r, w, append(stack, rv.Index(i).Interface()), s.nodes); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return renderNodes(r, w, append(stack, value), s.nodes)
+}
+
+// mpartial is a partial tag, {{>name}}.  Standalone partials indent each
+// line of the partial's output.
+type mpartial struct {
+	name   string
+	indent string
+}
+
+// render renders the partial, which may not be nested more deeply than the maximum
+// depth.  Partials may include themselves, so this is what stops a runaway template.
+func (p mpartial) render(r *mrenderer, w io.Writer, stack []interface{}) error {
+	partial, found := r.mt.partials[p.name]
+	if !found {
+		// per the specification, a missing partial renders as the empty string
+		return nil
+	}
+
+	maxDepth := r.mt.limits.maxDepth
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxPartialDepth
+	}
+
+	if r.depth >= maxDepth {
+		return &TemplateDepthError{Name: p.name, MaxDepth: maxDepth}
+	} else if err := r.ctx.Err(); err != nil {
+		return err
+	}
+
+	r.depth++
+	defer func() { r.depth-- }()
+	if len(p.indent) > 0 {
+		w = &indentWriter{w: w, indent: p.indent}
+	}
+
+	return renderNodes(r, w, stack, partial)
+}
+
+// indentWriter writes an indent before each line written through it.
+type indentWriter struct {
+	w      io.Writer
+	indent string
+
+	// inLine is true when the last byte written did not end a line
+	inLine bool
+}
+
+func (iw *indentWriter) Write(p []byte) (n int, err error) {
+	for len(p) > 0 && err == nil {
+		if !iw.inLine {
+			if _, err = io.WriteString(iw.w, iw.indent); err != nil {
+				break
+			}
+		}
+
+		line := p
+		if i := bytes.IndexByte(p, '\n'); i >= 0 {
+			line = p[:i+1]
+		}
+
+		var written int
+		written, err = iw.w.Write(line)
+		n += written
+		iw.inLine = line[len(line)-1] != '\n'
+		p = p[len(line):]
+	}
+
+	return
+}
+
+func renderNodes(r *mrenderer, w io.Writer, stack []interface{}, nodes []mnode) error {
+	// keep nested sections from sharing the stack's backing array
+	stack = stack[:len(stack):len(stack)]
+	for _, n := range nodes {
+		if err := n.render(r, w, stack); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mustacheLookup resolves a possibly dotted name against a context stack.  The first
+// part of the name is found in the nearest context that has it.  The remaining parts
+// must be found within that value.
+func mustacheLookup(stack []interface{}, name string) (interface{}, bool) {
+	if name == "." {
+		if len(stack) == 0 {
+			return nil, false
+		}
+
+		return stack[len(stack)-1], true
+	}
+
+	parts := strings.Split(name, ".")
+	for i := len(stack) - 1; i >= 0; i-- {
+		value, found := mustacheField(stack[i], parts[0])
+		if !found {
+			continue
+		}
+
+		for _, p := range parts[1:] {
+			if value, found = mustacheField(value, p); !found {
+				return nil, false
+			}
+		}
+
+		return value, true
+	}
+
+	return nil, false
+}
+
+// mustacheField returns the named entry of a map or exported field of a struct.
+func mustacheField(context interface{}, name string) (interface{}, bool) {
+	rv := reflect.ValueOf(context)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil, false
+		}
+
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+
+		v := rv.MapIndex(reflect.ValueOf(name).Convert(rv.Type().Key()))
+		if !v.IsValid() {
+			return nil, false
+		}
+
+		return v.Interface(), true
+
+	case reflect.Struct:
+		f := rv.FieldByName(name)
+		if !f.IsValid() || !f.CanInterface() {
+			return nil, false
+		}
+
+		return f.Interface(), true
+
+	default:
+		return nil, false
+	}
+}
+
+// mustacheTemplate is a parsed Mustache template.
+type mustacheTemplate struct {
+	name      string
+	mediaType string
+	nodes     []mnode
+
+	// partials are the templates available to partial tags, by name
+	partials        map[string][]mnode
+	escape          func(string) (string, error)
+	missingKeyError bool
+	limits          limits
+}
+
+func (mt *mustacheTemplate) Name() string {
+	return mt.name
+}
+
+func (mt *mustacheTemplate) MediaType() string {
+	return mt.mediaType
+}
+
+// Execute renders this template.  The data is the bottom of the context stack.
+func (mt *mustacheTemplate) Execute(output io.Writer, data interface{}) error {
+	return mt.ExecuteWith(context.Background(), output, data)
+}
+
+// ExecuteWith is like Execute, but with per-execution options.  Mustache templates
+// have no functions, so function overrides result in an *UnsupportedExecuteOptionError.
+// The context is checked before rendering, for each element of a list section, for each
+// partial, and for each write to the output.
+func (mt *mustacheTemplate) ExecuteWith(ctx context.Context, output io.Writer, data interface{}, opts ...ExecuteOption) error {
+	if ec := NewExecuteConfig(opts...); len(ec.Funcs) > 0 {
+		return &UnsupportedExecuteOptionError{Name: mt.name, Option: "function overrides"}
+	}
+
+	ctx, cancel, err := mt.limits.context(ctx)
+	defer cancel()
+	if err != nil {
+		return err
+	}
+
+	r := &mrenderer{mt: mt, ctx: ctx}
+	return renderNodes(r, mt.limits.writer(ctx, output), []interface{}{data}, mt.nodes)
+}
+
+// mustacheScanner turns Mustache source into nodes.
+type mustacheScanner struct {
+	name   string
+	src    string
+	pos    int
+	ldelim string
+	rdelim string
+}
+
+func (ms *mustacheScanner) errorf(offset int, format string, args ...interface{}) error {
+	line := strings.Count(ms.src[:offset], "\n") + 1
+	column := offset - strings.LastIndexByte(ms.src[:offset], '\n')
+	return &PositionError{
+		Name:   ms.name,
+		Line:   line,
+		Column: column,
+		Err:    fmt.Errorf(format, args...),
+	}
+}
+
+// isBlank tests if a string contains only spaces and tabs.
+func isBlank(s string) bool {
+	return len(strings.Trim(s, " \t")) == 0
+}
+
+// standalone determines if the tag between start and end is the only thing on its
+// line other than whitespace.  If so, the returned indices are the beginning of the
+// line and the beginning of the next line.
+func (ms *mustacheScanner) standalone(start, end int) (lineStart, lineEnd int, ok bool) {
+	lineStart = strings.LastIndexByte(ms.src[:start], '\n') + 1
+	if lineStart < ms.pos || !isBlank(ms.src[lineStart:start]) {
+		return
+	}
+
+	lineEnd = strings.IndexByte(ms.src[end:], '\n')
+	if lineEnd < 0 {
+		lineEnd = len(ms.src)
+	} else {
+		lineEnd += end + 1
+	}
+
+	ok = isBlank(strings.TrimSuffix(strings.TrimSuffix(ms.src[end:lineEnd], "\n"), "\r"))
+	return
+}
+
+// parse produces the nodes up to the end of the source or the closing tag of the
+// enclosing section.  For the top level, section is empty.
+func (ms *mustacheScanner) parse(section string, sectionStart int) ([]mnode, error) {
+	var nodes []mnode
+	for {
+		i := strings.Index(ms.src[ms.pos:], ms.ldelim)
+		if i < 0 {
+			if len(section) > 0 {
+				return nil, ms.errorf(sectionStart, "unclosed section %s", section)
+			}
+
+			if ms.pos < len(ms.src) {
+				nodes = append(nodes, mtext(ms.src[ms.pos:]))
+			}
+
+			ms.pos = len(ms.src)
+			return nodes, nil
+		}
+
+		start := ms.pos + i
+		contentStart := start + len(ms.ldelim)
+		closer := ms.rdelim
+		triple := ms.ldelim == DefaultMustacheLeftDelim && strings.HasPrefix(ms.src[contentStart:], "{")
+		if triple {
+			closer = "}" + ms.rdelim
+		}
+
+		j := strings.Index(ms.src[contentStart:], closer)
+		if j < 0 {
+			return nil, ms.errorf(start, "unclosed tag")
+		}
+
+		end := contentStart + j + len(closer)
+		content := ms.src[contentStart : contentStart+j]
+		sigil := byte(0)
+		if triple {
+			sigil, content = '{', content[1:]
+		} else if trimmed := strings.TrimSpace(content); len(trimmed) > 0 && strings.IndexByte("#^/!>&=", trimmed[0]) >= 0 {
+			sigil, content = trimmed[0], trimmed[1:]
+		}
+
+		content = strings.TrimSpace(content)
+
+		// section, comment, partial, and delimiter tags that are alone on their
+		// line take the whole line with them
+		textEnd, next, indent := start, end, ""
+		if sigil != 0 && sigil != '&' && sigil != '{' {
+			if lineStart, lineEnd, ok := ms.standalone(start, end); ok {
+				textEnd, next, indent = lineStart, lineEnd, ms.src[lineStart:start]
+			}
+		}
+
+		if textEnd > ms.pos {
+			nodes = append(nodes, mtext(ms.src[ms.pos:textEnd]))
+		}
+
+		ms.pos = next
+		switch sigil {
+		case '!':
+			// comments produce no output
+
+		case '=':
+			if !strings.HasSuffix(content, "=") {
+				return nil, ms.errorf(start, "invalid delimiter tag")
+			}
+
+			delims := strings.Fields(strings.TrimSuffix(content, "="))
+			if len(delims) != 2 {
+				return nil, ms.errorf(start, "invalid delimiter tag")
+			}
+
+			ms.ldelim, ms.rdelim = delims[0], delims[1]
+
+		case '>':
+			nodes = append(nodes, mpartial{name: content, indent: indent})
+
+		case '#', '^':
+			s := &msection{name: content, inverted: sigil == '^'}
+			var err error
+			if s.nodes, err = ms.parse(content, start); err != nil {
+				return nil, err
+			}
+
+			nodes = append(nodes, s)
+
+		case '/':
+			if content != section {
+				if len(section) == 0 {
+					return nil, ms.errorf(start, "unexpected closing tag %s", content)
+				}
+
+				return nil, ms.errorf(start, "closing tag %s does not match section %s", content, section)
+			}
+
+			return nodes, nil
+
+		default:
+			if len(content) == 0 {
+				return nil, ms.errorf(start, "empty tag")
+			}
+
+			line := strings.Count(ms.src[:start], "\n") + 1
+			nodes = append(nodes, mvariable{
+				name:   content,
+				line:   line,
+				column: start - strings.LastIndexByte(ms.src[:start], '\n'),
+				raw:    sigil == '&' || sigil == '{',
+			})
+		}
+	}
+}
+
+// mustacheParser produces Mustache templates.
+type mustacheParser struct {
+	ldelim          string
+	rdelim          string
+	mediaType       string
+	escape          func(string) (string, error)
+	missingKeyError bool
+	partials        map[string][]mnode
+	limits          limits
+}
+
+// htmlEscaper replaces the characters that the Mustache specification requires be escaped.
+var htmlEscaper = strings.NewReplacer("&", "&amp;", "\"", "&quot;", "<", "&lt;", ">", "&gt;")
+
+// escapeHTML is the Mustache default escaping.
+func escapeHTML(s string) (string, error) {
+	return htmlEscaper.Replace(s), nil
+}
+
+// newMustacheParser is the Engine for EngineMustache.
+func newMustacheParser(c ParserConfig) (Parser, error) {
+	if _, err := templateOptions(c); err != nil {
+		return nil, err
+	}
+
+	mp := mustacheParser{
+		ldelim:          c.LeftDelim,
+		rdelim:          c.RightDelim,
+		mediaType:       c.MediaType,
+		escape:          escapeHTML,
+		missingKeyError: len(c.MissingKey) == 0 || c.MissingKey == MissingKeyError,
+		limits:          newLimits(c),
+	}
+
+	if len(mp.ldelim) == 0 {
+		mp.ldelim = DefaultMustacheLeftDelim
+	}
+
+	if len(mp.rdelim) == 0 {
+		mp.rdelim = DefaultMustacheRightDelim
+	}
+
+	if c.JSON {
+		mp.escape = func(s string) (string, error) { return JSONEscapeString(s) }
+	}
+
+	includes, err := readIncludes(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// each partial is known by its path, both with and without its final extension
+	mp.partials = make(map[string][]mnode, 2*len(includes))
+	defs := make(definitions)
+	for _, inc := range includes {
+		names := []string{inc.name}
+		if stem := strings.TrimSuffix(inc.name, path.Ext(inc.name)); stem != inc.name {
+			names = append(names, stem)
+		}
+
+		if err := defs.add(inc.name, names); err != nil {
+			return nil, err
+		}
+
+		nodes, err := mp.scan(inc.name, inc.content)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, n := range names {
+			mp.partials[n] = nodes
+		}
+	}
+
+	return mp, nil
+}
+
+// scan parses Mustache source into nodes.
+func (mp mustacheParser) scan(name, content string) ([]mnode, error) {
+	ms := mustacheScanner{
+		name:   name,
+		src:    content,
+		ldelim: mp.ldelim,
+		rdelim: mp.rdelim,
+	}
+
+	return ms.parse("", 0)
+}
+
+func (mp mustacheParser) Parse(name, content string) (Template, error) {
+	nodes, err := mp.scan(name, content)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mustacheTemplate{
+		name:            name,
+		mediaType:       mp.mediaType,
+		nodes:           nodes,
+		partials:        mp.partials,
+		escape:          mp.escape,
+		missingKeyError: mp.missingKeyError,
+		limits:          mp.limits,
+	}, nil
+}
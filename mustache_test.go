@@ -0,0 +1,249 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// testPartials are the Mustache partials available to tests.
+var testPartials = fstest.MapFS{
+	"partials/item.mustache":  {Data: []byte("<{{name}}>\n")},
+	"partials/loop.mustache":  {Data: []byte("{{>partials/loop}}")},
+	"partials/lines.mustache": {Data: []byte("a\nb\n")},
+}
+
+func TestMustacheTemplate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		config   ParserConfig
+		template string
+		data     interface{}
+		expected string
+	}{
+		{
+			name:     "Text",
+			template: "plain text",
+			expected: "plain text",
+		},
+		{
+			name:     "Escaped",
+			template: `{{v}}|{{{v}}}|{{&v}}`,
+			data:     Model{"v": `<a href="x">&</a>`},
+			expected: `&lt;a href=&quot;x&quot;&gt;&amp;&lt;/a&gt;|<a href="x">&</a>|<a href="x">&</a>`,
+		},
+		{
+			name:     "Dotted",
+			template: "{{a.b.c}}",
+			data:     Model{"a": map[string]interface{}{"b": map[string]interface{}{"c": 1}}},
+			expected: "1",
+		},
+		{
+			name:     "Struct",
+			template: "{{Name}}",
+			data:     struct{ Name string }{Name: "n"},
+			expected: "n",
+		},
+		{
+			name:     "Nil",
+			template: "[{{v}}]",
+			data:     Model{"v": nil},
+			expected: "[]",
+		},
+		{
+			name:     "List",
+			template: "{{#list}}({{.}}){{/list}}",
+			data:     Model{"list": []interface{}{1, 2, 3}},
+			expected: "(1)(2)(3)",
+		},
+		{
+			name:     "ContextStack",
+			template: "{{#items}}{{name}}-{{suffix}} {{/items}}",
+			data:     Model{"suffix": "s", "items": []interface{}{Model{"name": "a"}, Model{"name": "b"}}},
+			expected: "a-s b-s ",
+		},
+		{
+			name:     "FalseSection",
+			template: "{{#flag}}yes{{/flag}}{{^flag}}no{{/flag}}",
+			data:     Model{"flag": false},
+			expected: "no",
+		},
+		{
+			name:     "EmptyList",
+			template: "{{#list}}x{{/list}}{{^list}}empty{{/list}}",
+			data:     Model{"list": []interface{}{}},
+			expected: "empty",
+		},
+		{
+			name:     "Standalone",
+			template: "begin\n  {{#flag}}\n  inside\n  {{/flag}}\n{{! comment }}\nend\n",
+			data:     Model{"flag": true},
+			expected: "begin\n  inside\nend\n",
+		},
+		{
+			name:     "Delimiters",
+			template: "{{=<% %>=}}<% v %> {{v}}",
+			data:     Model{"v": "x"},
+			expected: "x {{v}}",
+		},
+		{
+			name:     "ConfiguredDelimiters",
+			config:   ParserConfig{LeftDelim: "[[", RightDelim: "]]"},
+			template: "[[v]] {{v}}",
+			data:     Model{"v": "x"},
+			expected: "x {{v}}",
+		},
+		{
+			name:     "Partial",
+			config:   ParserConfig{FS: testPartials, Includes: []string{"partials/*.mustache"}},
+			template: "{{#items}}{{>partials/item}}{{/items}}",
+			data:     Model{"items": []interface{}{Model{"name": "a"}, Model{"name": "b"}}},
+			expected: "<a>\n<b>\n",
+		},
+		{
+			name:     "PartialWithExtension",
+			config:   ParserConfig{FS: testPartials, Includes: []string{"partials/*.mustache"}},
+			template: "{{>partials/lines.mustache}}",
+			expected: "a\nb\n",
+		},
+		{
+			name:     "IndentedPartial",
+			config:   ParserConfig{FS: testPartials, Includes: []string{"partials/*.mustache"}},
+			template: "list:\n  {{>partials/lines}}\n",
+			expected: "list:\n  a\n  b\n",
+		},
+		{
+			name:     "MissingPartial",
+			template: "[{{>nosuch}}]",
+			expected: "[]",
+		},
+		{
+			name:     "MissingKeyDefault",
+			config:   ParserConfig{MissingKey: MissingKeyDefault},
+			template: "[{{nosuch}}]",
+			data:     Model{},
+			expected: "[]",
+		},
+		{
+			name:     "JSON",
+			config:   ParserConfig{JSON: true},
+			template: `{"v": "{{v}}"}`,
+			data:     Model{"v": "a \"b\"\n<c>"},
+			expected: `{"v": "a \"b\"\n<c>"}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.config.Engine = EngineMustache
+			p, err := NewParser(testCase.config)
+			if err != nil {
+				t.Fatalf("unable to create parser: %s", err)
+			}
+
+			tmpl, err := p.Parse("t", testCase.template)
+			if err != nil {
+				t.Fatalf("unable to parse template: %s", err)
+			}
+
+			var o strings.Builder
+			if err := tmpl.Execute(&o, testCase.data); err != nil || o.String() != testCase.expected {
+				t.Errorf("expected %q, got %q (%v)", testCase.expected, o.String(), err)
+			}
+		})
+	}
+}
+
+func TestMustacheTemplateErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		config   ParserConfig
+		template string
+		parseErr string
+		execErr  string
+	}{
+		{name: "UnclosedTag", template: "a\n  {{v", parseErr: "template: t:2:3: unclosed tag"},
+		{name: "UnclosedSection", template: "{{#s}}x", parseErr: "template: t:1:1: unclosed section s"},
+		{name: "MismatchedSection", template: "{{#s}}{{/t}}", parseErr: "closing tag t does not match section s"},
+		{name: "UnexpectedClose", template: "{{/t}}", parseErr: "unexpected closing tag t"},
+		{name: "EmptyTag", template: "{{ }}", parseErr: "empty tag"},
+		{name: "InvalidDelimiters", template: "{{=<%=}}", parseErr: "invalid delimiter tag"},
+		{name: "BadPartial", config: ParserConfig{FS: fstest.MapFS{"p.mustache": {Data: []byte("{{#x}}")}}, Includes: []string{"*.mustache"}}, parseErr: "template: p.mustache:1:1: unclosed section x"},
+		{name: "IncludesWithoutFS", config: ParserConfig{Includes: []string{"*.mustache"}}, parseErr: ErrIncludesWithoutFS.Error()},
+		{name: "MissingKey", template: "a\n {{nosuch}}", execErr: "template: t:2:2: no value for nosuch"},
+		{
+			name:     "SelfPartial",
+			config:   ParserConfig{FS: testPartials, Includes: []string{"partials/*.mustache"}},
+			template: "{{>partials/loop}}",
+			execErr:  `cannot invoke "partials/loop": exceeded the maximum template depth of 1000`,
+		},
+		{
+			name:     "MaxDepth",
+			config:   ParserConfig{FS: testPartials, Includes: []string{"partials/*.mustache"}, MaxDepth: 5},
+			template: "{{>partials/loop}}",
+			execErr:  "exceeded the maximum template depth of 5",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.config.Engine = EngineMustache
+			p, err := NewParser(testCase.config)
+			var tmpl Template
+			if err == nil {
+				tmpl, err = p.Parse("t", testCase.template)
+			}
+
+			if len(testCase.parseErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), testCase.parseErr) {
+					t.Errorf("expected an error containing %q, got %v", testCase.parseErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unable to parse template: %s", err)
+			}
+
+			var o strings.Builder
+			if err := tmpl.Execute(&o, Model{}); err == nil || !strings.Contains(err.Error(), testCase.execErr) {
+				t.Errorf("expected an error containing %q, got %v", testCase.execErr, err)
+			}
+		})
+	}
+}
+
+func TestMustacheTemplateExecuteWith(t *testing.T) {
+	p, err := NewParser(ParserConfig{Engine: EngineMustache})
+	if err != nil {
+		t.Fatalf("unable to create parser: %s", err)
+	}
+
+	tmpl, err := p.Parse("t", "{{#list}}{{.}}{{/list}}")
+	if err != nil {
+		t.Fatalf("unable to parse template: %s", err)
+	}
+
+	var o strings.Builder
+	var ueoe *UnsupportedExecuteOptionError
+	err = ExecuteWith(context.Background(), tmpl, &o, nil, WithFunc("f", func() string { return "" }))
+	if !errors.As(err, &ueoe) || ueoe.Option != "function overrides" || ueoe.Name != "t" {
+		t.Errorf("expected an UnsupportedExecuteOptionError, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := ExecuteWith(ctx, tmpl, &o, Model{"list": []interface{}{1}}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+
+	if o.Len() > 0 {
+		t.Errorf("a canceled execution should produce no output, got %q", o.String())
+	}
+}
@@ -4,9 +4,14 @@
 package thoth
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	htemplate "html/template"
+	"io"
+	"io/fs"
 	ttemplate "text/template"
+	"time"
 )
 
 const (
@@ -17,6 +22,16 @@ const (
 	DefaultMissingKey = MissingKeyError
 )
 
+var (
+	// ErrJSONWithHTML indicates that both the JSON and HTML options were set in
+	// a ParserConfig.  Only one escaping mode can apply to a template.
+	ErrJSONWithHTML = errors.New("the JSON and HTML options cannot both be set")
+
+	// ErrHTMLWithEngine indicates that the HTML option was set along with an
+	// engine other than EngineGoHTML.
+	ErrHTMLWithEngine = errors.New("the HTML option can only be used with the go-html engine")
+)
+
 // InvalidMissingKeyError indicates that an unrecognized value was used in
 // configuration for MissingKey.  The text/template and html/template packages
 // will panic in this case, whereas this package uses this error.
@@ -31,11 +46,38 @@ func (imke *InvalidMissingKeyError) Error() string {
 
 // ParserConfig is the set of configurable options for building a Parser.
 type ParserConfig struct {
+	// Engine is the name of the registered Engine that produces the Parser.  If unset,
+	// EngineGoHTML is used when HTML is set, and EngineGoText is used otherwise.  See
+	// RegisterEngine.
+	//
+	// With EngineMustache, templates use the logic-less Mustache syntax.  Variables are
+	// HTML-escaped as the Mustache specification requires, unless the JSON option is set,
+	// in which case they are escaped as JSON string content.  LeftDelim and RightDelim set
+	// the initial Mustache delimiters, and MissingKey applies to variables but not sections.
+	//
+	// With EngineStructured, each template is a JSON or YAML document.  A string value
+	// may contain text/template pipelines within "${" and "}", e.g. "${ .device.id }".
+	// A string that is a single expression takes on the expression's value, whatever its
+	// type, while other strings interpolate their expressions as text.  Within an
+	// expression, dot is the current value and $root is the template's data.  Mappings
+	// with $if or $each keys are directives for conditionals and iteration.  Rendered
+	// output is YAML for YAML media types and JSON for all others, so it is always
+	// well-formed.  The HTML, JSON, LeftDelim, and RightDelim options do not apply.
+	Engine string `json:"engine" yaml:"engine"`
+
 	// HTML indicates which template package to use.  If this field is false,
 	// which is the default, text/template is used by the returned Parser.
-	// If this field is true, html/template is used instead.
+	// If this field is true, html/template is used instead.  Setting this field
+	// is the same as using EngineGoHTML, and it cannot be used with any other Engine.
 	HTML bool `json:"html" yaml:"html"`
 
+	// JSON enables context-aware JSON escaping for text/template and JSON escaping of
+	// Mustache variables.  For text/template, the output of each action inside a JSON
+	// string literal is escaped as string content, and the output of each action anywhere
+	// else is encoded as a complete JSON value.  A json.RawMessage is written as is.
+	// This option cannot be used with HTML.
+	JSON bool `json:"json" yaml:"json"`
+
 	// MissingKey is the "missingkey=..." option.  If unset, error is used.
 	// If this field is set to an unrecognized value, an error is raised.
 	MissingKey string `json:"missingKey" yaml:"missingKey"`
@@ -46,12 +88,92 @@ type ParserConfig struct {
 	// RightDelim is the right delimiter for pipelines.  If unset, the default "}}" is used.
 	RightDelim string `json:"rightDelim" yaml:"rightDelim"`
 
-	// FuncMap is the function map for all templates returned by the Parser.
+	// Functions are the names of registered function sets to install in every template
+	// returned by the Parser.  The sets are installed in order, so a later set replaces
+	// any same-named functions from an earlier set.  The built-in sets are FuncSetStrings,
+	// FuncSetMath, FuncSetCollections, FuncSetEncoding, FuncSetTime, and FuncSetRandom.
+	// See RegisterFuncSet.  Mustache templates have no functions, so this option does not
+	// apply to them.
+	Functions []string `json:"functions" yaml:"functions"`
+
+	// FuncMap is the function map for all templates returned by the Parser.  These
+	// functions replace any same-named functions from the Functions sets.  Golang
+	// templates also have the IncludeFunc function unless this map replaces it.
 	FuncMap map[string]interface{} `json:"-" yaml:"-"`
 
+	// Clock is consulted by the FuncSetTime functions for the current time.  If unset,
+	// SystemClock is used.  Use FixedClock to render time-dependent templates reproducibly.
+	Clock Clock `json:"-" yaml:"-"`
+
+	// Entropy is the source of random bytes for the FuncSetRandom functions.  If unset,
+	// SystemEntropy is used.  Use SeededEntropy to render templates reproducibly, and
+	// WithEntropy to give a single execution its own source.
+	Entropy io.Reader `json:"-" yaml:"-"`
+
+	// Timeout bounds each execution of a template, in addition to any deadline of the
+	// context passed to ExecuteWith or ExecuteContext.  In YAML, this is a duration
+	// string such as "5s".  If unset, executions have no timeout.
+	//
+	// Golang templates check for a done context at the beginning of each range body and
+	// each template invocation, and whenever output is written.  Other templates check
+	// before they execute and when their output is written.
+	Timeout time.Duration `json:"timeout" yaml:"timeout"`
+
+	// MaxOutput is the maximum number of bytes a single execution may write.  An execution
+	// that would write more fails with an *OutputLimitError.  If unset, output is unlimited.
+	// Output written before the limit is reached is not retracted, so callers that must not
+	// see partial output should render into a buffer or use ValidatingTemplate.
+	MaxOutput int64 `json:"maxOutput" yaml:"maxOutput"`
+
+	// MaxDepth is the maximum depth of nested template invocations within golang templates,
+	// counting the executed template itself.  Exceeding it fails with a *TemplateDepthError.
+	// If unset, only the golang template packages' own limit applies.
+	MaxDepth int `json:"maxDepth" yaml:"maxDepth"`
+
+	// MaxIncludeDepth is the maximum number of nested IncludeFunc calls.  Exceeding it fails
+	// with an *IncludeDepthError.  If unset, DefaultMaxIncludeDepth is used.
+	MaxIncludeDepth int `json:"maxIncludeDepth" yaml:"maxIncludeDepth"`
+
 	// MediaType is the media type associated with all rendered templates produced
 	// by this parser configuration.  If unset, DefaultMediaType is assumed.
 	MediaType string `json:"mediaType" yaml:"mediaType"`
+
+	// Defaults is the common model of values that every template's data receives
+	// when the data does not already have those keys.
+	Defaults Model `json:"defaults" yaml:"defaults"`
+
+	// Overrides is the common model of values that unconditionally replace
+	// the corresponding keys in every template's data.
+	Overrides Model `json:"overrides" yaml:"overrides"`
+
+	// Merge controls how Defaults and Overrides are applied.  By default, only
+	// top-level keys are applied.  A Delete value in Overrides, written as
+	// !delete in YAML, removes a key from the data.
+	Merge MergeConfig `json:"merge" yaml:"merge"`
+
+	// Includes are globs for shared files that are parsed once, when the Parser is
+	// created, and read from FS.  For golang templates, the definitions in these files
+	// can be invoked from every template the Parser produces.  For Mustache templates,
+	// each file is a partial named by its path, with or without its final extension.
+	// Defining the same template in more than one file is an error.  Includes do not
+	// apply to structured templates.
+	Includes []string `json:"includes" yaml:"includes"`
+
+	// FS is the file system that Includes and layouts are read from.  This field is
+	// required if there are any Includes or if any template extends a layout.
+	//
+	// A golang template extends a layout by beginning with a comment such as
+	// {{/* extends "layouts/base.tmpl" */}}, where the path is relative to FS.  The layout
+	// is parsed by the same Parser, and the template is rendered by executing the layout.
+	// Each define or block in the template overrides the layout's definition of the same
+	// name, and any other content in the template is ignored.  Layouts may themselves
+	// extend layouts, but a chain of layouts may not contain a cycle.  When the Parser
+	// comes from NewSelector, a layout that the Selector assigns to another configuration
+	// is rejected with a *LayoutSelectionError rather than parsed with the wrong one.
+	FS fs.FS `json:"-" yaml:"-"`
+
+	// selector is the Selector that created the Parser for this configuration, if any
+	selector *matchSelector
 }
 
 // templateOptions determines the options to use in prototype templates
@@ -76,28 +198,75 @@ func templateOptions(c ParserConfig) (o []string, err error) {
 	return
 }
 
-// newPrototype produces the prototype text/template or html/template using
-// the given configuration.
-func newPrototype(c ParserConfig) (prototype interface{}, err error) {
-	var options []string
-	options, err = templateOptions(c)
-	if err == nil {
-		if c.HTML {
-			t := htemplate.New("prototype")
-			t.Funcs(c.FuncMap)
-			t.Delims(c.LeftDelim, c.RightDelim)
-			t.Option(options...)
-			prototype = t
-		} else {
-			t := ttemplate.New("prototype")
-			t.Funcs(c.FuncMap)
-			t.Delims(c.LeftDelim, c.RightDelim)
-			t.Option(options...)
-			prototype = t
-		}
+// newTextPrototype produces the prototype text/template using the given configuration.
+// Any includes are parsed into the prototype, and their definitions are returned.
+// The prototype is not escaped for JSON.
+func newTextPrototype(c ParserConfig) (*ttemplate.Template, definitions, error) {
+	options, err := templateOptions(c)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return
+	t := ttemplate.New("prototype")
+	t.Funcs(ttemplate.FuncMap{IncludeFunc: unboundInclude})
+	t.Funcs(c.FuncMap)
+	t.Funcs(limitFuncs)
+	if c.JSON {
+		t.Funcs(jsonFuncs)
+	}
+
+	t.Delims(c.LeftDelim, c.RightDelim)
+	t.Option(options...)
+
+	defs, err := parseIncludes(c, func(name, content string) error {
+		_, err := t.New(name).Parse(content)
+		return err
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, at := range t.Templates() {
+		instrument(at.Name(), at.Tree, c.MaxDepth > 0)
+	}
+
+	return t, defs, nil
+}
+
+// newHTMLPrototype produces the prototype html/template using the given configuration.
+// Any includes are parsed into the prototype, and their definitions are returned.
+func newHTMLPrototype(c ParserConfig) (*htemplate.Template, definitions, error) {
+	options, err := templateOptions(c)
+	if err == nil && c.JSON {
+		err = ErrJSONWithHTML
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	t := htemplate.New("prototype")
+	t.Funcs(htemplate.FuncMap{IncludeFunc: unboundInclude})
+	t.Funcs(c.FuncMap)
+	t.Funcs(limitFuncs)
+	t.Delims(c.LeftDelim, c.RightDelim)
+	t.Option(options...)
+
+	defs, err := parseIncludes(c, func(name, content string) error {
+		_, err := t.New(name).Parse(content)
+		return err
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, at := range t.Templates() {
+		instrument(at.Name(), at.Tree, c.MaxDepth > 0)
+	}
+
+	return t, defs, nil
 }
 
 // Parser is a template parser.  A ParserConfig defines all the options
@@ -106,63 +275,329 @@ type Parser interface {
 	// Parse produces a Template from some parsed content.  The name is
 	// optional, and can be the empty string.
 	//
-	// If a common Model was defined in ParserConfig, the returned Template
+	// If Defaults or Overrides were defined in ParserConfig, the returned Template
 	// will also implement ModelTemplate.
 	//
 	// For golang templates, an internal prototype template is first cloned
 	// and then used as the containing template for each parsed template.  This
-	// ensures that every template is unaffected by global definitions in other templates.
+	// ensures that every template is unaffected by global definitions in other templates,
+	// while the definitions from any includes are available to every template.
 	Parse(name, content string) (Template, error)
 }
 
-// NewParser creates a Parser from a set of configuration options.  Templates returned
-// by the parser created by this function will also implement MediaTyper, which will
+// NewParser creates a Parser from a set of configuration options.  The Engine option
+// selects the registered Engine that creates the Parser.  Templates returned by the
+// parser created by this function will also implement MediaTyper, which will
 // associated each Template with the media type specified in the config.
 func NewParser(c ParserConfig) (Parser, error) {
-	prototype, err := newPrototype(c)
+	if err := c.Merge.Validate(); err != nil {
+		return nil, err
+	}
+
+	name := c.Engine
+	switch {
+	case len(name) == 0 && c.HTML:
+		name = EngineGoHTML
+
+	case len(name) == 0:
+		name = EngineGoText
+
+	case c.HTML && name != EngineGoHTML:
+		return nil, ErrHTMLWithEngine
+	}
+
+	e, found := EngineFor(name)
+	if !found {
+		return nil, &UnsupportedEngineError{Engine: name}
+	}
+
+	sets := c
+	fm, err := funcMap(c)
 	if err != nil {
 		return nil, err
 	}
 
-	return golangParser{
-		prototype: prototype,
-		mediaType: c.MediaType,
-	}, nil
+	c.FuncMap = fm
+	p, err := e.NewParser(c)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(c.Functions) > 0 && name != EngineMustache {
+		// Mustache templates have no functions to rebuild
+		p = funcSetParser{
+			Parser: p,
+			config: sets,
+		}
+	}
+
+	if len(c.Defaults) > 0 || len(c.Overrides) > 0 {
+		p = modelParser{
+			Parser:    p,
+			defaults:  KeyValues{}.Append(c.Defaults),
+			overrides: KeyValues{}.Append(c.Overrides),
+			merge:     c.Merge,
+		}
+	}
+
+	return p, nil
+}
+
+// modelParser decorates the templates from another Parser so that
+// they are ModelTemplates.
+type modelParser struct {
+	Parser
+	defaults  KeyValues
+	overrides KeyValues
+	merge     MergeConfig
+}
+
+func (mp modelParser) Parse(name, content string) (t Template, err error) {
+	t, err = mp.Parser.Parse(name, content)
+	if err == nil {
+		t = EnrichTemplate(t, mp.defaults, mp.overrides, mp.merge)
+	}
+
+	return
 }
 
-type golangParser struct {
-	// prototype is a text/template.Template or html/template.Template which
-	// gets cloned to make new templates.
-	prototype interface{}
+// textParser produces text/template templates.
+type textParser struct {
+	// prototype is cloned to make new templates
+	prototype *ttemplate.Template
+
+	// unescaped is the prototype before JSON escaping, which the
+	// include function renders with
+	unescaped *ttemplate.Template
 
 	// mediaType is the media type used when a template doesn't specify one
 	mediaType string
+
+	// json indicates that templates are escaped for JSON after parsing
+	json bool
+
+	// include indicates that the built-in include function is bound to each template
+	include bool
+
+	// limits are the resource bounds for executing templates
+	limits limits
+
+	// definitions are the templates defined by includes
+	definitions definitions
+
+	// layouts resolves the layouts that templates extend
+	layouts layouts
+
+	leftDelim  string
+	rightDelim string
 }
 
-func (gp golangParser) Parse(name, content string) (t Template, err error) {
-	switch pt := gp.prototype.(type) {
-	case *ttemplate.Template:
-		var raw *ttemplate.Template
-		raw, err = pt.Clone()
+// newTextParser is the Engine for EngineGoText.
+func newTextParser(c ParserConfig) (Parser, error) {
+	unescaped, defs, err := newTextPrototype(c)
+	prototype := unescaped
+	if err == nil && c.JSON {
+		prototype, err = copyTrees(unescaped)
 		if err == nil {
-			raw, err = raw.New(name).Parse(content)
-			if err == nil {
-				t = MediaTemplate(raw, gp.mediaType)
+			err = escapeJSON(prototype.Templates())
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return textParser{
+		prototype:   prototype,
+		unescaped:   unescaped,
+		mediaType:   c.MediaType,
+		json:        c.JSON,
+		include:     bindsInclude(c),
+		limits:      newLimits(c),
+		definitions: defs,
+		layouts:     newLayouts(c),
+		leftDelim:   c.LeftDelim,
+		rightDelim:  c.RightDelim,
+	}, nil
+}
+
+// checkDefinitions reports any definitions in golang template source that
+// redefine templates from includes.
+func checkDefinitions(defs definitions, name, content, leftDelim, rightDelim string) error {
+	if len(defs) == 0 {
+		return nil
+	}
+
+	names, err := golangDefinitions(name, content, leftDelim, rightDelim)
+	if err == nil {
+		err = defs.check(name, names)
+	}
+
+	return err
+}
+
+func (tp textParser) Parse(name, content string) (t Template, err error) {
+	chain, err := tp.layouts.chain(name, content)
+	for i := 0; err == nil && i < len(chain); i++ {
+		err = checkDefinitions(tp.definitions, chain[i].name, chain[i].content, tp.leftDelim, tp.rightDelim)
+	}
+
+	var set *ttemplate.Template
+	if err == nil {
+		set, err = tp.prototype.Clone()
+	}
+
+	// layouts are parsed first, so that the definitions in the
+	// template being parsed override theirs
+	for i := 0; err == nil && i < len(chain); i++ {
+		_, err = set.New(chain[i].name).Parse(chain[i].content)
+	}
+
+	if err == nil {
+		for _, at := range set.Templates() {
+			instrument(at.Name(), at.Tree, tp.limits.maxDepth > 0)
+		}
+	}
+
+	// include renders the templates as they were written, and
+	// its output is escaped wherever it is used
+	var pristine *ttemplate.Template
+	if err == nil && tp.json {
+		// the templates from includes share their parse trees with
+		// the prototype, which has already been escaped
+		var parsed []*ttemplate.Template
+		for _, at := range set.Templates() {
+			if pt := tp.prototype.Lookup(at.Name()); pt == nil || pt.Tree != at.Tree {
+				parsed = append(parsed, at)
 			}
 		}
 
-	case *htemplate.Template:
-		var raw *htemplate.Template
-		raw, err = pt.Clone()
+		if tp.include {
+			pristine, err = withTrees(tp.unescaped, parsed)
+		}
+
 		if err == nil {
-			raw, err = raw.New(name).Parse(content)
-			if err == nil {
-				t = MediaTemplate(raw, gp.mediaType)
-			}
+			err = escapeJSON(parsed)
 		}
+	} else if err == nil && tp.include {
+		pristine, err = set.Clone()
+	}
 
-	default:
-		panic(fmt.Errorf("%T is not a template", t))
+	if err == nil && tp.include {
+		bindTextInclude(set, pristine, nil, newIncludeLevels(&execState{ctx: context.Background()}, tp.limits))
+	}
+
+	if err == nil {
+		tt := textTemplate{
+			Template:  set.Lookup(chain[0].name),
+			name:      name,
+			pristine:  pristine,
+			mediaType: tp.mediaType,
+			limits:    tp.limits,
+		}
+
+		tt.instances = &instances{
+			create: func(state *execState) (includeSet, error) {
+				return tt.instance(state, nil)
+			},
+		}
+
+		t = tt
+	}
+
+	return
+}
+
+// htmlParser produces html/template templates.
+type htmlParser struct {
+	// prototype is cloned to make new templates
+	prototype *htemplate.Template
+
+	// mediaType is the media type used when a template doesn't specify one
+	mediaType string
+
+	// include indicates that the built-in include function is bound to each template
+	include bool
+
+	// limits are the resource bounds for executing templates
+	limits limits
+
+	// definitions are the templates defined by includes
+	definitions definitions
+
+	// layouts resolves the layouts that templates extend
+	layouts layouts
+
+	leftDelim  string
+	rightDelim string
+}
+
+// newHTMLParser is the Engine for EngineGoHTML.
+func newHTMLParser(c ParserConfig) (Parser, error) {
+	prototype, defs, err := newHTMLPrototype(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return htmlParser{
+		prototype:   prototype,
+		mediaType:   c.MediaType,
+		include:     bindsInclude(c),
+		limits:      newLimits(c),
+		definitions: defs,
+		layouts:     newLayouts(c),
+		leftDelim:   c.LeftDelim,
+		rightDelim:  c.RightDelim,
+	}, nil
+}
+
+func (hp htmlParser) Parse(name, content string) (t Template, err error) {
+	chain, err := hp.layouts.chain(name, content)
+	for i := 0; err == nil && i < len(chain); i++ {
+		err = checkDefinitions(hp.definitions, chain[i].name, chain[i].content, hp.leftDelim, hp.rightDelim)
+	}
+
+	var set *htemplate.Template
+	if err == nil {
+		set, err = hp.prototype.Clone()
+	}
+
+	// layouts are parsed first, so that the definitions in the
+	// template being parsed override theirs
+	for i := 0; err == nil && i < len(chain); i++ {
+		_, err = set.New(chain[i].name).Parse(chain[i].content)
+	}
+
+	var pristine *htemplate.Template
+	if err == nil {
+		for _, at := range set.Templates() {
+			instrument(at.Name(), at.Tree, hp.limits.maxDepth > 0)
+		}
+
+		pristine, err = set.Clone()
+	}
+
+	if err == nil && hp.include {
+		bindHTMLInclude(set, pristine, nil, newIncludeLevels(&execState{ctx: context.Background()}, hp.limits))
+	}
+
+	if err == nil {
+		ht := htmlTemplate{
+			Template:  set.Lookup(chain[0].name),
+			name:      name,
+			pristine:  pristine,
+			include:   hp.include,
+			mediaType: hp.mediaType,
+			limits:    hp.limits,
+		}
+
+		ht.instances = &instances{
+			create: func(state *execState) (includeSet, error) {
+				return ht.instance(state, nil)
+			},
+		}
+
+		t = ht
 	}
 
 	return
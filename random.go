@@ -0,0 +1,153 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// alphaNum is the alphabet for the randAlphaNum template function.
+const alphaNum = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+// ErrEmptyRange is returned by the randInt template function when its
+// maximum is not greater than its minimum.
+var ErrEmptyRange = errors.New("the maximum must be greater than the minimum")
+
+// random generates the values for the FuncSetRandom functions, drawing all
+// of its randomness from the ParserConfig.Entropy reader.
+type random struct {
+	r io.Reader
+}
+
+// randomFuncs is the FuncSetRandom function set.
+func randomFuncs(c ParserConfig) map[string]interface{} {
+	rnd := random{r: entropy(c)}
+	return map[string]interface{}{
+		"uuid":         rnd.uuid,
+		"randInt":      rnd.randInt,
+		"randFloat":    rnd.randFloat,
+		"randAlphaNum": rnd.randAlphaNum,
+		"randHex":      rnd.randHex,
+		"randChoice":   rnd.randChoice,
+		"shuffle":      rnd.shuffle,
+	}
+}
+
+func (rnd random) bytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	_, err := io.ReadFull(rnd.r, b)
+	return b, err
+}
+
+// uint64n returns a uniformly distributed value in [0, n), which must be nonzero.
+func (rnd random) uint64n(n uint64) (uint64, error) {
+	limit := math.MaxUint64 - math.MaxUint64%n
+	for {
+		b, err := rnd.bytes(8)
+		if err != nil {
+			return 0, err
+		}
+
+		if v := binary.LittleEndian.Uint64(b); v < limit {
+			return v % n, nil
+		}
+	}
+}
+
+// uuid returns a random, version 4 UUID.
+func (rnd random) uuid() (string, error) {
+	b, err := rnd.bytes(16)
+	if err != nil {
+		return "", err
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// randInt returns a random integer in [min, max).
+func (rnd random) randInt(minimum, maximum interface{}) (int64, error) {
+	lo, err := toInt(minimum)
+	if err != nil {
+		return 0, err
+	}
+
+	hi, err := toInt(maximum)
+	if err != nil {
+		return 0, err
+	} else if hi <= lo {
+		return 0, ErrEmptyRange
+	}
+
+	v, err := rnd.uint64n(uint64(hi - lo))
+	return lo + int64(v), err
+}
+
+// randFloat returns a random floating point number in [0.0, 1.0).
+func (rnd random) randFloat() (float64, error) {
+	v, err := rnd.uint64n(1 << 53)
+	return float64(v) / (1 << 53), err
+}
+
+// randAlphaNum returns a random string of n letters and digits.
+func (rnd random) randAlphaNum(n int) (string, error) {
+	s := make([]byte, max(n, 0))
+	for i := range s {
+		v, err := rnd.uint64n(uint64(len(alphaNum)))
+		if err != nil {
+			return "", err
+		}
+
+		s[i] = alphaNum[v]
+	}
+
+	return string(s), nil
+}
+
+// randHex returns n random bytes, encoded as hexadecimal.
+func (rnd random) randHex(n int) (string, error) {
+	b, err := rnd.bytes(max(n, 0))
+	return hex.EncodeToString(b), err
+}
+
+// randChoice returns a random element of a list, or nil if the list is empty.
+func (rnd random) randChoice(l interface{}) (interface{}, error) {
+	items, err := toList(l)
+	if err != nil || len(items) == 0 {
+		return nil, err
+	}
+
+	v, err := rnd.uint64n(uint64(len(items)))
+	if err != nil {
+		return nil, err
+	}
+
+	return items[v], nil
+}
+
+// shuffle returns a copy of a list in random order.
+func (rnd random) shuffle(l interface{}) ([]interface{}, error) {
+	items, err := toList(l)
+	if err != nil {
+		return nil, err
+	}
+
+	result := append([]interface{}(nil), items...)
+	for i := len(result) - 1; i > 0; i-- {
+		j, err := rnd.uint64n(uint64(i + 1))
+		if err != nil {
+			return nil, err
+		}
+
+		result[i], result[j] = result[j], result[i]
+	}
+
+	return result, nil
+}
@@ -0,0 +1,217 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"context"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestRandomFuncs(t *testing.T) {
+	testCases := []struct {
+		name     string
+		template string
+		pattern  string
+		err      error
+	}{
+		{name: "UUID", template: `{{ uuid }}`, pattern: `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`},
+		{name: "RandInt", template: `{{ randInt 5 8 }}`, pattern: `^[567]$`},
+		{name: "RandIntNegative", template: `{{ randInt -3 -1 }}`, pattern: `^-[23]$`},
+		{name: "RandIntEmpty", template: `{{ randInt 5 5 }}`, err: ErrEmptyRange},
+		{name: "RandFloat", template: `{{ randFloat }}`, pattern: `^0(\.\d+)?(e-\d+)?$`},
+		{name: "RandAlphaNum", template: `{{ randAlphaNum 12 }}|{{ randAlphaNum -1 }}`, pattern: `^[A-Za-z0-9]{12}\|$`},
+		{name: "RandHex", template: `{{ randHex 4 }}`, pattern: `^[0-9a-f]{8}$`},
+		{name: "RandChoice", template: `{{ randChoice .list }}|{{ randChoice .empty }}`, pattern: `^[abc]\|<no value>$`},
+		{name: "Shuffle", template: `{{ shuffle .list | sortAlpha }}`, pattern: `^\[a b c\]$`},
+	}
+
+	p, err := NewParser(ParserConfig{
+		Functions: []string{FuncSetRandom, FuncSetCollections},
+		Entropy:   SeededEntropy(1),
+	})
+
+	if err != nil {
+		t.Fatalf("unable to create parser: %s", err)
+	}
+
+	data := Model{"list": []string{"a", "b", "c"}, "empty": []string{}}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			tmpl, err := p.Parse("t", testCase.template)
+			if err != nil {
+				t.Fatalf("unable to parse template: %s", err)
+			}
+
+			// enough executions to see the values vary
+			for i := 0; i < 20; i++ {
+				var o strings.Builder
+				err = tmpl.Execute(&o, data)
+				switch {
+				case testCase.err != nil:
+					if !errors.Is(err, testCase.err) {
+						t.Fatalf("expected %v, got %v", testCase.err, err)
+					}
+
+				case err != nil || !regexp.MustCompile(testCase.pattern).MatchString(o.String()):
+					t.Fatalf("expected output matching %s, got %q (%v)", testCase.pattern, o.String(), err)
+				}
+			}
+		})
+	}
+}
+
+// renderRandom renders a template that uses random functions with the given
+// configuration and execution options.
+func renderRandom(t *testing.T, c ParserConfig, opts ...ExecuteOption) string {
+	c.Functions = []string{FuncSetRandom}
+	p, err := NewParser(c)
+	if err != nil {
+		t.Fatalf("unable to create parser: %s", err)
+	}
+
+	tmpl, err := p.Parse("t", `{{ uuid }} {{ randInt 0 1000000 }} {{ randAlphaNum 8 }}`)
+	if err != nil {
+		t.Fatalf("unable to parse template: %s", err)
+	}
+
+	var o strings.Builder
+	if err := ExecuteWith(context.Background(), tmpl, &o, nil, opts...); err != nil {
+		t.Fatalf("unable to execute template: %s", err)
+	}
+
+	return o.String()
+}
+
+func TestRandomReproducible(t *testing.T) {
+	testCases := []struct {
+		name   string
+		first  func(*testing.T) string
+		second func(*testing.T) string
+		same   bool
+	}{
+		{
+			name:   "SameSeed",
+			first:  func(t *testing.T) string { return renderRandom(t, ParserConfig{Entropy: SeededEntropy(42)}) },
+			second: func(t *testing.T) string { return renderRandom(t, ParserConfig{Entropy: SeededEntropy(42)}) },
+			same:   true,
+		},
+		{
+			name:   "DifferentSeeds",
+			first:  func(t *testing.T) string { return renderRandom(t, ParserConfig{Entropy: SeededEntropy(42)}) },
+			second: func(t *testing.T) string { return renderRandom(t, ParserConfig{Entropy: SeededEntropy(43)}) },
+		},
+		{
+			name:   "SystemEntropy",
+			first:  func(t *testing.T) string { return renderRandom(t, ParserConfig{}) },
+			second: func(t *testing.T) string { return renderRandom(t, ParserConfig{}) },
+		},
+		{
+			name:  "WithEntropy",
+			first: func(t *testing.T) string { return renderRandom(t, ParserConfig{Entropy: SeededEntropy(42)}) },
+			second: func(t *testing.T) string {
+				return renderRandom(t, ParserConfig{Entropy: SeededEntropy(1)}, WithEntropy(SeededEntropy(42)))
+			},
+			same: true,
+		},
+		{
+			name: "WithEntropyLabels",
+			first: func(t *testing.T) string {
+				return renderRandom(t, ParserConfig{}, WithEntropy(SeededEntropy(42, "t", "a")))
+			},
+			second: func(t *testing.T) string {
+				return renderRandom(t, ParserConfig{}, WithEntropy(SeededEntropy(42, "t", "b")))
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			first, second := testCase.first(t), testCase.second(t)
+			if (first == second) != testCase.same {
+				t.Errorf("expected the output to be the same: %t, got %q and %q", testCase.same, first, second)
+			}
+		})
+	}
+}
+
+func TestRandomWithEntropyIsPerExecution(t *testing.T) {
+	p, err := NewParser(ParserConfig{Functions: []string{FuncSetRandom}, Entropy: SeededEntropy(1)})
+	if err != nil {
+		t.Fatalf("unable to create parser: %s", err)
+	}
+
+	tmpl, err := p.Parse("t", `{{ randHex 8 }}`)
+	if err != nil {
+		t.Fatalf("unable to parse template: %s", err)
+	}
+
+	var first, overridden, second strings.Builder
+	if err := tmpl.Execute(&first, nil); err != nil {
+		t.Fatalf("unable to execute template: %s", err)
+	}
+
+	if err := ExecuteWith(context.Background(), tmpl, &overridden, nil, WithEntropy(SeededEntropy(2))); err != nil {
+		t.Fatalf("unable to execute template: %s", err)
+	}
+
+	if err := tmpl.Execute(&second, nil); err != nil {
+		t.Fatalf("unable to execute template: %s", err)
+	}
+
+	// the configured entropy continues its sequence, unaffected by the override
+	expected := renderSequence(t, SeededEntropy(1))
+	if first.String()+second.String() != expected {
+		t.Errorf("expected %s, got %s%s", expected, first.String(), second.String())
+	}
+
+	if overridden.String() != renderSequence(t, SeededEntropy(2))[:16] {
+		t.Errorf("the override should supply the random values, got %s", overridden.String())
+	}
+
+	// an override for a function set still takes precedence over the rebuilt set
+	var o strings.Builder
+	err = ExecuteWith(context.Background(), tmpl, &o, nil, WithEntropy(SeededEntropy(2)), WithFunc("randHex", func(int) string { return "fixed" }))
+	if err != nil || o.String() != "fixed" {
+		t.Errorf("expected the function override, got %q (%v)", o.String(), err)
+	}
+}
+
+// renderSequence returns the hex encoding of two consecutive 8-byte reads.
+func renderSequence(t *testing.T, r io.Reader) string {
+	rnd := random{r: r}
+	first, err := rnd.randHex(8)
+	if err == nil {
+		var second string
+		second, err = rnd.randHex(8)
+		first += second
+	}
+
+	if err != nil {
+		t.Fatalf("unable to read entropy: %s", err)
+	}
+
+	return first
+}
+
+func TestRandomEntropyError(t *testing.T) {
+	rnd := random{r: iotest.ErrReader(errors.New("expected"))}
+	for name, f := range map[string]func() error{
+		"uuid":         func() error { _, err := rnd.uuid(); return err },
+		"randInt":      func() error { _, err := rnd.randInt(0, 10); return err },
+		"randFloat":    func() error { _, err := rnd.randFloat(); return err },
+		"randAlphaNum": func() error { _, err := rnd.randAlphaNum(3); return err },
+		"randHex":      func() error { _, err := rnd.randHex(3); return err },
+		"randChoice":   func() error { _, err := rnd.randChoice([]int{1, 2}); return err },
+		"shuffle":      func() error { _, err := rnd.shuffle([]int{1, 2}); return err },
+	} {
+		if err := f(); err == nil || err.Error() != "expected" {
+			t.Errorf("%s: expected the entropy's error, got %v", name, err)
+		}
+	}
+}
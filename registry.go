@@ -0,0 +1,148 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"sort"
+)
+
+// TemplateError associates an error with the name of the template file
+// that produced it.
+type TemplateError struct {
+	// Name is the relative path of the template within its file system.
+	Name string
+
+	// Err is the error that occurred when reading or parsing the template.
+	Err error
+}
+
+// Error satisfies the error interface.
+func (te *TemplateError) Error() string {
+	return fmt.Sprintf("template [%s]: %s", te.Name, te.Err)
+}
+
+// Unwrap returns the underlying error.
+func (te *TemplateError) Unwrap() error {
+	return te.Err
+}
+
+// MissingTemplateError indicates that a Registry has no template with a given name.
+type MissingTemplateError struct {
+	Name string
+}
+
+// Error satisfies the error interface.
+func (mte *MissingTemplateError) Error() string {
+	return fmt.Sprintf("no such template [%s]", mte.Name)
+}
+
+// Registry is an immutable set of templates loaded from a file system.  A Registry
+// is safe for concurrent use.
+type Registry struct {
+	names     []string
+	templates map[string]Template
+}
+
+// NewRegistry walks a file system, such as an embed.FS or os.DirFS, and parses each
+// file that the Selector chooses a parser for.  Each template is named by its
+// slash-separated path relative to the root of fsys.
+//
+// The returned Registry is never nil.  It contains every template that parsed
+// successfully.  If any file could not be read or parsed, the returned error is
+// the errors.Join of a *TemplateError for each such file.
+func NewRegistry(fsys fs.FS, s Selector) (*Registry, error) {
+	var (
+		r = &Registry{
+			templates: make(map[string]Template),
+		}
+
+		errs []error
+	)
+
+	walkErr := fs.WalkDir(fsys, ".", func(path string, entry fs.DirEntry, err error) error {
+		switch {
+		case err != nil:
+			errs = append(errs, &TemplateError{Name: path, Err: err})
+			return nil
+
+		case entry.IsDir():
+			return nil
+		}
+
+		p, found := s.Select(path)
+		if !found {
+			return nil
+		}
+
+		var (
+			t       Template
+			content []byte
+		)
+
+		content, err = fs.ReadFile(fsys, path)
+		if err == nil {
+			t, err = p.Parse(path, string(content))
+		}
+
+		if err == nil {
+			r.names = append(r.names, path)
+			r.templates[path] = t
+		} else {
+			errs = append(errs, &TemplateError{Name: path, Err: err})
+		}
+
+		return nil
+	})
+
+	if walkErr != nil {
+		errs = append(errs, walkErr)
+	}
+
+	sort.Strings(r.names)
+	return r, errors.Join(errs...)
+}
+
+// Len returns the number of templates in this Registry.
+func (r *Registry) Len() int {
+	return len(r.names)
+}
+
+// Names returns the sorted names of the templates in this Registry.  The
+// returned slice is a copy and may be freely modified.
+func (r *Registry) Names() []string {
+	return append([]string(nil), r.names...)
+}
+
+// Lookup returns the template with the given name.
+func (r *Registry) Lookup(name string) (t Template, found bool) {
+	t, found = r.templates[name]
+	return
+}
+
+// Execute renders the named template.  If no such template exists, a
+// *MissingTemplateError is returned.
+func (r *Registry) Execute(name string, output io.Writer, data interface{}) error {
+	t, found := r.Lookup(name)
+	if !found {
+		return &MissingTemplateError{Name: name}
+	}
+
+	return t.Execute(output, data)
+}
+
+// ExecuteWith renders the named template with per-execution options.  If no such
+// template exists, a *MissingTemplateError is returned.  See ExecuteWith.
+func (r *Registry) ExecuteWith(ctx context.Context, name string, output io.Writer, data interface{}, opts ...ExecuteOption) error {
+	t, found := r.Lookup(name)
+	if !found {
+		return &MissingTemplateError{Name: name}
+	}
+
+	return ExecuteWith(ctx, t, output, data, opts...)
+}
@@ -0,0 +1,166 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// newTestSelector creates a Selector that parses every .tmpl file with the default settings.
+func newTestSelector(t *testing.T) Selector {
+	s, err := NewSelector(SelectorConfig{
+		Patterns: []string{"*.tmpl", "*/*.tmpl"},
+	})
+
+	if err != nil {
+		t.Fatalf("unable to create selector: %s", err)
+	}
+
+	return s
+}
+
+func TestNewRegistry(t *testing.T) {
+	testCases := []struct {
+		name     string
+		fsys     fstest.MapFS
+		expected []string
+		errors   []string
+	}{
+		{
+			name:     "Empty",
+			fsys:     fstest.MapFS{},
+			expected: []string{},
+		},
+		{
+			name: "Valid",
+			fsys: fstest.MapFS{
+				"b.tmpl":     {Data: []byte("b")},
+				"a.tmpl":     {Data: []byte("a")},
+				"dir/c.tmpl": {Data: []byte("c")},
+				"ignored.md": {Data: []byte("ignored")},
+			},
+			expected: []string{"a.tmpl", "b.tmpl", "dir/c.tmpl"},
+		},
+		{
+			name: "Invalid",
+			fsys: fstest.MapFS{
+				"a.tmpl":   {Data: []byte("a")},
+				"bad.tmpl": {Data: []byte("{{.unclosed")},
+				"end.tmpl": {Data: []byte("{{end}}")},
+			},
+			expected: []string{"a.tmpl"},
+			errors:   []string{"bad.tmpl", "end.tmpl"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r, err := NewRegistry(testCase.fsys, newTestSelector(t))
+			if r == nil {
+				t.Fatal("the registry should never be nil")
+			}
+
+			if r.Len() != len(testCase.expected) || !reflect.DeepEqual(testCase.expected, append([]string{}, r.Names()...)) {
+				t.Errorf("expected templates %q, got %q", testCase.expected, r.Names())
+			}
+
+			for _, name := range testCase.errors {
+				var te *TemplateError
+				if !errors.As(err, &te) {
+					t.Fatalf("expected a TemplateError, got %v", err)
+				}
+
+				if !strings.Contains(err.Error(), "template ["+name+"]") {
+					t.Errorf("expected an error for %s, got %s", name, err)
+				}
+			}
+
+			if len(testCase.errors) == 0 && err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
+func TestRegistryExecute(t *testing.T) {
+	r, err := NewRegistry(fstest.MapFS{
+		"hello.tmpl": {Data: []byte("hello {{.name}}")},
+	}, newTestSelector(t))
+
+	if err != nil {
+		t.Fatalf("unable to create registry: %s", err)
+	}
+
+	names := r.Names()
+	names[0] = "modified"
+	if r.Names()[0] != "hello.tmpl" {
+		t.Error("Names should return a copy")
+	}
+
+	testCases := []struct {
+		name     string
+		execute  func(string, *bytes.Buffer) error
+		template string
+		expected string
+		missing  bool
+	}{
+		{
+			name: "Execute",
+			execute: func(name string, o *bytes.Buffer) error {
+				return r.Execute(name, o, Model{"name": "world"})
+			},
+			template: "hello.tmpl",
+			expected: "hello world",
+		},
+		{
+			name: "ExecuteWith",
+			execute: func(name string, o *bytes.Buffer) error {
+				return r.ExecuteWith(context.Background(), name, o, Model{"name": "world"})
+			},
+			template: "hello.tmpl",
+			expected: "hello world",
+		},
+		{
+			name: "ExecuteMissing",
+			execute: func(name string, o *bytes.Buffer) error {
+				return r.Execute(name, o, nil)
+			},
+			template: "missing.tmpl",
+			missing:  true,
+		},
+		{
+			name: "ExecuteWithMissing",
+			execute: func(name string, o *bytes.Buffer) error {
+				return r.ExecuteWith(context.Background(), name, o, nil)
+			},
+			template: "missing.tmpl",
+			missing:  true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var o bytes.Buffer
+			err := testCase.execute(testCase.template, &o)
+			if testCase.missing {
+				var mte *MissingTemplateError
+				if !errors.As(err, &mte) || mte.Name != testCase.template {
+					t.Errorf("expected a MissingTemplateError, got %v", err)
+				}
+
+				return
+			}
+
+			if err != nil || o.String() != testCase.expected {
+				t.Errorf("expected %q, got %q (%v)", testCase.expected, o.String(), err)
+			}
+		})
+	}
+}
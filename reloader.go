@@ -0,0 +1,275 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"context"
+	"hash/fnv"
+	"io"
+	"io/fs"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultReloadInterval is the polling interval used when a ReloaderConfig
+	// does not specify one.
+	DefaultReloadInterval = 5 * time.Second
+
+	// DefaultReloadEvents is the size of a Reloader's event channel when a
+	// ReloaderConfig does not specify one.
+	DefaultReloadEvents = 10
+)
+
+// ReloadEvent describes the outcome of a single reload.
+type ReloadEvent struct {
+	// Registry is the template set that is current after the reload.  If the reload
+	// failed, this is the previous Registry, which remains in use.
+	Registry *Registry
+
+	// Err is the error that caused the reload to be rejected.  If nil, the reload
+	// succeeded and Registry is the new template set.
+	Err error
+
+	// Time is when the reload finished.
+	Time time.Time
+}
+
+// ReloaderConfig holds the options for creating a Reloader.
+type ReloaderConfig struct {
+	// FS is the file system templates are loaded from.  This field is required.
+	FS fs.FS
+
+	// Selector chooses the parser for each file.  Either this field or Configs is required.
+	// A Selector's parsers read their includes and layouts only once, so changes to those
+	// files are not seen by reloads unless Configs is used instead.
+	Selector Selector
+
+	// Configs are the configurations that a new Selector is created from for each load,
+	// so that changes to includes and layouts take effect.  If set, Selector is ignored.
+	Configs []SelectorConfig
+
+	// Interval is how often FS is polled for changes.  If unset, DefaultReloadInterval is used.
+	Interval time.Duration
+
+	// Events is the buffer size of the event channel.  If unset, DefaultReloadEvents is used.
+	Events int
+}
+
+// Reloader is a Registry that is periodically reloaded from a file system.  A new template
+// set replaces the current one only if every template in it parses.  Otherwise, the current
+// template set remains in use.
+//
+// Callers that need a consistent view across several operations should use Current to
+// obtain a snapshot.  A snapshot is never modified by subsequent reloads.
+type Reloader struct {
+	fsys     fs.FS
+	selector func() (Selector, error)
+	interval time.Duration
+
+	current     atomic.Pointer[Registry]
+	fingerprint uint64
+	reloadLock  sync.Mutex
+	stopped     bool
+
+	events   chan ReloadEvent
+	stop     chan struct{}
+	done     chan struct{}
+	startRun sync.Once
+	stopRun  sync.Once
+}
+
+// NewReloader creates a Reloader and performs the initial load.  If any template
+// fails to load, this function returns the error from NewRegistry.  Polling does
+// not begin until Start is called.
+func NewReloader(c ReloaderConfig) (*Reloader, error) {
+	r := &Reloader{
+		fsys:     c.FS,
+		interval: c.Interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	if c.Configs != nil {
+		r.selector = func() (Selector, error) {
+			return NewSelector(c.Configs...)
+		}
+	} else {
+		r.selector = func() (Selector, error) {
+			return c.Selector, nil
+		}
+	}
+
+	if r.interval <= 0 {
+		r.interval = DefaultReloadInterval
+	}
+
+	events := c.Events
+	if events <= 0 {
+		events = DefaultReloadEvents
+	}
+
+	r.events = make(chan ReloadEvent, events)
+
+	fingerprint, err := r.scan()
+	if err != nil {
+		return nil, err
+	}
+
+	registry, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+
+	r.fingerprint = fingerprint
+	r.current.Store(registry)
+	return r, nil
+}
+
+// load creates a Registry from the file system.
+func (r *Reloader) load() (*Registry, error) {
+	selector, err := r.selector()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewRegistry(r.fsys, selector)
+}
+
+// scan computes a fingerprint of the names, sizes, and modification times
+// of every file in the file system.
+func (r *Reloader) scan() (uint64, error) {
+	h := fnv.New64a()
+	err := fs.WalkDir(r.fsys, ".", func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		fi, err := entry.Info()
+		if err == nil {
+			io.WriteString(h, path)
+			io.WriteString(h, strconv.FormatInt(fi.Size(), 16))
+			io.WriteString(h, strconv.FormatInt(fi.ModTime().UnixNano(), 16))
+		}
+
+		return err
+	})
+
+	return h.Sum64(), err
+}
+
+// Current returns the current template set.  The returned Registry is immutable
+// and unaffected by later reloads.
+func (r *Reloader) Current() *Registry {
+	return r.current.Load()
+}
+
+// Names returns the names of the templates in the current template set.
+func (r *Reloader) Names() []string {
+	return r.Current().Names()
+}
+
+// Lookup returns the template with the given name from the current template set.
+func (r *Reloader) Lookup(name string) (Template, bool) {
+	return r.Current().Lookup(name)
+}
+
+// Execute renders the named template from the current template set.
+func (r *Reloader) Execute(name string, output io.Writer, data interface{}) error {
+	return r.Current().Execute(name, output, data)
+}
+
+// ExecuteWith renders the named template from the current template set with
+// per-execution options.
+func (r *Reloader) ExecuteWith(ctx context.Context, name string, output io.Writer, data interface{}, opts ...ExecuteOption) error {
+	return r.Current().ExecuteWith(ctx, name, output, data, opts...)
+}
+
+// Events returns the channel on which reload events are delivered.  An event is sent
+// each time a change is detected, whether or not the reload succeeds.  Events are
+// dropped if the channel is full.  The channel is closed after Stop is called and
+// polling has ended.
+func (r *Reloader) Events() <-chan ReloadEvent {
+	return r.events
+}
+
+// Reload checks the file system immediately and reloads the template set if anything
+// has changed.  The returned error is nil if nothing changed or the reload succeeded.
+func (r *Reloader) Reload() error {
+	r.reloadLock.Lock()
+	defer r.reloadLock.Unlock()
+	if r.stopped {
+		return nil
+	}
+
+	fingerprint, err := r.scan()
+	if err == nil && fingerprint == r.fingerprint {
+		return nil
+	}
+
+	var registry *Registry
+	if err == nil {
+		// remember this fingerprint even if parsing fails, so that the same
+		// bad template set isn't parsed and reported over and over
+		r.fingerprint = fingerprint
+		registry, err = r.load()
+	}
+
+	if err == nil {
+		r.current.Store(registry)
+	}
+
+	select {
+	case r.events <- ReloadEvent{Registry: r.Current(), Err: err, Time: time.Now()}:
+	default:
+		// drop the event rather than block reloading
+	}
+
+	return err
+}
+
+// Start begins polling the file system in a background goroutine.  This method
+// is idempotent.
+func (r *Reloader) Start() {
+	r.startRun.Do(func() {
+		go r.run()
+	})
+}
+
+// Stop ends polling and waits for the background goroutine to exit.  This method
+// is idempotent.  A Reloader cannot be restarted after it is stopped, and calls
+// to Reload after Stop do nothing.
+func (r *Reloader) Stop() {
+	r.stopRun.Do(func() {
+		close(r.stop)
+		started := true
+		r.startRun.Do(func() { started = false })
+		if started {
+			<-r.done
+		}
+
+		r.reloadLock.Lock()
+		r.stopped = true
+		close(r.events)
+		r.reloadLock.Unlock()
+	})
+}
+
+func (r *Reloader) run() {
+	defer close(r.done)
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+
+		case <-ticker.C:
+			r.Reload()
+		}
+	}
+}
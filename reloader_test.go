@@ -0,0 +1,234 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// assertRender checks the output of a Reloader's current template.
+func assertRender(t *testing.T, r *Reloader, name, expected string) {
+	t.Helper()
+	var o bytes.Buffer
+	if err := r.Execute(name, &o, Model{}); err != nil || o.String() != expected {
+		t.Errorf("expected %s to render %q, got %q (%v)", name, expected, o.String(), err)
+	}
+}
+
+// nextEvent returns the event sent by a reload, failing if there is none.
+func nextEvent(t *testing.T, r *Reloader) ReloadEvent {
+	t.Helper()
+	select {
+	case e := <-r.Events():
+		return e
+
+	default:
+		t.Fatal("expected a reload event")
+		return ReloadEvent{}
+	}
+}
+
+func TestNewReloader(t *testing.T) {
+	testCases := []struct {
+		name string
+		c    ReloaderConfig
+		err  bool
+	}{
+		{
+			name: "Selector",
+			c: ReloaderConfig{
+				FS:       fstest.MapFS{"a.tmpl": {Data: []byte("a")}},
+				Selector: newTestSelector(t),
+			},
+		},
+		{
+			name: "Configs",
+			c: ReloaderConfig{
+				FS:      fstest.MapFS{"a.tmpl": {Data: []byte("a")}},
+				Configs: []SelectorConfig{{Patterns: []string{"*.tmpl"}}},
+			},
+		},
+		{
+			name: "InvalidTemplate",
+			c: ReloaderConfig{
+				FS:       fstest.MapFS{"a.tmpl": {Data: []byte("{{.a")}},
+				Selector: newTestSelector(t),
+			},
+			err: true,
+		},
+		{
+			name: "InvalidConfigs",
+			c: ReloaderConfig{
+				FS:      fstest.MapFS{"a.tmpl": {Data: []byte("a")}},
+				Configs: []SelectorConfig{{Patterns: []string{"[a"}}},
+			},
+			err: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r, err := NewReloader(testCase.c)
+			if testCase.err {
+				if err == nil || r != nil {
+					t.Errorf("expected an error, got %v", err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			defer r.Stop()
+			assertRender(t, r, "a.tmpl", "a")
+		})
+	}
+}
+
+func TestReloaderReload(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.tmpl": {Data: []byte("a")},
+	}
+
+	r, err := NewReloader(ReloaderConfig{FS: fsys, Selector: newTestSelector(t)})
+	if err != nil {
+		t.Fatalf("unable to create reloader: %s", err)
+	}
+
+	defer r.Stop()
+	snapshot := r.Current()
+
+	// nothing has changed
+	if err := r.Reload(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	if len(r.Events()) != 0 || r.Current() != snapshot {
+		t.Error("a reload without changes should not replace the templates")
+	}
+
+	// a valid change
+	fsys["a.tmpl"] = &fstest.MapFile{Data: []byte("changed")}
+	fsys["b.tmpl"] = &fstest.MapFile{Data: []byte("b")}
+	if err := r.Reload(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	if e := nextEvent(t, r); e.Err != nil || e.Registry != r.Current() || e.Registry.Len() != 2 {
+		t.Errorf("unexpected event %+v", e)
+	}
+
+	assertRender(t, r, "a.tmpl", "changed")
+	assertRender(t, r, "b.tmpl", "b")
+
+	var o bytes.Buffer
+	if err := snapshot.Execute("a.tmpl", &o, nil); err != nil || o.String() != "a" {
+		t.Errorf("a snapshot should not change, got %q (%v)", o.String(), err)
+	}
+
+	// an invalid change keeps the current templates
+	current := r.Current()
+	fsys["b.tmpl"] = &fstest.MapFile{Data: []byte("{{.b")}
+	if err := r.Reload(); err == nil {
+		t.Error("expected the reload to be rejected")
+	}
+
+	if e := nextEvent(t, r); e.Err == nil || e.Registry != current {
+		t.Errorf("unexpected event %+v", e)
+	}
+
+	assertRender(t, r, "b.tmpl", "b")
+
+	// the same invalid templates are not reported again
+	if err := r.Reload(); err != nil || len(r.Events()) != 0 {
+		t.Errorf("an unchanged, rejected template set should not be reloaded: %v", err)
+	}
+}
+
+func TestReloaderIncludes(t *testing.T) {
+	fsys := fstest.MapFS{
+		"page.tmpl":        {Data: []byte(`{{template "greeting"}}`)},
+		"includes/a.inc":   {Data: []byte(`{{define "greeting"}}hello{{end}}`)},
+		"includes/ignored": {Data: []byte(`not an include`)},
+	}
+
+	r, err := NewReloader(ReloaderConfig{
+		FS: fsys,
+		Configs: []SelectorConfig{
+			{
+				Patterns: []string{"*.tmpl"},
+				Parser: ParserConfig{
+					Includes: []string{"includes/*.inc"},
+					FS:       fsys,
+				},
+			},
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("unable to create reloader: %s", err)
+	}
+
+	defer r.Stop()
+	assertRender(t, r, "page.tmpl", "hello")
+
+	// changing only an include rebuilds the parser
+	fsys["includes/a.inc"] = &fstest.MapFile{Data: []byte(`{{define "greeting"}}goodbye{{end}}`)}
+	if err := r.Reload(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	assertRender(t, r, "page.tmpl", "goodbye")
+}
+
+func TestReloaderStartStop(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.tmpl": {Data: []byte("a")},
+	}
+
+	r, err := NewReloader(ReloaderConfig{
+		FS:       fsys,
+		Selector: newTestSelector(t),
+		Interval: time.Millisecond,
+	})
+
+	if err != nil {
+		t.Fatalf("unable to create reloader: %s", err)
+	}
+
+	r.Start()
+	r.Start()
+	time.Sleep(5 * time.Millisecond)
+	r.Stop()
+	r.Stop()
+
+	for e := range r.Events() {
+		t.Errorf("unexpected event %+v", e)
+	}
+
+	if err := r.Reload(); err != nil {
+		t.Errorf("reloading after Stop should do nothing: %v", err)
+	}
+
+	// a reloader that was never started can be stopped
+	unstarted, err := NewReloader(ReloaderConfig{FS: fsys, Selector: newTestSelector(t)})
+	if err != nil {
+		t.Fatalf("unable to create reloader: %s", err)
+	}
+
+	unstarted.Stop()
+	if _, open := <-unstarted.Events(); open {
+		t.Error("the event channel should be closed")
+	}
+
+	if !strings.Contains(strings.Join(unstarted.Names(), ","), "a.tmpl") {
+		t.Errorf("unexpected names %q", unstarted.Names())
+	}
+}
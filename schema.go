@@ -0,0 +1,252 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"gopkg.in/yaml.v3"
+)
+
+// schemaScheme is the URL scheme used to identify schema documents within
+// the file system a Schema is compiled from.
+const schemaScheme = "file"
+
+// SchemaViolation is a single way in which a value failed to conform to a JSON Schema.
+type SchemaViolation struct {
+	// Pointer is the JSON pointer, per RFC 6901, of the offending value.  The
+	// empty string refers to the entire value.
+	Pointer string
+
+	// Message describes the violation.
+	Message string
+}
+
+// String formats this violation as a URI fragment followed by the message.
+func (sv SchemaViolation) String() string {
+	return "#" + sv.Pointer + ": " + sv.Message
+}
+
+// SchemaError indicates that a value did not conform to a JSON Schema.
+type SchemaError struct {
+	// Schema is the name of the schema that was violated.
+	Schema string
+
+	// Violations are the individual problems with the value.  There is always
+	// at least one violation.
+	Violations []SchemaViolation
+}
+
+// Error satisfies the error interface.  Each violation appears on its own line.
+func (se *SchemaError) Error() string {
+	var o strings.Builder
+	fmt.Fprintf(&o, "violates schema %s:", se.Schema)
+	if len(se.Violations) == 1 {
+		o.WriteString(" ")
+		o.WriteString(se.Violations[0].String())
+		return o.String()
+	}
+
+	for _, v := range se.Violations {
+		o.WriteString("\n")
+		o.WriteString(v.String())
+	}
+
+	return o.String()
+}
+
+// UnsupportedSchemaMediaTypeError indicates that output could not be checked against
+// a schema because its media type is neither JSON nor YAML.
+type UnsupportedSchemaMediaTypeError struct {
+	MediaType string
+}
+
+// Error satisfies the error interface.
+func (usmte *UnsupportedSchemaMediaTypeError) Error() string {
+	return fmt.Sprintf("cannot validate %s output against a JSON schema", usmte.MediaType)
+}
+
+// Schema is a compiled JSON Schema.
+type Schema struct {
+	name   string
+	schema *jsonschema.Schema
+}
+
+// Name returns the name this schema was compiled from.
+func (s *Schema) Name() string {
+	return s.name
+}
+
+// schemaLoader loads schema documents from a file system.  Both JSON and YAML
+// documents are supported.
+type schemaLoader struct {
+	fsys fs.FS
+}
+
+func (sl schemaLoader) Load(url string) (interface{}, error) {
+	name := strings.TrimPrefix(url, schemaScheme+":///")
+	data, err := fs.ReadFile(sl.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if format, _ := FormatOf(name); format == FormatYAML {
+		return decodeSchemaYAML(data)
+	}
+
+	return jsonschema.UnmarshalJSON(bytes.NewReader(data))
+}
+
+// CompileSchema compiles the JSON Schema with the given name within a file system.
+// Schemas may be written in JSON or YAML, and references to other schemas are
+// resolved relative to the same file system.
+func CompileSchema(fsys fs.FS, name string) (*Schema, error) {
+	c := jsonschema.NewCompiler()
+	c.UseLoader(jsonschema.SchemeURLLoader{
+		schemaScheme: schemaLoader{fsys: fsys},
+	})
+
+	s, err := c.Compile(schemaScheme + ":///" + name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compile schema %s: %w", name, err)
+	}
+
+	return &Schema{
+		name:   name,
+		schema: s,
+	}, nil
+}
+
+// Validate checks that a value, such as a Model, conforms to this schema.  Any
+// failure is returned as a *SchemaError.
+func (s *Schema) Validate(v interface{}) error {
+	// round trip the value through JSON, so that the validator
+	// only ever sees JSON types
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	return s.validate(instance)
+}
+
+// ValidateOutput checks that rendered output conforms to this schema.  The media type
+// must be a JSON or YAML type, including types with a +json or +yaml suffix.  Each
+// document in a YAML stream must conform to the schema.
+func (s *Schema) ValidateOutput(mediaType string, output []byte) error {
+	switch structuredFormat(mediaType) {
+	case FormatJSON:
+		instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(output))
+		if err != nil {
+			return err
+		}
+
+		return s.validate(instance)
+
+	case FormatYAML:
+		d := yaml.NewDecoder(bytes.NewReader(output))
+		for {
+			var v interface{}
+			err := d.Decode(&v)
+			if errors.Is(err, io.EOF) {
+				return nil
+			} else if err != nil {
+				return err
+			}
+
+			if err := s.Validate(v); err != nil {
+				return err
+			}
+		}
+
+	default:
+		return &UnsupportedSchemaMediaTypeError{MediaType: baseMediaType(mediaType)}
+	}
+}
+
+// validate checks a value that has already been converted to JSON types.
+func (s *Schema) validate(instance interface{}) error {
+	err := s.schema.Validate(instance)
+	if ve, ok := err.(*jsonschema.ValidationError); ok {
+		se := &SchemaError{Schema: s.name}
+		se.Violations = appendViolations(se.Violations, ve)
+		return se
+	}
+
+	return err
+}
+
+// appendViolations flattens a tree of validation errors into its leaves, which
+// are the errors that describe actual problems with the instance.  The output of
+// a leaf is just its own message.
+func appendViolations(vs []SchemaViolation, ve *jsonschema.ValidationError) []SchemaViolation {
+	if len(ve.Causes) == 0 {
+		return append(vs, SchemaViolation{
+			Pointer: jsonPointer(ve.InstanceLocation),
+			Message: fmt.Sprint(ve.BasicOutput().Error),
+		})
+	}
+
+	for _, cause := range ve.Causes {
+		vs = appendViolations(vs, cause)
+	}
+
+	return vs
+}
+
+// jsonPointer builds an RFC 6901 pointer from its reference tokens.
+func jsonPointer(tokens []string) string {
+	var o strings.Builder
+	for _, t := range tokens {
+		o.WriteByte('/')
+		t = strings.ReplaceAll(t, "~", "~0")
+		o.WriteString(strings.ReplaceAll(t, "/", "~1"))
+	}
+
+	return o.String()
+}
+
+// structuredFormat returns FormatJSON or FormatYAML if a media type is one
+// that a schema can validate.  Otherwise, this function returns the empty string.
+func structuredFormat(mediaType string) string {
+	mediaType = baseMediaType(mediaType)
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return FormatJSON
+
+	case mediaType == "application/yaml" || mediaType == "application/x-yaml" ||
+		mediaType == "text/yaml" || strings.HasSuffix(mediaType, "+yaml"):
+		return FormatYAML
+
+	default:
+		return ""
+	}
+}
+
+// decodeSchemaYAML decodes a YAML schema document into JSON types.
+func decodeSchemaYAML(data []byte) (interface{}, error) {
+	var v interface{}
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return jsonschema.UnmarshalJSON(bytes.NewReader(data))
+}
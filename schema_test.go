@@ -0,0 +1,236 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// testSchemas are the schemas used by these tests.  The YAML schema refers
+// to the JSON schema.
+var testSchemas = fstest.MapFS{
+	"device.json": {Data: []byte(`{
+		"type": "object",
+		"required": ["id"],
+		"properties": {
+			"id": {"type": "string", "minLength": 3},
+			"tags": {"type": "array", "items": {"type": "string"}},
+			"a/b": {"type": "integer"}
+		}
+	}`)},
+	"wrapper.yaml": {Data: []byte("type: object\nrequired: [device]\nproperties:\n  device:\n    $ref: device.json\n")},
+	"invalid.json": {Data: []byte(`{"type": 12}`)},
+	"broken.yaml":  {Data: []byte("type: [")},
+}
+
+func TestCompileSchema(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  bool
+	}{
+		{name: "device.json"},
+		{name: "wrapper.yaml"},
+		{name: "invalid.json", err: true},
+		{name: "broken.yaml", err: true},
+		{name: "missing.json", err: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			s, err := CompileSchema(testSchemas, testCase.name)
+			switch {
+			case testCase.err && err == nil:
+				t.Error("expected an error")
+
+			case !testCase.err && err != nil:
+				t.Errorf("unexpected error: %s", err)
+
+			case !testCase.err && s.Name() != testCase.name:
+				t.Errorf("expected the name %s, got %s", testCase.name, s.Name())
+			}
+		})
+	}
+}
+
+func TestSchemaValidate(t *testing.T) {
+	testCases := []struct {
+		name       string
+		schema     string
+		value      interface{}
+		violations []string
+	}{
+		{
+			name:   "Valid",
+			schema: "device.json",
+			value:  Model{"id": "abc", "tags": []interface{}{"x"}, "a/b": 1},
+		},
+		{
+			name:   "ValidInt64",
+			schema: "device.json",
+			value:  Model{"id": "abc", "a/b": int64(1)},
+		},
+		{
+			name:       "Missing",
+			schema:     "device.json",
+			value:      Model{},
+			violations: []string{"#: missing property 'id'"},
+		},
+		{
+			name:   "Several",
+			schema: "device.json",
+			value:  Model{"id": "a", "tags": []interface{}{"x", 1}, "a/b": "x"},
+			violations: []string{
+				"#/a~1b: got string, want integer",
+				"#/id: minLength: got 1, want 3",
+				"#/tags/1: got number, want string",
+			},
+		},
+		{
+			name:       "Reference",
+			schema:     "wrapper.yaml",
+			value:      Model{"device": map[string]interface{}{"id": 1}},
+			violations: []string{"#/device/id: got number, want string"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			s, err := CompileSchema(testSchemas, testCase.schema)
+			if err != nil {
+				t.Fatalf("unable to compile schema: %s", err)
+			}
+
+			assertViolations(t, s.Validate(testCase.value), testCase.schema, testCase.violations)
+		})
+	}
+}
+
+func TestSchemaValidateOutput(t *testing.T) {
+	testCases := []struct {
+		name       string
+		mediaType  string
+		output     string
+		violations []string
+		err        string
+	}{
+		{
+			name:      "JSON",
+			mediaType: "application/json",
+			output:    `{"id": "abc"}`,
+		},
+		{
+			name:       "JSONViolation",
+			mediaType:  "application/problem+json; charset=utf-8",
+			output:     `{"id": 1}`,
+			violations: []string{"#/id: got number, want string"},
+		},
+		{
+			name:      "InvalidJSON",
+			mediaType: "application/json",
+			output:    `{`,
+			err:       "unexpected EOF",
+		},
+		{
+			name:      "YAMLStream",
+			mediaType: "application/yaml",
+			output:    "id: abc\n---\nid: def\n",
+		},
+		{
+			name:       "YAMLViolation",
+			mediaType:  "text/yaml",
+			output:     "id: abc\n---\nid: [x]\n",
+			violations: []string{"#/id: got array, want string"},
+		},
+		{
+			name:      "InvalidYAML",
+			mediaType: "application/x-yaml",
+			output:    "id: [",
+			err:       "yaml",
+		},
+		{
+			name:      "Unsupported",
+			mediaType: "text/HTML; charset=utf-8",
+			output:    "<p></p>",
+			err:       "cannot validate text/html output against a JSON schema",
+		},
+	}
+
+	s, err := CompileSchema(testSchemas, "device.json")
+	if err != nil {
+		t.Fatalf("unable to compile schema: %s", err)
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := s.ValidateOutput(testCase.mediaType, []byte(testCase.output))
+			if len(testCase.err) > 0 {
+				if err == nil || !strings.Contains(err.Error(), testCase.err) {
+					t.Errorf("expected an error containing %q, got %v", testCase.err, err)
+				}
+
+				return
+			}
+
+			assertViolations(t, err, "device.json", testCase.violations)
+		})
+	}
+}
+
+// assertViolations checks that err is a SchemaError with the given violations, in any order.
+// No violations means that err must be nil.
+func assertViolations(t *testing.T, err error, schema string, violations []string) {
+	t.Helper()
+	if len(violations) == 0 {
+		if err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+
+		return
+	}
+
+	var se *SchemaError
+	if !errors.As(err, &se) || se.Schema != schema {
+		t.Fatalf("expected a SchemaError for %s, got %v", schema, err)
+	}
+
+	actual := make(map[string]bool, len(se.Violations))
+	for _, v := range se.Violations {
+		actual[v.String()] = true
+	}
+
+	expected := make(map[string]bool, len(violations))
+	for _, v := range violations {
+		expected[v] = true
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected violations %q, got %s", violations, se)
+	}
+}
+
+func TestSchemaError(t *testing.T) {
+	testCases := []struct {
+		violations []SchemaViolation
+		expected   string
+	}{
+		{
+			violations: []SchemaViolation{{Message: "bad"}},
+			expected:   "violates schema s.json: #: bad",
+		},
+		{
+			violations: []SchemaViolation{{Pointer: "/a", Message: "bad"}, {Pointer: "/b", Message: "worse"}},
+			expected:   "violates schema s.json:\n#/a: bad\n#/b: worse",
+		},
+	}
+
+	for _, testCase := range testCases {
+		if actual := (&SchemaError{Schema: "s.json", Violations: testCase.violations}).Error(); actual != testCase.expected {
+			t.Errorf("expected %q, got %q", testCase.expected, actual)
+		}
+	}
+}
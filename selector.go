@@ -13,6 +13,17 @@ type SelectorConfig struct {
 	// Parser is the configuration for parsing templates that match any
 	// of the configured patterns.
 	Parser ParserConfig `json:"parser" yaml:"parser"`
+
+	// InputSchema is the optional path to a JSON Schema that the models for the selected
+	// templates must conform to.  The path is relative to the root of the file system
+	// that templates are loaded from.  See CompileSchema.
+	InputSchema string `json:"inputSchema" yaml:"inputSchema"`
+
+	// OutputSchema is the optional path to a JSON Schema that the rendered output of
+	// the selected templates must conform to.  Only JSON and YAML output can be checked
+	// against a schema.  The path is relative to the root of the file system that
+	// templates are loaded from.
+	OutputSchema string `json:"outputSchema" yaml:"outputSchema"`
 }
 
 // Selector is a strategy for determining how to parse a template based
@@ -34,20 +45,43 @@ type matchSelector struct {
 }
 
 func (ms matchSelector) Select(name string) (p Parser, found bool) {
-	for _, e := range ms.entries {
-		found = e.m.Match(name)
-		if found {
-			p = e.p
-			break
-		}
+	if i := ms.index(name); i >= 0 {
+		p, found = ms.entries[i].p, true
 	}
 
 	return
 }
 
+// index returns the position of the first entry that matches a name, or -1
+// if no entry matches.
+func (ms matchSelector) index(name string) int {
+	for i, e := range ms.entries {
+		if e.m.Match(name) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// sameParser tests whether a layout may be parsed by the Parser of a template
+// that extends it.  That is the case unless each is selected by a different entry.
+func (ms *matchSelector) sameParser(template, layout string) bool {
+	if ms == nil {
+		return true
+	}
+
+	t, l := ms.index(template), ms.index(layout)
+	return t < 0 || l < 0 || t == l
+}
+
 // NewSelector constructs a Selector based on the given configurations.
 // If an empty configs is passed, the returned Selector won't match
 // any template names.
+//
+// Layouts are resolved through the returned Selector.  A layout that is
+// selected by a different configuration than a template that extends it
+// cannot be parsed with that template, so extending it is an error.
 func NewSelector(configs ...SelectorConfig) (Selector, error) {
 	ms := &matchSelector{
 		entries: make([]matchEntry, len(configs)),
@@ -55,6 +89,7 @@ func NewSelector(configs ...SelectorConfig) (Selector, error) {
 
 	for i, c := range configs {
 		var err error
+		c.Parser.selector = ms
 		ms.entries[i].m, err = ParsePatterns(c.Patterns...)
 		if err == nil {
 			ms.entries[i].p, err = NewParser(c.Parser)
@@ -0,0 +1,936 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	ttemplate "text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// ExpressionStart begins an expression within a string value of a structured
+	// template.  The expression ends with the matching "}".  Use "$${" for a literal "${".
+	ExpressionStart = "${"
+
+	// DirectiveIf is the key of a conditional in a structured template.  Its mapping
+	// may also have then and else keys.  When the condition is false and there is no
+	// else, the mapping is omitted from its enclosing object or array.
+	DirectiveIf = "$if"
+
+	// DirectiveEach is the key of an iteration in a structured template.  Its mapping
+	// may also have do, as, and key keys.  The result is an array with the value of do
+	// for each element of the array or object being iterated over.
+	DirectiveEach = "$each"
+
+	// rootVariable is the expression variable that holds the template's data.
+	rootVariable = "root"
+
+	// frameVariable is the internal expression variable that holds the frame.
+	frameVariable = "_thoth"
+)
+
+var (
+	// variablePattern is the syntax of the names bound by as and key.
+	variablePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+	// directiveKeys are the keys allowed in each kind of directive mapping.
+	directiveKeys = map[string][]string{
+		DirectiveIf:   {DirectiveIf, "then", "else"},
+		DirectiveEach: {DirectiveEach, "do", "as", "key"},
+	}
+)
+
+// orderedMap is an object produced by a structured template.  Keys are
+// serialized in the order they were written in the template.
+type orderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (om *orderedMap) set(key string, value interface{}) {
+	if _, exists := om.values[key]; !exists {
+		om.keys = append(om.keys, key)
+	}
+
+	om.values[key] = value
+}
+
+// MarshalJSON writes this object's keys in order.
+func (om *orderedMap) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, k := range om.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		key, err := encodeJSON(k)
+		if err == nil {
+			b.Write(key)
+			b.WriteByte(':')
+
+			var value []byte
+			value, err = encodeJSON(om.values[k])
+			b.Write(value)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// MarshalYAML writes this object's keys in order.
+func (om *orderedMap) MarshalYAML() (interface{}, error) {
+	n := &yaml.Node{
+		Kind: yaml.MappingNode,
+		Tag:  "!!map",
+	}
+
+	for _, k := range om.keys {
+		value := new(yaml.Node)
+		if err := value.Encode(om.values[k]); err != nil {
+			return nil, err
+		}
+
+		n.Content = append(n.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: k},
+			value,
+		)
+	}
+
+	return n, nil
+}
+
+// frame is the data passed to each expression.  The preamble of each expression
+// unpacks the frame into the root, the current value, and any bound variables.
+type frame struct {
+	Root interface{}
+	Dots []interface{}
+	Vars map[string]interface{}
+
+	value interface{}
+}
+
+// Capture records the value of an expression.  It is invoked by expressions
+// that produce a value rather than text.
+func (f *frame) Capture(v interface{}) string {
+	f.value = v
+	return ""
+}
+
+// scope is the evaluation state at some point within a structured template.
+type scope struct {
+	root interface{}
+	dot  interface{}
+	vars map[string]interface{}
+}
+
+// bind returns a copy of this scope with a new current value and variables.
+func (s scope) bind(dot interface{}, names []string, values []interface{}) scope {
+	vars := make(map[string]interface{}, len(s.vars)+len(names))
+	for k, v := range s.vars {
+		vars[k] = v
+	}
+
+	for i, n := range names {
+		if len(n) > 0 {
+			vars[n] = values[i]
+		}
+	}
+
+	return scope{root: s.root, dot: dot, vars: vars}
+}
+
+// evaluation is the state of a single execution of a structured template.
+type evaluation struct {
+	st  *structuredTemplate
+	ctx context.Context
+
+	// operand is true while evaluating the operand of a directive, such as
+	// the condition of an $if, which is not part of the output
+	operand bool
+
+	// size is a lower bound on the size of the output produced so far.  It is
+	// tracked only when there is a maximum output size, so that a runaway
+	// template stops long before its result is encoded.
+	size int64
+}
+
+// produce accounts for a value added to the output.  Objects and arrays that the
+// template builds account for their own contents, so they are not measured here.
+func (ev *evaluation) produce(v interface{}) error {
+	maxOutput := ev.st.limits.maxOutput
+	if maxOutput <= 0 || ev.operand {
+		return nil
+	}
+
+	ev.size += measure(v, maxOutput-ev.size)
+	if ev.size > maxOutput {
+		return &OutputLimitError{MaxOutput: maxOutput}
+	}
+
+	return nil
+}
+
+// measure approximates the encoded size of a value.  The approximation never exceeds
+// the actual size.  Measuring stops once the size is known to exceed the limit.
+func measure(v interface{}, limit int64) int64 {
+	switch tv := v.(type) {
+	case nil:
+		return 4
+
+	case string:
+		return int64(len(tv)) + 2
+
+	case []byte:
+		return int64(len(tv))
+
+	case *orderedMap:
+		size := int64(2)
+		for i := 0; size <= limit && i < len(tv.keys); i++ {
+			size += int64(len(tv.keys[i])) + 3 + measure(tv.values[tv.keys[i]], limit-size)
+		}
+
+		return size
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return int64(len(fmt.Sprint(v)))
+
+	case reflect.Slice, reflect.Array:
+		size := int64(2)
+		for i := 0; size <= limit && i < rv.Len(); i++ {
+			size += 1 + measure(rv.Index(i).Interface(), limit-size)
+		}
+
+		return size
+
+	case reflect.Map:
+		size := int64(2)
+		for it := rv.MapRange(); size <= limit && it.Next(); {
+			size += int64(len(fmt.Sprint(it.Key().Interface()))) + 3 + measure(it.Value().Interface(), limit-size)
+		}
+
+		return size
+
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return 4
+		}
+
+		return measure(rv.Elem().Interface(), limit)
+
+	default:
+		return 2
+	}
+}
+
+// evalOperand evaluates the operand of a directive, which does not count as output.
+func (ev *evaluation) evalOperand(n snode, s scope) (interface{}, error) {
+	outer := ev.operand
+	ev.operand = true
+	v, _, err := n.eval(ev, s)
+	ev.operand = outer
+	return v, err
+}
+
+// snode is a compiled node of a structured template.  The present flag is false
+// when a node evaluates to nothing, as with a false $if that has no else.
+type snode interface {
+	eval(ev *evaluation, s scope) (v interface{}, present bool, err error)
+}
+
+type literalNode struct {
+	value interface{}
+}
+
+func (ln literalNode) eval(ev *evaluation, _ scope) (interface{}, bool, error) {
+	return ln.value, true, ev.produce(ln.value)
+}
+
+// valueNode is a string that consists of exactly one expression.  Its value is the
+// value of the expression, whatever its type.  The name of the expression's template
+// includes its location, so errors from the template need no further context.
+type valueNode struct {
+	name string
+}
+
+func (vn valueNode) eval(ev *evaluation, s scope) (interface{}, bool, error) {
+	f := &frame{Root: s.root, Dots: []interface{}{s.dot}, Vars: s.vars}
+	err := ev.st.exprs.ExecuteTemplate(io.Discard, vn.name, f)
+	if err == nil {
+		err = ev.produce(f.value)
+	}
+
+	return f.value, true, err
+}
+
+// textNode is a string that interpolates one or more expressions.
+type textNode struct {
+	name string
+}
+
+func (tn textNode) eval(ev *evaluation, s scope) (interface{}, bool, error) {
+	var o strings.Builder
+	f := &frame{Root: s.root, Dots: []interface{}{s.dot}, Vars: s.vars}
+	err := ev.st.exprs.ExecuteTemplate(&o, tn.name, f)
+	if err == nil {
+		err = ev.produce(o.String())
+	}
+
+	return o.String(), true, err
+}
+
+type mappingNode struct {
+	keys   []snode
+	values []snode
+}
+
+func (mn mappingNode) eval(ev *evaluation, s scope) (interface{}, bool, error) {
+	om := &orderedMap{
+		keys:   make([]string, 0, len(mn.keys)),
+		values: make(map[string]interface{}, len(mn.keys)),
+	}
+
+	if err := ev.produce(om); err != nil {
+		return nil, false, err
+	}
+
+	for i := range mn.keys {
+		value, present, err := mn.values[i].eval(ev, s)
+		if err != nil {
+			return nil, false, err
+		} else if !present {
+			continue
+		}
+
+		key, _, err := mn.keys[i].eval(ev, s)
+		if err != nil {
+			return nil, false, err
+		}
+
+		om.set(fmt.Sprint(key), value)
+	}
+
+	return om, true, nil
+}
+
+type sequenceNode struct {
+	items []snode
+}
+
+func (sn sequenceNode) eval(ev *evaluation, s scope) (interface{}, bool, error) {
+	l := make([]interface{}, 0, len(sn.items))
+	if err := ev.produce(l); err != nil {
+		return nil, false, err
+	}
+
+	for _, item := range sn.items {
+		value, present, err := item.eval(ev, s)
+		if err != nil {
+			return nil, false, err
+		} else if present {
+			l = append(l, value)
+		}
+	}
+
+	return l, true, nil
+}
+
+type ifNode struct {
+	condition snode
+	then      snode
+	otherwise snode
+}
+
+func (in ifNode) eval(ev *evaluation, s scope) (interface{}, bool, error) {
+	c, err := ev.evalOperand(in.condition, s)
+	if err != nil {
+		return nil, false, err
+	}
+
+	truth, _ := ttemplate.IsTrue(c)
+	switch {
+	case truth:
+		return in.then.eval(ev, s)
+
+	case in.otherwise != nil:
+		return in.otherwise.eval(ev, s)
+
+	default:
+		return nil, false, nil
+	}
+}
+
+type eachNode struct {
+	pos        *yaml.Node
+	collection snode
+	as         string
+	key        string
+	do         snode
+}
+
+// eval evaluates do for each element of the collection.  The context is
+// checked before each element.
+func (en eachNode) eval(ev *evaluation, s scope) (interface{}, bool, error) {
+	c, err := ev.evalOperand(en.collection, s)
+	if err != nil {
+		return nil, false, err
+	}
+
+	l := make([]interface{}, 0)
+	if err = ev.produce(l); err != nil {
+		return nil, false, ev.st.wrap(en.pos, err)
+	}
+
+	err = iterate(c, func(k, v interface{}) error {
+		if err := ev.ctx.Err(); err != nil {
+			return err
+		}
+
+		value, present, err := en.do.eval(ev, s.bind(v, []string{en.as, en.key}, []interface{}{v, k}))
+		if err == nil && present {
+			l = append(l, value)
+		}
+
+		return err
+	})
+
+	if err != nil {
+		return nil, false, ev.st.wrap(en.pos, err)
+	}
+
+	return l, true, nil
+}
+
+// iterate visits each element of an array or object.  Arrays are visited in order
+// with their indices as keys.  Objects produced by the template itself are visited in
+// the order their keys were written, and other maps are visited in key order.
+func iterate(c interface{}, f func(k, v interface{}) error) error {
+	if om, ok := c.(*orderedMap); ok {
+		for _, k := range om.keys {
+			if err := f(k, om.values[k]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	rv := reflect.ValueOf(c)
+	switch rv.Kind() {
+	case reflect.Invalid:
+		return nil
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if err := f(i, rv.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+
+		return nil
+
+	case reflect.Map:
+		keys := rv.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+
+		for _, k := range keys {
+			if err := f(k.Interface(), rv.MapIndex(k).Interface()); err != nil {
+				return err
+			}
+		}
+
+		return nil
+
+	default:
+		return fmt.Errorf("cannot iterate over %T", c)
+	}
+}
+
+// structuredTemplate is a Template whose source is a JSON or YAML document.
+type structuredTemplate struct {
+	name      string
+	mediaType string
+	root      snode
+	exprs     *ttemplate.Template
+	limits    limits
+}
+
+func (st *structuredTemplate) Name() string {
+	return st.name
+}
+
+func (st *structuredTemplate) MediaType() string {
+	return st.mediaType
+}
+
+// wrap associates an error with the location of a node in this template.
+func (st *structuredTemplate) wrap(pos *yaml.Node, err error) error {
+	var se *PositionError
+	if err == nil || errors.As(err, &se) {
+		return err
+	}
+
+	return &PositionError{
+		Name:   st.name,
+		Line:   pos.Line,
+		Column: pos.Column,
+		Err:    err,
+	}
+}
+
+// Execute evaluates this template and writes the result.  YAML media types produce
+// YAML output.  All other media types produce JSON.
+func (st *structuredTemplate) Execute(output io.Writer, data interface{}) error {
+	return st.ExecuteWith(context.Background(), output, data)
+}
+
+// render evaluates this template and writes the result.
+func (st *structuredTemplate) render(ctx context.Context, output io.Writer, data interface{}) error {
+	ev := &evaluation{st: st, ctx: ctx}
+	v, _, err := st.root.eval(ev, scope{root: data, dot: data})
+	if err != nil {
+		return err
+	}
+
+	if structuredFormat(MediaType(st)) == FormatYAML {
+		e := yaml.NewEncoder(output)
+		e.SetIndent(2)
+		err = e.Encode(v)
+		if err == nil {
+			err = e.Close()
+		}
+	} else {
+		e := json.NewEncoder(output)
+		e.SetEscapeHTML(false)
+		e.SetIndent("", "  ")
+		err = e.Encode(v)
+	}
+
+	return err
+}
+
+// ExecuteWith is like Execute, but with per-execution options.  Function overrides
+// are installed into a clone of the expression templates.  The context is checked
+// before evaluation, for each element of an iteration, and when the result is written.
+// The maximum output size is enforced as values are produced.
+func (st *structuredTemplate) ExecuteWith(ctx context.Context, output io.Writer, data interface{}, opts ...ExecuteOption) error {
+	ctx, cancel, err := st.limits.context(ctx)
+	defer cancel()
+	if err != nil {
+		return err
+	}
+
+	output = st.limits.writer(ctx, output)
+	ec := NewExecuteConfig(opts...)
+	if len(ec.Funcs) == 0 {
+		return st.render(ctx, output, data)
+	}
+
+	exprs, err := st.exprs.Clone()
+	if err != nil {
+		return err
+	}
+
+	clone := *st
+	clone.exprs = exprs.Funcs(ec.Funcs)
+	return clone.render(ctx, output, data)
+}
+
+// structuredCompiler turns a YAML node tree into snodes, defining a template
+// for each expression along the way.
+type structuredCompiler struct {
+	st      *structuredTemplate
+	vars    []string
+	aliases aliasExpander
+}
+
+func (sc *structuredCompiler) errorf(pos *yaml.Node, format string, args ...interface{}) error {
+	return &PositionError{
+		Name:   sc.st.name,
+		Line:   pos.Line,
+		Column: pos.Column,
+		Err:    fmt.Errorf(format, args...),
+	}
+}
+
+func (sc *structuredCompiler) compile(n *yaml.Node) (snode, error) {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			return literalNode{}, nil
+		}
+
+		return sc.compile(n.Content[0])
+
+	case yaml.AliasNode:
+		if err := sc.aliases.enter(n); err != nil {
+			return nil, sc.errorf(n, "%s", err)
+		}
+
+		defer sc.aliases.exit(n)
+		return sc.compile(n.Alias)
+
+	case yaml.MappingNode:
+		return sc.mapping(n)
+
+	case yaml.SequenceNode:
+		sn := sequenceNode{items: make([]snode, 0, len(n.Content))}
+		for _, item := range n.Content {
+			c, err := sc.compile(item)
+			if err != nil {
+				return nil, err
+			}
+
+			sn.items = append(sn.items, c)
+		}
+
+		return sn, nil
+
+	case yaml.ScalarNode:
+		if n.Tag == "!!str" {
+			return sc.string(n)
+		}
+
+		var v interface{}
+		if err := n.Decode(&v); err != nil {
+			return nil, sc.errorf(n, "%s", err)
+		}
+
+		return literalNode{value: v}, nil
+
+	default:
+		return nil, sc.errorf(n, "unexpected YAML node")
+	}
+}
+
+func (sc *structuredCompiler) mapping(n *yaml.Node) (snode, error) {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if k := n.Content[i].Value; k == DirectiveIf || k == DirectiveEach {
+			return sc.directive(n, k)
+		}
+	}
+
+	mn := mappingNode{
+		keys:   make([]snode, 0, len(n.Content)/2),
+		values: make([]snode, 0, len(n.Content)/2),
+	}
+
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		kn := n.Content[i]
+		if strings.HasPrefix(kn.Value, "$") {
+			if !strings.HasPrefix(kn.Value, "$$") {
+				return nil, sc.errorf(kn, "unknown directive %s", kn.Value)
+			}
+
+			// "$$" escapes a key that begins with a literal "$"
+			escaped := *kn
+			escaped.Value = kn.Value[1:]
+			kn = &escaped
+		}
+
+		key, err := sc.compile(kn)
+		var value snode
+		if err == nil {
+			value, err = sc.compile(n.Content[i+1])
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		mn.keys = append(mn.keys, key)
+		mn.values = append(mn.values, value)
+	}
+
+	return mn, nil
+}
+
+// directive compiles a mapping that holds either $if or $each.
+func (sc *structuredCompiler) directive(n *yaml.Node, name string) (snode, error) {
+	fields := make(map[string]*yaml.Node, len(n.Content)/2)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k := n.Content[i].Value
+		allowed := false
+		for _, a := range directiveKeys[name] {
+			allowed = allowed || a == k
+		}
+
+		if !allowed {
+			return nil, sc.errorf(n.Content[i], "%s is not allowed with %s", k, name)
+		}
+
+		fields[k] = n.Content[i+1]
+	}
+
+	// compileField compiles an optional field of the directive
+	compileField := func(k string) (snode, error) {
+		if f, ok := fields[k]; ok {
+			return sc.compile(f)
+		}
+
+		return nil, nil
+	}
+
+	if name == DirectiveIf {
+		if _, ok := fields["then"]; !ok {
+			return nil, sc.errorf(n, "%s requires then", DirectiveIf)
+		}
+
+		var in ifNode
+		var err error
+		in.condition, err = compileField(DirectiveIf)
+		if err == nil {
+			in.then, err = compileField("then")
+		}
+
+		if err == nil {
+			in.otherwise, err = compileField("else")
+		}
+
+		return in, err
+	}
+
+	en := eachNode{pos: n}
+	for _, k := range []string{"as", "key"} {
+		f, ok := fields[k]
+		switch {
+		case !ok:
+			continue
+
+		case !variablePattern.MatchString(f.Value) || f.Value == rootVariable || f.Value == frameVariable:
+			return nil, sc.errorf(f, "%q cannot be used as a variable name", f.Value)
+
+		case k == "as":
+			en.as = f.Value
+
+		default:
+			en.key = f.Value
+		}
+	}
+
+	var err error
+	en.collection, err = compileField(DirectiveEach)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := fields["do"]; !ok {
+		return nil, sc.errorf(n, "%s requires do", DirectiveEach)
+	}
+
+	// the variables bound by this directive are visible only within do
+	outer := sc.vars
+	sc.vars = append(append(make([]string, 0, len(outer)+2), outer...), en.as, en.key)
+	en.do, err = compileField("do")
+	sc.vars = outer
+
+	return en, err
+}
+
+// string compiles a string scalar, which may contain expressions.
+func (sc *structuredCompiler) string(n *yaml.Node) (snode, error) {
+	parts, exprs, err := splitExpressions(n.Value)
+	if err != nil {
+		return nil, sc.errorf(n, "%s", err)
+	}
+
+	if len(exprs) == 0 {
+		return literalNode{value: strings.Join(parts, "")}, nil
+	}
+
+	var body strings.Builder
+	whole := len(exprs) == 1 && len(parts[0]) == 0 && len(parts[1]) == 0
+	if whole {
+		fmt.Fprintf(&body, "{{ $%s.Capture (%s) }}", frameVariable, exprs[0])
+	} else {
+		for i, p := range parts {
+			if len(p) > 0 {
+				fmt.Fprintf(&body, "{{ %s }}", strconv.Quote(p))
+			}
+
+			if i < len(exprs) {
+				fmt.Fprintf(&body, "{{ %s }}", exprs[i])
+			}
+		}
+	}
+
+	name := fmt.Sprintf("%s:%d:%d", sc.st.name, n.Line, n.Column)
+	if _, err := sc.st.exprs.New(name).Parse(sc.preamble() + body.String() + "{{ end }}"); err != nil {
+		return nil, err
+	}
+
+	if whole {
+		return valueNode{name: name}, nil
+	}
+
+	return textNode{name: name}, nil
+}
+
+// preamble unpacks a frame into the variables visible to an expression, then
+// starts a single iteration that sets the current value.  The caller must supply
+// the closing {{ end }}.
+func (sc *structuredCompiler) preamble() string {
+	var o strings.Builder
+	fmt.Fprintf(&o, "{{- $%s := . -}}{{- $%s := .Root -}}", frameVariable, rootVariable)
+	for _, v := range sc.vars {
+		if len(v) > 0 {
+			fmt.Fprintf(&o, "{{- $%s := index .Vars %q -}}", v, v)
+		}
+	}
+
+	o.WriteString("{{- range .Dots -}}")
+	return o.String()
+}
+
+// splitExpressions breaks a string into its literal parts and expressions.  There
+// is always one more part than there are expressions.
+func splitExpressions(s string) (parts, exprs []string, err error) {
+	var literal strings.Builder
+	for {
+		i := strings.Index(s, ExpressionStart)
+		if i < 0 {
+			literal.WriteString(s)
+			break
+		}
+
+		if i > 0 && s[i-1] == '$' {
+			// "$${" is an escaped, literal "${"
+			literal.WriteString(s[:i-1])
+			literal.WriteString(ExpressionStart)
+			s = s[i+len(ExpressionStart):]
+			continue
+		}
+
+		literal.WriteString(s[:i])
+		s = s[i+len(ExpressionStart):]
+		end := expressionEnd(s)
+		if end < 0 {
+			return nil, nil, errors.New("unterminated expression")
+		}
+
+		expr := strings.TrimSpace(s[:end])
+		if len(expr) == 0 {
+			return nil, nil, errors.New("empty expression")
+		}
+
+		parts = append(parts, literal.String())
+		exprs = append(exprs, expr)
+		literal.Reset()
+		s = s[end+1:]
+	}
+
+	parts = append(parts, literal.String())
+	return
+}
+
+// expressionEnd finds the "}" that closes an expression, skipping over quoted
+// strings and balanced braces.  This function returns -1 if there is no such "}".
+func expressionEnd(s string) int {
+	var (
+		depth int
+		quote byte
+	)
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0 && c == '\\' && quote != '`':
+			i++
+
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+
+		case c == '"' || c == '`' || c == '\'':
+			quote = c
+
+		case c == '{':
+			depth++
+
+		case c == '}' && depth == 0:
+			return i
+
+		case c == '}':
+			depth--
+		}
+	}
+
+	return -1
+}
+
+// structuredParser produces templates whose source is a JSON or YAML document.
+type structuredParser struct {
+	options   []string
+	funcs     map[string]interface{}
+	mediaType string
+	limits    limits
+}
+
+// newStructuredParser is the Engine for EngineStructured.
+func newStructuredParser(c ParserConfig) (Parser, error) {
+	options, err := templateOptions(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return structuredParser{
+		options:   options,
+		funcs:     c.FuncMap,
+		mediaType: c.MediaType,
+		limits:    newLimits(c),
+	}, nil
+}
+
+func (sp structuredParser) Parse(name, content string) (Template, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &doc); err != nil {
+		return nil, fmt.Errorf("template: %s: %w", name, err)
+	}
+
+	st := &structuredTemplate{
+		name:      name,
+		mediaType: sp.mediaType,
+		exprs:     ttemplate.New(name).Funcs(sp.funcs).Option(sp.options...),
+		limits:    sp.limits,
+	}
+
+	if doc.Kind == 0 {
+		// empty content is a null document
+		st.root = literalNode{}
+		return st, nil
+	}
+
+	sc := structuredCompiler{st: st}
+	root, err := sc.compile(&doc)
+	if err != nil {
+		return nil, err
+	}
+
+	st.root = root
+	return st, nil
+}
@@ -0,0 +1,303 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSplitExpressions(t *testing.T) {
+	testCases := []struct {
+		value string
+		parts []string
+		exprs []string
+		err   string
+	}{
+		{value: "plain", parts: []string{"plain"}},
+		{value: "${.a}", parts: []string{"", ""}, exprs: []string{".a"}},
+		{value: "a ${ .b } c", parts: []string{"a ", " c"}, exprs: []string{".b"}},
+		{value: `${printf "}%s{" .a}`, parts: []string{"", ""}, exprs: []string{`printf "}%s{" .a`}},
+		{value: "${index .m `}`}", parts: []string{"", ""}, exprs: []string{"index .m `}`"}},
+		{value: "cost: $${x}", parts: []string{"cost: ${x}"}},
+		{value: "${.a", err: "unterminated expression"},
+		{value: "${ }", err: "empty expression"},
+	}
+
+	for _, testCase := range testCases {
+		parts, exprs, err := splitExpressions(testCase.value)
+		switch {
+		case len(testCase.err) > 0:
+			if err == nil || err.Error() != testCase.err {
+				t.Errorf("%q: expected error %q, got %v", testCase.value, testCase.err, err)
+			}
+
+		case err != nil:
+			t.Errorf("%q: unexpected error: %s", testCase.value, err)
+
+		case strings.Join(parts, "|") != strings.Join(testCase.parts, "|") || strings.Join(exprs, "|") != strings.Join(testCase.exprs, "|"):
+			t.Errorf("%q: expected %q and %q, got %q and %q", testCase.value, testCase.parts, testCase.exprs, parts, exprs)
+		}
+	}
+}
+
+func TestStructuredTemplate(t *testing.T) {
+	testCases := []struct {
+		name      string
+		mediaType string
+		template  string
+		data      interface{}
+		expected  string
+	}{
+		{
+			name:     "Empty",
+			template: "",
+			expected: "null\n",
+		},
+		{
+			name:     "Literals",
+			template: `{"s": "text", "n": 1, "f": 1.5, "b": true, "z": null, "l": [1, "a"]}`,
+			expected: `{"s":"text","n":1,"f":1.5,"b":true,"z":null,"l":[1,"a"]}`,
+		},
+		{
+			name:     "KeyOrder",
+			template: "z: 1\na: 2\nm: 3\n",
+			expected: `{"z":1,"a":2,"m":3}`,
+		},
+		{
+			name:     "Value",
+			template: `{"n": "${.n}", "l": "${.l}", "m": "${.m}"}`,
+			data:     Model{"n": 12, "l": []interface{}{"x", 2}, "m": map[string]interface{}{"k": true}},
+			expected: `{"n":12,"l":["x",2],"m":{"k":true}}`,
+		},
+		{
+			name:     "Interpolation",
+			template: `{"greeting": "hello, ${.who}!  you are ${.age}"}`,
+			data:     Model{"who": `"world"`, "age": 3},
+			expected: `{"greeting":"hello, \"world\"!  you are 3"}`,
+		},
+		{
+			name:     "ExpressionKey",
+			template: `{"id-${.key}": "value"}`,
+			data:     Model{"key": "dynamic"},
+			expected: `{"id-dynamic":"value"}`,
+		},
+		{
+			name:     "Escapes",
+			template: `{"$$ref": "$${literal}", "key": "$$ alone"}`,
+			expected: `{"$ref":"${literal}","key":"$$ alone"}`,
+		},
+		{
+			name:     "Root",
+			template: `{"$each": "${.items}", "do": "${$root.prefix}${.}"}`,
+			data:     Model{"items": []interface{}{"a", "b"}, "prefix": "-"},
+			expected: `["-a","-b"]`,
+		},
+		{
+			name:     "IfThen",
+			template: `{"a": {"$if": "${.flag}", "then": "yes", "else": "no"}}`,
+			data:     Model{"flag": true},
+			expected: `{"a":"yes"}`,
+		},
+		{
+			name:     "IfElse",
+			template: `{"a": {"$if": "${.flag}", "then": "yes", "else": "no"}}`,
+			data:     Model{"flag": false},
+			expected: `{"a":"no"}`,
+		},
+		{
+			name:     "IfOmitted",
+			template: `{"a": {"$if": "${.flag}", "then": "yes"}, "b": [1, {"$if": "${.flag}", "then": 2}, 3]}`,
+			data:     Model{"flag": false},
+			expected: `{"b":[1,3]}`,
+		},
+		{
+			name:     "EachArray",
+			template: `{"$each": "${.list}", "as": "item", "key": "i", "do": {"index": "${$i}", "value": "${$item}"}}`,
+			data:     Model{"list": []interface{}{"x", "y"}},
+			expected: `[{"index":0,"value":"x"},{"index":1,"value":"y"}]`,
+		},
+		{
+			name:     "EachMap",
+			template: `{"$each": "${.m}", "key": "k", "do": "${$k}=${.}"}`,
+			data:     Model{"m": map[string]interface{}{"b": 2, "a": 1}},
+			expected: `["a=1","b=2"]`,
+		},
+		{
+			name:     "EachNil",
+			template: `{"$each": "${.missing}", "do": 1}`,
+			data:     map[string]interface{}{"missing": nil},
+			expected: `[]`,
+		},
+		{
+			name:     "NestedEach",
+			template: `{"$each": "${.rows}", "as": "row", "do": {"$each": "${$row}", "do": "${$row}"}}`,
+			data:     Model{"rows": []interface{}{[]interface{}{1}}},
+			expected: `[[[1]]]`,
+		},
+		{
+			name:     "Aliases",
+			template: "base: &base {a: 1}\ncopy: *base\n",
+			expected: `{"base":{"a":1},"copy":{"a":1}}`,
+		},
+		{
+			name:      "YAML",
+			mediaType: "application/yaml",
+			template:  `{"b": "${.n}", "a": [1, 2]}`,
+			data:      Model{"n": "x"},
+			expected:  "b: x\na:\n  - 1\n  - 2\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			p, err := NewParser(ParserConfig{Engine: EngineStructured, MediaType: testCase.mediaType})
+			if err != nil {
+				t.Fatalf("unable to create parser: %s", err)
+			}
+
+			tmpl, err := p.Parse("t", testCase.template)
+			if err != nil {
+				t.Fatalf("unable to parse template: %s", err)
+			}
+
+			var o bytes.Buffer
+			if err := tmpl.Execute(&o, testCase.data); err != nil {
+				t.Fatalf("unable to execute template: %s", err)
+			}
+
+			actual := o.String()
+			if len(testCase.mediaType) == 0 {
+				actual = compactJSON(t, actual)
+			}
+
+			if actual != testCase.expected {
+				t.Errorf("expected %s, got %s", testCase.expected, actual)
+			}
+		})
+	}
+}
+
+// compactJSON removes the insignificant whitespace from JSON output.
+func compactJSON(t *testing.T, s string) string {
+	if s == "null\n" {
+		return s
+	}
+
+	var o bytes.Buffer
+	if err := json.Compact(&o, []byte(s)); err != nil {
+		t.Fatalf("invalid JSON output %s: %s", s, err)
+	}
+
+	return o.String()
+}
+
+func TestStructuredTemplateErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		template string
+		data     interface{}
+		parseErr string
+		execErr  string
+	}{
+		{name: "Syntax", template: `{"a": [}`, parseErr: "template: t: "},
+		{name: "UnknownDirective", template: `{"$unless": 1}`, parseErr: "template: t:1:2: unknown directive $unless"},
+		{name: "ExtraIfKey", template: `{"$if": true, "then": 1, "do": 2}`, parseErr: "do is not allowed with $if"},
+		{name: "IfWithoutThen", template: `{"$if": true}`, parseErr: "$if requires then"},
+		{name: "EachWithoutDo", template: `{"$each": []}`, parseErr: "$each requires do"},
+		{name: "BadVariable", template: `{"$each": [], "as": "1x", "do": 1}`, parseErr: `"1x" cannot be used as a variable name`},
+		{name: "RootVariable", template: `{"$each": [], "key": "root", "do": 1}`, parseErr: `"root" cannot be used as a variable name`},
+		{name: "Unterminated", template: `{"a": "${.b"}`, parseErr: "template: t:1:7: unterminated expression"},
+		{name: "BadExpression", template: `{"a": "${nosuchfunc 1}"}`, parseErr: `function "nosuchfunc" not defined`},
+		{name: "UnboundVariable", template: `{"a": "${$item}"}`, parseErr: "undefined variable"},
+		{
+			name:     "AliasCycle",
+			template: "a: &x\n  b: *x\n",
+			parseErr: "alias *x refers to a node that contains it",
+		},
+		{
+			name:     "AliasExpansion",
+			template: billionLaughs(),
+			parseErr: "expanded more than 10000 aliases",
+		},
+		{
+			name:     "MissingKey",
+			template: `{"a": "${.missing}"}`,
+			data:     Model{},
+			execErr:  "map has no entry for key",
+		},
+		{
+			name:     "NotIterable",
+			template: `{"$each": "${.n}", "do": 1}`,
+			data:     Model{"n": 1},
+			execErr:  "template: t:1:1: cannot iterate over int",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			p, err := NewParser(ParserConfig{Engine: EngineStructured})
+			if err != nil {
+				t.Fatalf("unable to create parser: %s", err)
+			}
+
+			tmpl, err := p.Parse("t", testCase.template)
+			if len(testCase.parseErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), testCase.parseErr) {
+					t.Errorf("expected a parse error containing %q, got %v", testCase.parseErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unable to parse template: %s", err)
+			}
+
+			var o bytes.Buffer
+			err = tmpl.Execute(&o, testCase.data)
+			if err == nil || !strings.Contains(err.Error(), testCase.execErr) {
+				t.Errorf("expected an execution error containing %q, got %v", testCase.execErr, err)
+			}
+		})
+	}
+}
+
+func TestStructuredTemplateExecuteWith(t *testing.T) {
+	p, err := NewParser(ParserConfig{
+		Engine:  EngineStructured,
+		FuncMap: map[string]interface{}{"greet": func() string { return "hello" }},
+	})
+
+	if err != nil {
+		t.Fatalf("unable to create parser: %s", err)
+	}
+
+	tmpl, err := p.Parse("t", `{"a": "${greet}"}`)
+	if err != nil {
+		t.Fatalf("unable to parse template: %s", err)
+	}
+
+	var o bytes.Buffer
+	err = ExecuteWith(context.Background(), tmpl, &o, nil, WithFuncs(map[string]interface{}{"greet": func() string { return "bonjour" }}))
+	if err != nil || compactJSON(t, o.String()) != `{"a":"bonjour"}` {
+		t.Errorf("expected the override, got %s (%v)", o.String(), err)
+	}
+
+	o.Reset()
+	if err := tmpl.Execute(&o, nil); err != nil || compactJSON(t, o.String()) != `{"a":"hello"}` {
+		t.Errorf("the override should not affect later executions, got %s (%v)", o.String(), err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	o.Reset()
+	if err := ExecuteWith(ctx, tmpl, &o, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
@@ -4,6 +4,7 @@
 package thoth
 
 import (
+	"context"
 	"io"
 )
 
@@ -55,6 +56,10 @@ func (mt mediaTemplate) MediaType() string {
 	return mt.mediaType
 }
 
+func (mt mediaTemplate) ExecuteWith(ctx context.Context, output io.Writer, data interface{}, opts ...ExecuteOption) error {
+	return ExecuteWith(ctx, mt.Template, output, data, opts...)
+}
+
 // MediaTemplate associates a Template, typically a raw golang template,
 // with a MIME type.  The returned Template will also implement MediaTyper.
 func MediaTemplate(t Template, mediaType string) Template {
@@ -0,0 +1,265 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Validator checks that rendered output is well-formed for a media type.
+type Validator interface {
+	// Validate returns an error if output is not well-formed.
+	Validate(output []byte) error
+}
+
+// ValidatorFunc is a function type that implements Validator.
+type ValidatorFunc func([]byte) error
+
+// Validate invokes this function.
+func (vf ValidatorFunc) Validate(output []byte) error {
+	return vf(output)
+}
+
+// ValidationError indicates that rendered output was not well-formed for its media type.
+type ValidationError struct {
+	// MediaType is the media type the output was validated against.
+	MediaType string
+
+	// Err is the error returned by the Validator.
+	Err error
+}
+
+// Error satisfies the error interface.
+func (ve *ValidationError) Error() string {
+	return fmt.Sprintf("output is not valid %s: %s", ve.MediaType, ve.Err)
+}
+
+// Unwrap returns the underlying error.
+func (ve *ValidationError) Unwrap() error {
+	return ve.Err
+}
+
+var (
+	validatorsLock sync.RWMutex
+	validators     = map[string]Validator{
+		"application/json":   ValidatorFunc(ValidateJSON),
+		"application/yaml":   ValidatorFunc(ValidateYAML),
+		"application/x-yaml": ValidatorFunc(ValidateYAML),
+		"text/yaml":          ValidatorFunc(ValidateYAML),
+		"application/xml":    ValidatorFunc(ValidateXML),
+		"text/xml":           ValidatorFunc(ValidateXML),
+		"text/html":          ValidatorFunc(ValidateHTML),
+	}
+
+	// suffixTypes maps structured syntax suffixes, as in application/ld+json,
+	// onto the media type whose validator applies.
+	suffixTypes = map[string]string{
+		"+json": "application/json",
+		"+yaml": "application/yaml",
+		"+xml":  "application/xml",
+	}
+)
+
+// RegisterValidator associates a Validator with a media type, replacing any
+// existing Validator for that media type, including the built-in ones.  Media
+// type parameters, such as charset, are ignored.  A nil Validator removes
+// validation for the media type.
+func RegisterValidator(mediaType string, v Validator) {
+	mediaType = baseMediaType(mediaType)
+	validatorsLock.Lock()
+	defer validatorsLock.Unlock()
+	if v != nil {
+		validators[mediaType] = v
+	} else {
+		delete(validators, mediaType)
+	}
+}
+
+// baseMediaType strips any parameters from a media type and normalizes its case.
+func baseMediaType(mediaType string) string {
+	if mt, _, err := mime.ParseMediaType(mediaType); err == nil {
+		return mt
+	}
+
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
+
+// ValidatorFor returns the Validator for a media type.  Parameters are ignored, and
+// a media type with a structured syntax suffix, such as application/problem+json,
+// uses the validator for the suffix's type if it has none of its own.
+func ValidatorFor(mediaType string) (v Validator, found bool) {
+	mediaType = baseMediaType(mediaType)
+	validatorsLock.RLock()
+	defer validatorsLock.RUnlock()
+
+	v, found = validators[mediaType]
+	if !found {
+		for suffix, mt := range suffixTypes {
+			if strings.HasSuffix(mediaType, suffix) {
+				v, found = validators[mt]
+				break
+			}
+		}
+	}
+
+	return
+}
+
+// Validate checks output against the Validator for a media type.  If there is no
+// Validator for the media type, this function returns nil.  Otherwise, any failure
+// is returned as a *ValidationError.
+func Validate(mediaType string, output []byte) error {
+	v, found := ValidatorFor(mediaType)
+	if !found {
+		return nil
+	}
+
+	if err := v.Validate(output); err != nil {
+		return &ValidationError{MediaType: baseMediaType(mediaType), Err: err}
+	}
+
+	return nil
+}
+
+// lineColumn converts a byte offset into a 1-based line and column.
+func lineColumn(data []byte, offset int64) (line, column int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+
+	before := data[:offset]
+	line = bytes.Count(before, []byte{'\n'}) + 1
+	column = int(offset) - (bytes.LastIndexByte(before, '\n') + 1)
+	return
+}
+
+// ValidateJSON checks that output is a single, well-formed JSON value.  Syntax
+// errors report the line and column where they occurred.
+func ValidateJSON(output []byte) error {
+	var v interface{}
+	err := json.Unmarshal(output, &v)
+
+	var se *json.SyntaxError
+	if errors.As(err, &se) {
+		line, column := lineColumn(output, se.Offset)
+		return fmt.Errorf("line %d, column %d: %w", line, column, err)
+	}
+
+	return err
+}
+
+// ValidateYAML checks that output is a well-formed stream of YAML documents.
+func ValidateYAML(output []byte) error {
+	d := yaml.NewDecoder(bytes.NewReader(output))
+	for {
+		var v interface{}
+		err := d.Decode(&v)
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+// ValidateXML checks that output is a well-formed XML document with exactly
+// one root element.
+func ValidateXML(output []byte) error {
+	var (
+		d     = xml.NewDecoder(bytes.NewReader(output))
+		depth int
+		roots int
+	)
+
+	for {
+		t, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		switch tt := t.(type) {
+		case xml.StartElement:
+			if depth == 0 {
+				roots++
+			}
+
+			depth++
+
+		case xml.EndElement:
+			depth--
+
+		case xml.CharData:
+			if depth == 0 && len(bytes.TrimSpace(tt)) > 0 {
+				line, _ := d.InputPos()
+				return fmt.Errorf("line %d: text outside of the root element", line)
+			}
+		}
+	}
+
+	if roots != 1 {
+		return fmt.Errorf("expected exactly one root element, but found %d", roots)
+	}
+
+	return nil
+}
+
+type validatingTemplate struct {
+	Template
+}
+
+func (vt validatingTemplate) MediaType() string {
+	return MediaType(vt.Template)
+}
+
+// Execute renders the decorated template into a buffer and validates the result.
+// Output is written only if it is valid.
+func (vt validatingTemplate) Execute(output io.Writer, data interface{}) error {
+	return vt.validate(output, func(buffer io.Writer) error {
+		return vt.Template.Execute(buffer, data)
+	})
+}
+
+// ExecuteWith is like Execute, but passes the given options to the decorated template.
+func (vt validatingTemplate) ExecuteWith(ctx context.Context, output io.Writer, data interface{}, opts ...ExecuteOption) error {
+	return vt.validate(output, func(buffer io.Writer) error {
+		return ExecuteWith(ctx, vt.Template, buffer, data, opts...)
+	})
+}
+
+// validate renders into a buffer, then writes the buffer to output only
+// if it is valid for the decorated template's media type.
+func (vt validatingTemplate) validate(output io.Writer, render func(io.Writer) error) error {
+	var buffer bytes.Buffer
+	err := render(&buffer)
+	if err == nil {
+		err = Validate(MediaType(vt.Template), buffer.Bytes())
+	}
+
+	if err == nil {
+		_, err = buffer.WriteTo(output)
+	}
+
+	return err
+}
+
+// ValidatingTemplate decorates a Template so that each execution validates the rendered
+// output against the Validator for the template's MediaType.  Invalid output produces
+// a *ValidationError, and nothing is written.  The returned Template also implements
+// MediaTyper, reporting the media type of t.
+func ValidatingTemplate(t Template) Template {
+	return validatingTemplate{Template: t}
+}
@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package thoth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidators(t *testing.T) {
+	testCases := []struct {
+		name      string
+		validator func([]byte) error
+		output    string
+		err       string
+	}{
+		{name: "JSONObject", validator: ValidateJSON, output: `{"a": [1, 2]}`},
+		{name: "JSONScalar", validator: ValidateJSON, output: `"text"`},
+		{name: "JSONEmpty", validator: ValidateJSON, output: "", err: "unexpected end of JSON input"},
+		{name: "JSONSyntax", validator: ValidateJSON, output: "{\n  \"a\": 1,\n}", err: "line 3, column 1"},
+		{name: "JSONTrailing", validator: ValidateJSON, output: `{} {}`, err: "line 1, column 4"},
+		{name: "YAMLDocument", validator: ValidateYAML, output: "a: 1\nb: [x, y]\n"},
+		{name: "YAMLStream", validator: ValidateYAML, output: "a: 1\n---\nb: 2\n"},
+		{name: "YAMLEmpty", validator: ValidateYAML, output: ""},
+		{name: "YAMLSyntax", validator: ValidateYAML, output: "a: [1, 2\n", err: "yaml"},
+		{name: "YAMLSecondDocument", validator: ValidateYAML, output: "a: 1\n---\nb: : c\n", err: "yaml"},
+		{name: "XMLDocument", validator: ValidateXML, output: "<?xml version=\"1.0\"?>\n<a><b x=\"1\"/>text</a>\n"},
+		{name: "XMLNoRoot", validator: ValidateXML, output: "", err: "expected exactly one root element, but found 0"},
+		{name: "XMLTwoRoots", validator: ValidateXML, output: "<a/><b/>", err: "expected exactly one root element, but found 2"},
+		{name: "XMLTextOutside", validator: ValidateXML, output: "<a/>\ntext", err: "line 2: text outside of the root element"},
+		{name: "XMLMismatched", validator: ValidateXML, output: "<a><b></a>", err: "element <b> closed by </a>"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := testCase.validator([]byte(testCase.output))
+			switch {
+			case len(testCase.err) == 0 && err != nil:
+				t.Errorf("unexpected error: %s", err)
+
+			case len(testCase.err) > 0 && (err == nil || !strings.Contains(err.Error(), testCase.err)):
+				t.Errorf("expected an error containing %q, got %v", testCase.err, err)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		mediaType string
+		output    string
+		invalid   bool
+	}{
+		{mediaType: "application/json", output: `{}`},
+		{mediaType: "application/json", output: `{`, invalid: true},
+		{mediaType: "Application/JSON; charset=utf-8", output: `{`, invalid: true},
+		{mediaType: "application/problem+json", output: `{`, invalid: true},
+		{mediaType: "application/vnd.api+yaml", output: `a: [`, invalid: true},
+		{mediaType: "application/atom+xml", output: `<a>`, invalid: true},
+		{mediaType: "text/yaml", output: `a: [`, invalid: true},
+		{mediaType: "text/xml", output: `<a/>`},
+		{mediaType: "text/html", output: `<div>`, invalid: true},
+		{mediaType: "text/plain", output: `{`},
+		{mediaType: "not a media type", output: `{`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.mediaType, func(t *testing.T) {
+			err := Validate(testCase.mediaType, []byte(testCase.output))
+			if !testCase.invalid {
+				if err != nil {
+					t.Errorf("unexpected error: %s", err)
+				}
+
+				return
+			}
+
+			var ve *ValidationError
+			if !errors.As(err, &ve) || ve.Unwrap() == nil || strings.ContainsAny(ve.MediaType, "; ") {
+				t.Errorf("expected a ValidationError, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRegisterValidator(t *testing.T) {
+	const mediaType = "application/vnd.thoth.test"
+	defer RegisterValidator(mediaType, nil)
+
+	if _, found := ValidatorFor(mediaType); found {
+		t.Fatal("the test media type should have no validator")
+	}
+
+	failure := errors.New("always invalid")
+	RegisterValidator(mediaType+"; charset=utf-8", ValidatorFunc(func([]byte) error { return failure }))
+	if err := Validate(mediaType, nil); !errors.Is(err, failure) {
+		t.Errorf("expected the registered validator to be used, got %v", err)
+	}
+
+	RegisterValidator(mediaType, nil)
+	if err := Validate(mediaType, nil); err != nil {
+		t.Errorf("expected the validator to be removed, got %v", err)
+	}
+
+	// built in validators can be replaced, too
+	defer RegisterValidator("text/html", ValidatorFunc(ValidateHTML))
+	RegisterValidator("text/html", nil)
+	if err := Validate("text/html", []byte("<div>")); err != nil {
+		t.Errorf("expected the built in validator to be removed, got %v", err)
+	}
+}
+
+func TestValidatingTemplate(t *testing.T) {
+	p, err := NewParser(ParserConfig{})
+	if err != nil {
+		t.Fatalf("unable to create parser: %s", err)
+	}
+
+	base, err := p.Parse("t", `{{.}}`)
+	if err != nil {
+		t.Fatalf("unable to parse template: %s", err)
+	}
+
+	vt := ValidatingTemplate(base)
+	if MediaType(vt) != DefaultMediaType {
+		t.Errorf("unexpected media type %s", MediaType(vt))
+	}
+
+	testCases := []struct {
+		name    string
+		data    string
+		invalid bool
+	}{
+		{name: "Valid", data: `{"a": 1}`},
+		{name: "Invalid", data: `{"a": `, invalid: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			executions := map[string]func(*bytes.Buffer) error{
+				"Execute": func(o *bytes.Buffer) error {
+					return vt.Execute(o, testCase.data)
+				},
+				"ExecuteWith": func(o *bytes.Buffer) error {
+					return ExecuteWith(context.Background(), vt, o, testCase.data)
+				},
+			}
+
+			for name, execute := range executions {
+				var o bytes.Buffer
+				err := execute(&o)
+				if testCase.invalid {
+					var ve *ValidationError
+					if !errors.As(err, &ve) || o.Len() > 0 {
+						t.Errorf("%s: expected a ValidationError and no output, got %q, %v", name, o.String(), err)
+					}
+				} else if err != nil || o.String() != testCase.data {
+					t.Errorf("%s: expected %q, got %q (%v)", name, testCase.data, o.String(), err)
+				}
+			}
+		})
+	}
+}